@@ -0,0 +1,152 @@
+package excelsnapshot
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// BatchOptions 批量渲染的配置
+type BatchOptions struct {
+	// 并发的工作协程数，小于等于 0 时为 runtime.GOMAXPROCS(0)
+	// 已开始处理但尚未交付的工作表不超过该数量，以限制编码结果占用的内存峰值
+	// 批量渲染加载的工作表不放入 Excel 的缓存，交付后即可释放，内存占用与 Workers 相关而与工作表数量无关
+	Workers int
+	// 要渲染的工作表名称，结果按该顺序交付；为空时为全部工作表（按工作簿顺序）
+	Sheets []string
+	// 输出图片格式（为空时为 PNG）与编码参数
+	Format ImageFormat
+	Encode *EncodeOptions
+	// 每个工作表渲染时的选项，只作用于本次批量渲染
+	Render []RendererOption
+	// 额外的渲染变体（如缩略图使用 WithMaxSize）：每个工作表只加载一次，
+	// 在 Render 的基础上叠加各变体的选项再渲染并编码，结果按顺序放在 SheetResult.Variants 中
+	Variants [][]RendererOption
+}
+
+// SheetResult 批量处理中单个工作表的结果
+type SheetResult struct {
+	// 在 BatchOptions.Sheets（或工作簿）中的序号
+	Index int
+	Name  string
+	// 加载后的工作表，加载失败时为 nil
+	Sheet *Sheet
+	// 编码后的图片（仅 RenderSheets）
+	Data []byte
+	// 各渲染变体编码后的图片，与 BatchOptions.Variants 一一对应
+	Variants [][]byte
+	// 加载、渲染或编码失败的错误
+	Err error
+}
+
+// RenderSheets 以多个工作协程并发加载、渲染并编码工作表，在调用方的 goroutine 中按顺序对每个结果调用 fn
+// 单个工作表失败时其 SheetResult.Err 非空，是否中止由 fn 决定；fn 返回错误时不再开始新的工作表，
+// 等待进行中的工作表完成后返回该错误。fn 返回后不应继续持有 SheetResult.Data 以外的引用以便及时释放内存
+// 已缓存的工作表直接使用；未缓存的工作表单独加载且不放入缓存，之后的 GetSheet 会重新加载
+func (sr *SheetRenderer) RenderSheets(excel *Excel, opts BatchOptions, fn func(*SheetResult) error) error {
+	return sr.RenderSheetsContext(context.Background(), excel, opts, fn)
+}
+
+// RenderSheetsContext 同 RenderSheets，ctx 取消后尚未完成的工作表以 ctx 的错误交付
+func (sr *SheetRenderer) RenderSheetsContext(ctx context.Context, excel *Excel, opts BatchOptions, fn func(*SheetResult) error) error {
+	if excel == nil || excel.file == nil {
+		return fmt.Errorf("Excel 文件未打开")
+	}
+	names := opts.Sheets
+	if len(names) == 0 {
+		names = excel.file.GetSheetList()
+	}
+	return runSheets(names, opts.Workers, func(res *SheetResult) {
+		if res.Sheet, res.Err = excel.sheetUncached(ctx, res.Name); res.Err != nil {
+			return
+		}
+		if res.Data, res.Err = sr.renderEncoded(ctx, res.Sheet, opts, opts.Render); res.Err != nil {
+			return
+		}
+		for _, variant := range opts.Variants {
+			render := append(append([]RendererOption(nil), opts.Render...), variant...)
+			data, err := sr.renderEncoded(ctx, res.Sheet, opts, render)
+			if err != nil {
+				res.Err = err
+				return
+			}
+			res.Variants = append(res.Variants, data)
+		}
+	}, fn)
+}
+
+// renderEncoded 以 render 为单次选项渲染工作表，并按 opts 的格式编码
+func (sr *SheetRenderer) renderEncoded(ctx context.Context, sheet *Sheet, opts BatchOptions, render []RendererOption) ([]byte, error) {
+	img, err := sr.RenderSheetContext(ctx, sheet, render...)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := EncodeImage(&buf, img, opts.Format, opts.Encode); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// runSheets 以 workers 个工作协程对 names 中的每个工作表调用 work，并按 names 的顺序在调用方的 goroutine 中交付结果；
+// 已开始但尚未交付的工作表不超过 workers 个，deliver 返回错误时停止分派并等待进行中的工作表完成
+func runSheets(names []string, workers int, work func(*SheetResult), deliver func(*SheetResult) error) error {
+	if len(names) == 0 {
+		return nil
+	}
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, len(names))
+
+	results := make([]chan *SheetResult, len(names))
+	for i := range results {
+		results[i] = make(chan *SheetResult, 1)
+	}
+	jobs := make(chan int)
+	// 每个工作表在开始前占用一个名额，交付后释放
+	slots := make(chan struct{}, workers)
+	stop := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				res := &SheetResult{Index: i, Name: names[i]}
+				work(res)
+				results[i] <- res
+			}
+		}()
+	}
+	go func() {
+		defer close(jobs)
+		for i := range names {
+			select {
+			case slots <- struct{}{}:
+			case <-stop:
+				return
+			}
+			select {
+			case jobs <- i:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	var err error
+	for i := range names {
+		err = deliver(<-results[i])
+		<-slots
+		if err != nil {
+			break
+		}
+	}
+	close(stop)
+	wg.Wait()
+	return err
+}
@@ -0,0 +1,240 @@
+package excelsnapshot
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image/png"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap/zaptest"
+)
+
+// createTestBatchExcel 创建包含 n 个工作表的测试工作簿（Sheet1..Sheetn）
+func createTestBatchExcel(t *testing.T, n int) *Excel {
+	t.Helper()
+	f := excelize.NewFile()
+	for i := 1; i <= n; i++ {
+		name := fmt.Sprintf("Sheet%d", i)
+		if i > 1 {
+			f.NewSheet(name)
+		}
+		if err := createComplexWorksheet(f, name, 5+i, 4); err != nil {
+			t.Fatalf("创建工作表失败: %v", err)
+		}
+	}
+	buf, err := f.WriteToBuffer()
+	f.Close()
+	if err != nil {
+		t.Fatalf("写入工作簿失败: %v", err)
+	}
+	excel, err := NewExcelFromBytes(buf.Bytes(), zaptest.NewLogger(t))
+	if err != nil {
+		t.Fatalf("加载Excel失败: %v", err)
+	}
+	t.Cleanup(func() { excel.Close() })
+	return excel
+}
+
+// TestSheetRenderer_RenderSheets 测试并发批量渲染按工作簿顺序交付，且与逐个渲染的结果一致
+func TestSheetRenderer_RenderSheets(t *testing.T) {
+	excel := createTestBatchExcel(t, 5)
+	renderer := NewSheetRenderer(zaptest.NewLogger(t))
+
+	for _, workers := range []int{0, 1, 3, 10} {
+		t.Run(fmt.Sprintf("workers=%d", workers), func(t *testing.T) {
+			var got []string
+			err := renderer.RenderSheets(excel, BatchOptions{Workers: workers}, func(res *SheetResult) error {
+				if res.Err != nil {
+					t.Fatalf("渲染工作表 %s 失败: %v", res.Name, res.Err)
+				}
+				if res.Index != len(got) {
+					t.Errorf("Index = %d, want %d", res.Index, len(got))
+				}
+				got = append(got, res.Name)
+
+				img, err := png.Decode(bytes.NewReader(res.Data))
+				if err != nil {
+					t.Fatalf("解码 %s 失败: %v", res.Name, err)
+				}
+				var want bytes.Buffer
+				if err := renderer.RenderTo(&want, res.Sheet, FormatPNG, nil); err != nil {
+					t.Fatalf("RenderTo() 失败: %v", err)
+				}
+				wantImg, _ := png.Decode(&want)
+				if !bytes.Equal(toRGBA(img).Pix, toRGBA(wantImg).Pix) {
+					t.Errorf("工作表 %s 的批量渲染结果与逐个渲染不一致", res.Name)
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("RenderSheets() 失败: %v", err)
+			}
+			want := []string{"Sheet1", "Sheet2", "Sheet3", "Sheet4", "Sheet5"}
+			if fmt.Sprint(got) != fmt.Sprint(want) {
+				t.Errorf("交付顺序 = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+// TestSheetRenderer_RenderSheets_Errors 测试单个工作表失败与回调返回错误时的处理
+func TestSheetRenderer_RenderSheets_Errors(t *testing.T) {
+	excel := createTestBatchExcel(t, 4)
+	renderer := NewSheetRenderer(zaptest.NewLogger(t))
+
+	// 不存在的工作表只影响其自身的结果
+	var failed []string
+	opts := BatchOptions{Workers: 2, Sheets: []string{"Sheet2", "不存在的工作表", "Sheet1"}, Format: FormatJPEG}
+	err := renderer.RenderSheets(excel, opts, func(res *SheetResult) error {
+		if res.Err != nil {
+			failed = append(failed, res.Name)
+		} else if len(res.Data) == 0 {
+			t.Errorf("工作表 %s 没有输出", res.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("RenderSheets() 失败: %v", err)
+	}
+	if len(failed) != 1 || failed[0] != "不存在的工作表" {
+		t.Errorf("失败的工作表 = %v", failed)
+	}
+
+	// 回调返回错误时中止并返回该错误
+	stop := errors.New("stop")
+	delivered := 0
+	err = renderer.RenderSheets(excel, BatchOptions{Workers: 2}, func(res *SheetResult) error {
+		delivered++
+		return stop
+	})
+	if !errors.Is(err, stop) {
+		t.Errorf("RenderSheets() error = %v, want %v", err, stop)
+	}
+	if delivered != 1 {
+		t.Errorf("中止后仍交付了 %d 个结果", delivered)
+	}
+
+	if err := renderer.RenderSheets(nil, BatchOptions{}, func(*SheetResult) error { return nil }); err == nil {
+		t.Error("Excel 为空时应该返回错误")
+	}
+}
+
+// TestSheetRenderer_RenderSheets_Cache 测试批量渲染不缓存其加载的工作表，已缓存的工作表直接复用
+func TestSheetRenderer_RenderSheets_Cache(t *testing.T) {
+	excel := createTestBatchExcel(t, 3)
+	renderer := NewSheetRenderer(zaptest.NewLogger(t))
+	cached, err := excel.GetSheet("Sheet2")
+	if err != nil {
+		t.Fatalf("获取工作表失败: %v", err)
+	}
+
+	err = renderer.RenderSheets(excel, BatchOptions{Workers: 2}, func(res *SheetResult) error {
+		if res.Err != nil {
+			t.Fatalf("渲染工作表 %s 失败: %v", res.Name, res.Err)
+		}
+		if res.Name == "Sheet2" && res.Sheet != cached {
+			t.Errorf("已缓存的工作表 Sheet2 应直接复用")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("RenderSheets() 失败: %v", err)
+	}
+	sheets := excel.Sheets()
+	if len(sheets) != 1 || sheets["Sheet2"] != cached {
+		t.Errorf("批量渲染后的缓存 = %v, want 只有 Sheet2", sheets)
+	}
+}
+
+// TestSheetRenderer_RenderSheets_Variants 测试渲染变体与主图在同一个任务中输出
+func TestSheetRenderer_RenderSheets_Variants(t *testing.T) {
+	excel := createTestBatchExcel(t, 3)
+	renderer := NewSheetRenderer(zaptest.NewLogger(t))
+	opts := BatchOptions{
+		Workers:  2,
+		Variants: [][]RendererOption{{WithMaxSize(40, 40)}, {WithBackground(nil)}},
+	}
+	delivered := 0
+	err := renderer.RenderSheets(excel, opts, func(res *SheetResult) error {
+		if res.Err != nil {
+			t.Fatalf("渲染工作表 %s 失败: %v", res.Name, res.Err)
+		}
+		delivered++
+		if len(res.Variants) != 2 {
+			t.Fatalf("工作表 %s 的变体数量 = %d, want 2", res.Name, len(res.Variants))
+		}
+		main, err := png.Decode(bytes.NewReader(res.Data))
+		if err != nil {
+			t.Fatalf("解码 %s 失败: %v", res.Name, err)
+		}
+		thumb, err := png.Decode(bytes.NewReader(res.Variants[0]))
+		if err != nil {
+			t.Fatalf("解码 %s 的缩略图失败: %v", res.Name, err)
+		}
+		if b := thumb.Bounds(); b.Dx() > 40 || b.Dy() > 40 || b.Dx() >= main.Bounds().Dx() {
+			t.Errorf("工作表 %s 的缩略图尺寸 = %v, 主图 %v", res.Name, b.Size(), main.Bounds().Size())
+		}
+		plain, err := png.Decode(bytes.NewReader(res.Variants[1]))
+		if err != nil {
+			t.Fatalf("解码 %s 的透明背景变体失败: %v", res.Name, err)
+		}
+		if plain.Bounds() != main.Bounds() {
+			t.Errorf("工作表 %s 的透明背景变体尺寸 = %v, want %v", res.Name, plain.Bounds(), main.Bounds())
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("RenderSheets() 失败: %v", err)
+	}
+	if delivered != 3 {
+		t.Errorf("交付了 %d 个结果, want 3", delivered)
+	}
+}
+
+// TestRunSheets_Bounded 测试已开始但尚未交付的工作表数量不超过工作协程数
+func TestRunSheets_Bounded(t *testing.T) {
+	names := make([]string, 20)
+	for i := range names {
+		names[i] = fmt.Sprint(i)
+	}
+	const workers = 3
+	var mu sync.Mutex
+	pending, peak := 0, 0
+	err := runSheets(names, workers, func(res *SheetResult) {
+		mu.Lock()
+		pending++
+		peak = max(peak, pending)
+		mu.Unlock()
+		// 让靠前的工作表较晚完成，使后面的结果需要等待交付
+		time.Sleep(time.Duration(len(names)-res.Index) * time.Millisecond)
+	}, func(res *SheetResult) error {
+		if res.Name != names[res.Index] {
+			t.Errorf("结果 %d 的名称为 %s", res.Index, res.Name)
+		}
+		mu.Lock()
+		pending--
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("runSheets() 失败: %v", err)
+	}
+	if peak > workers {
+		t.Errorf("同时存在 %d 个未交付的工作表, want <= %d", peak, workers)
+	}
+}
+
+// TestExcel_LoadAllSheetsParallel 测试并发加载所有工作表
+func TestExcel_LoadAllSheetsParallel(t *testing.T) {
+	excel := createTestBatchExcel(t, 6)
+	if err := excel.LoadAllSheetsParallel(4); err != nil {
+		t.Fatalf("LoadAllSheetsParallel() 失败: %v", err)
+	}
+	if len(excel.Sheets()) != 6 {
+		t.Errorf("已加载的工作表数量 = %d, want 6", len(excel.Sheets()))
+	}
+}
@@ -2,6 +2,7 @@ package excelsnapshot
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -15,14 +16,23 @@ type Cell struct {
 	Col         int    // 1-based
 	Address     string // 如 "A1"
 	Value       string // 解析后的显示值（已由 excelize 处理）
+	Formula     string // 公式文本（不含前导 "="，无公式为空）
 	IsMerged    bool
 	StyleIndex  int
 	MergedRange []string
+
+	renderStyle *excelize.Style // 叠加表格样式后的渲染样式，加载表格时计算（仅表格内的单元格）
 }
 
 // IsEmpty 判断单元格是否为空
 func (c *Cell) IsEmpty() bool { return c == nil || strings.TrimSpace(c.Value) == "" }
 
+// IsError 判断单元格值是否为 Excel 错误值（如 #DIV/0!、#N/A）
+func (c *Cell) IsError() bool { return c != nil && isExcelError(c.Value) }
+
+// HasFormula 判断单元格是否包含公式
+func (c *Cell) HasFormula() bool { return c != nil && c.Formula != "" }
+
 // String 返回单元格的字符串值（空则返回空串）
 func (c *Cell) String() string {
 	if c == nil {
@@ -54,3 +64,90 @@ func (c *Cell) Style() (*excelize.Style, error) {
 	}
 	return c.Sheet.GetStyle(c.StyleIndex)
 }
+
+// RenderStyle 获取渲染用样式：表格样式在下，单元格显式样式覆盖其上
+func (c *Cell) RenderStyle() (*excelize.Style, error) {
+	if c != nil && c.renderStyle != nil {
+		return c.renderStyle, nil
+	}
+	return c.Style()
+}
+
+// cellIndex 按行列索引的稀疏单元格存储：每行保存按列号排序的单元格切片
+// 只保存实际存在的单元格，按行顺序追加时插入开销为 O(1)
+type cellIndex struct {
+	rows  map[int][]*Cell
+	count int
+}
+
+// newCellIndex 创建空的单元格索引
+func newCellIndex() *cellIndex {
+	return &cellIndex{rows: make(map[int][]*Cell)}
+}
+
+// at 按行列号获取单元格，不存在时返回 nil
+func (ci *cellIndex) at(row, col int) *Cell {
+	cells := ci.rows[row]
+	i := sort.Search(len(cells), func(i int) bool { return cells[i].Col >= col })
+	if i < len(cells) && cells[i].Col == col {
+		return cells[i]
+	}
+	return nil
+}
+
+// get 按地址（如 "A1"）获取单元格，不存在或地址无效时返回 nil
+func (ci *cellIndex) get(addr string) *Cell {
+	col, row, err := excelize.CellNameToCoordinates(addr)
+	if err != nil {
+		return nil
+	}
+	return ci.at(row, col)
+}
+
+// put 保存单元格（同一位置已存在时替换）
+func (ci *cellIndex) put(cell *Cell) {
+	cells := ci.rows[cell.Row]
+	n := len(cells)
+	if n == 0 || cells[n-1].Col < cell.Col {
+		ci.rows[cell.Row] = append(cells, cell)
+		ci.count++
+		return
+	}
+	i := sort.Search(n, func(i int) bool { return cells[i].Col >= cell.Col })
+	if i < n && cells[i].Col == cell.Col {
+		cells[i] = cell
+		return
+	}
+	cells = append(cells, nil)
+	copy(cells[i+1:], cells[i:])
+	cells[i] = cell
+	ci.rows[cell.Row] = cells
+	ci.count++
+}
+
+// row 返回指定行的单元格（按列号排序）
+func (ci *cellIndex) row(row int) []*Cell {
+	return ci.rows[row]
+}
+
+// len 返回单元格数量
+func (ci *cellIndex) len() int {
+	return ci.count
+}
+
+// each 按行列顺序遍历所有单元格，fn 返回错误时停止遍历并返回该错误
+func (ci *cellIndex) each(fn func(cell *Cell) error) error {
+	rows := make([]int, 0, len(ci.rows))
+	for r := range ci.rows {
+		rows = append(rows, r)
+	}
+	sort.Ints(rows)
+	for _, r := range rows {
+		for _, cell := range ci.rows[r] {
+			if err := fn(cell); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -293,7 +293,7 @@ func TestCell_WithRealExcelData(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run("Cell_"+tt.addr, func(t *testing.T) {
-			cell := sheet.cells[tt.addr]
+			cell := sheet.cells.get(tt.addr)
 			
 			if tt.addr == "A6" && cell != nil {
 				t.Error("不存在的单元格应该返回nil")
@@ -355,7 +355,7 @@ func TestCell_MergedCells(t *testing.T) {
 	}
 
 	// 测试主单元格
-	cellA1 := sheet.cells["A1"]
+	cellA1 := sheet.cells.get("A1")
 	if cellA1 == nil {
 		t.Fatal("合并单元格主单元格不应该为nil")
 	}
@@ -394,3 +394,47 @@ func BenchmarkCell_GetNumericValue(b *testing.B) {
 		_, _ = cell.Float64()
 	}
 }
+
+// TestCellIndex 测试按行列索引的单元格存储
+func TestCellIndex(t *testing.T) {
+	idx := newCellIndex()
+	for _, addr := range []string{"C2", "A2", "B1", "B2", "A2"} {
+		col, row, _ := excelize.CellNameToCoordinates(addr)
+		idx.put(&Cell{Address: addr, Row: row, Col: col, Value: addr})
+	}
+
+	if idx.len() != 4 {
+		t.Errorf("len() = %d, want 4", idx.len())
+	}
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"A2", true},
+		{"B2", true},
+		{"C2", true},
+		{"B1", true},
+		{"A1", false},
+		{"D2", false},
+	}
+	for _, tt := range tests {
+		if got := idx.get(tt.addr) != nil; got != tt.want {
+			t.Errorf("get(%s) 存在性 = %v, want %v", tt.addr, got, tt.want)
+		}
+	}
+
+	var order []string
+	idx.each(func(cell *Cell) error {
+		order = append(order, cell.Address)
+		return nil
+	})
+	want := []string{"B1", "A2", "B2", "C2"}
+	if len(order) != len(want) {
+		t.Fatalf("each() 顺序 = %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("each() 顺序 = %v, want %v", order, want)
+		}
+	}
+}
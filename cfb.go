@@ -0,0 +1,193 @@
+package excelsnapshot
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+)
+
+// 复合文档（[MS-CFB]）中的特殊扇区编号
+const (
+	cfbEndOfChain = 0xFFFFFFFE
+	cfbFreeSector = 0xFFFFFFFF
+	// 目录项类型：流
+	cfbStreamObject = 2
+	// 头部中 DIFAT 的条目数
+	cfbHeaderDIFAT = 109
+)
+
+// errCFBCorrupt 复合文档结构损坏
+var errCFBCorrupt = errors.New("复合文档结构损坏")
+
+// cfbEntry 复合文档的目录项
+type cfbEntry struct {
+	name  string
+	typ   byte
+	start uint32
+	size  uint64
+}
+
+// cfbFile 内存中的 OLE 复合文档（.xls 等旧版 Office 文件的容器），只支持读取流
+type cfbFile struct {
+	data          []byte
+	sectorSize    int
+	miniCutoff    uint64
+	fat, miniFAT  []uint32
+	entries       []cfbEntry
+	miniStream    []byte
+	miniStreamErr error
+}
+
+// openCFB 解析复合文档的头部、FAT 与目录
+func openCFB(data []byte) (*cfbFile, error) {
+	if len(data) < 512 || !bytes.HasPrefix(data, oleSignature) {
+		return nil, fmt.Errorf("不是 OLE 复合文档")
+	}
+	le := binary.LittleEndian
+	shift := le.Uint16(data[0x1E:])
+	if shift != 9 && shift != 12 {
+		return nil, errCFBCorrupt
+	}
+	c := &cfbFile{
+		data:       data,
+		sectorSize: 1 << shift,
+		miniCutoff: uint64(le.Uint32(data[0x38:])),
+	}
+
+	// DIFAT：头部中的 109 个条目，之后为 DIFAT 扇区链（每个扇区最后一个条目指向下一个扇区）
+	numFAT := int(le.Uint32(data[0x2C:]))
+	var difat []uint32
+	for i := 0; i < cfbHeaderDIFAT && len(difat) < numFAT; i++ {
+		difat = append(difat, le.Uint32(data[0x4C+4*i:]))
+	}
+	perSector := c.sectorSize/4 - 1
+	for sector, n := le.Uint32(data[0x44:]), 0; len(difat) < numFAT && sector < cfbEndOfChain-3; n++ {
+		buf, err := c.sector(sector)
+		if err != nil || n > numFAT {
+			return nil, errCFBCorrupt
+		}
+		for i := 0; i < perSector && len(difat) < numFAT; i++ {
+			difat = append(difat, le.Uint32(buf[4*i:]))
+		}
+		sector = le.Uint32(buf[4*perSector:])
+	}
+	for _, sector := range difat {
+		buf, err := c.sector(sector)
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < len(buf); i += 4 {
+			c.fat = append(c.fat, le.Uint32(buf[i:]))
+		}
+	}
+
+	dir, err := c.chain(le.Uint32(data[0x30:]), 0)
+	if err != nil {
+		return nil, err
+	}
+	for off := 0; off+128 <= len(dir); off += 128 {
+		e := dir[off : off+128]
+		nameLen := int(le.Uint16(e[64:]))
+		if nameLen < 2 || nameLen > 64 {
+			c.entries = append(c.entries, cfbEntry{})
+			continue
+		}
+		u := make([]uint16, nameLen/2-1)
+		for i := range u {
+			u[i] = le.Uint16(e[2*i:])
+		}
+		size := le.Uint64(e[120:])
+		if c.sectorSize == 512 {
+			size &= 0xFFFFFFFF // 版本 3 只使用低 32 位
+		}
+		c.entries = append(c.entries, cfbEntry{
+			name:  string(utf16.Decode(u)),
+			typ:   e[66],
+			start: le.Uint32(e[116:]),
+			size:  size,
+		})
+	}
+	if len(c.entries) == 0 {
+		return nil, errCFBCorrupt
+	}
+
+	if miniFAT, err := c.chain(le.Uint32(data[0x3C:]), 0); err == nil {
+		for i := 0; i+4 <= len(miniFAT); i += 4 {
+			c.miniFAT = append(c.miniFAT, le.Uint32(miniFAT[i:]))
+		}
+	}
+	// 根目录项的起始扇区与大小描述迷你流
+	root := c.entries[0]
+	c.miniStream, c.miniStreamErr = c.chain(root.start, root.size)
+	return c, nil
+}
+
+// sector 返回扇区内容（扇区 0 紧跟在头部之后）
+func (c *cfbFile) sector(n uint32) ([]byte, error) {
+	off := (int64(n) + 1) * int64(c.sectorSize)
+	if n >= cfbEndOfChain-3 || off+int64(c.sectorSize) > int64(len(c.data)) {
+		return nil, errCFBCorrupt
+	}
+	return c.data[off : off+int64(c.sectorSize)], nil
+}
+
+// chain 沿 FAT 读取扇区链，size 大于 0 时截断为该长度
+func (c *cfbFile) chain(start uint32, size uint64) ([]byte, error) {
+	var out []byte
+	for n := start; n != cfbEndOfChain && n != cfbFreeSector; {
+		if len(out) > len(c.data) || int(n) >= len(c.fat) {
+			return nil, errCFBCorrupt
+		}
+		buf, err := c.sector(n)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, buf...)
+		n = c.fat[n]
+	}
+	if size > 0 {
+		if uint64(len(out)) < size {
+			return nil, errCFBCorrupt
+		}
+		out = out[:size]
+	}
+	return out, nil
+}
+
+// miniChain 沿迷你 FAT 从迷你流中读取 64 字节扇区链
+func (c *cfbFile) miniChain(start uint32, size uint64) ([]byte, error) {
+	if c.miniStreamErr != nil {
+		return nil, c.miniStreamErr
+	}
+	const miniSectorSize = 64
+	var out []byte
+	for n := start; n != cfbEndOfChain && uint64(len(out)) < size; {
+		off := int(n) * miniSectorSize
+		if int(n) >= len(c.miniFAT) || off+miniSectorSize > len(c.miniStream) {
+			return nil, errCFBCorrupt
+		}
+		out = append(out, c.miniStream[off:off+miniSectorSize]...)
+		n = c.miniFAT[n]
+	}
+	if uint64(len(out)) < size {
+		return nil, errCFBCorrupt
+	}
+	return out[:size], nil
+}
+
+// stream 按名称（不区分大小写）读取流的内容
+func (c *cfbFile) stream(name string) ([]byte, error) {
+	for _, e := range c.entries {
+		if e.typ != cfbStreamObject || !strings.EqualFold(e.name, name) {
+			continue
+		}
+		if e.size < c.miniCutoff {
+			return c.miniChain(e.start, e.size)
+		}
+		return c.chain(e.start, e.size)
+	}
+	return nil, fmt.Errorf("复合文档中没有 %s 流", name)
+}
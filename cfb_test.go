@@ -0,0 +1,174 @@
+package excelsnapshot
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"unicode/utf16"
+)
+
+// createTestCFB 构建只包含一个流的复合文档（版本 3，512 字节扇区）；
+// 小于 4096 字节的流存放在迷你流中
+func createTestCFB(t *testing.T, name string, stream []byte) []byte {
+	t.Helper()
+	const sectorSize, miniCutoff = 512, 4096
+	le := binary.LittleEndian
+	sectors := func(n int) int { return (n + sectorSize - 1) / sectorSize }
+
+	// 扇区布局：0 FAT，1 目录，2 迷你 FAT，之后为流（或根目录项的迷你流）
+	mini := len(stream) < miniCutoff
+	body := stream
+	var miniFAT []byte
+	if mini {
+		n := (len(stream) + 63) / 64
+		body = append(append([]byte(nil), stream...), make([]byte, n*64-len(stream))...)
+		miniFAT = make([]byte, sectorSize)
+		for i := range sectorSize / 4 {
+			next := uint32(cfbFreeSector)
+			if i < n-1 {
+				next = uint32(i + 1)
+			} else if i == n-1 {
+				next = cfbEndOfChain
+			}
+			le.PutUint32(miniFAT[4*i:], next)
+		}
+	}
+	bodySectors := sectors(len(body))
+	if 3+bodySectors > sectorSize/4 {
+		t.Fatalf("测试流过大: %d 字节", len(stream))
+	}
+	fat := make([]byte, sectorSize)
+	for i := range sectorSize / 4 {
+		next := uint32(cfbFreeSector)
+		switch {
+		case i == 0:
+			next = 0xFFFFFFFD // FAT 扇区
+		case i == 1 || i == 2:
+			next = cfbEndOfChain
+		case i < 3+bodySectors-1:
+			next = uint32(i + 1)
+		case i == 3+bodySectors-1:
+			next = cfbEndOfChain
+		}
+		le.PutUint32(fat[4*i:], next)
+	}
+
+	entry := func(name string, typ byte, start uint32, size int) []byte {
+		e := make([]byte, 128)
+		u := utf16.Encode([]rune(name))
+		for i, c := range u {
+			le.PutUint16(e[2*i:], c)
+		}
+		le.PutUint16(e[64:], uint16(2*len(u)+2))
+		e[66] = typ
+		le.PutUint32(e[68:], cfbFreeSector) // 左右兄弟与子节点
+		le.PutUint32(e[72:], cfbFreeSector)
+		le.PutUint32(e[76:], cfbFreeSector)
+		le.PutUint32(e[116:], start)
+		le.PutUint64(e[120:], uint64(size))
+		return e
+	}
+	var dir bytes.Buffer
+	if mini {
+		dir.Write(entry("Root Entry", 5, 3, len(body)))
+		dir.Write(entry(name, cfbStreamObject, 0, len(stream)))
+	} else {
+		dir.Write(entry("Root Entry", 5, cfbEndOfChain, 0))
+		dir.Write(entry(name, cfbStreamObject, 3, len(stream)))
+	}
+	le.PutUint32(dir.Bytes()[76:], 1) // 根目录项的子节点
+	dir.Write(make([]byte, 256))
+
+	header := make([]byte, sectorSize)
+	copy(header, oleSignature)
+	le.PutUint16(header[0x18:], 0x3E)
+	le.PutUint16(header[0x1A:], 3)
+	le.PutUint16(header[0x1C:], 0xFFFE)
+	le.PutUint16(header[0x1E:], 9)
+	le.PutUint16(header[0x20:], 6)
+	le.PutUint32(header[0x2C:], 1)
+	le.PutUint32(header[0x30:], 1)
+	le.PutUint32(header[0x38:], miniCutoff)
+	le.PutUint32(header[0x3C:], cfbEndOfChain)
+	if mini {
+		le.PutUint32(header[0x3C:], 2)
+		le.PutUint32(header[0x40:], 1)
+	}
+	le.PutUint32(header[0x44:], cfbEndOfChain)
+	for i := range cfbHeaderDIFAT {
+		le.PutUint32(header[0x4C+4*i:], cfbFreeSector)
+	}
+	le.PutUint32(header[0x4C:], 0)
+
+	var buf bytes.Buffer
+	buf.Write(header)
+	buf.Write(fat)
+	buf.Write(dir.Bytes())
+	buf.Write(append(miniFAT, make([]byte, sectorSize-len(miniFAT))...))
+	buf.Write(body)
+	buf.Write(make([]byte, bodySectors*sectorSize-len(body)))
+	return buf.Bytes()
+}
+
+// TestOpenCFB 测试从复合文档的普通扇区与迷你流中读取流
+func TestOpenCFB(t *testing.T) {
+	tests := []struct {
+		name string
+		size int
+	}{
+		{"迷你流", 100},
+		{"普通扇区", 5000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := make([]byte, tt.size)
+			for i := range want {
+				want[i] = byte(i % 251)
+			}
+			c, err := openCFB(createTestCFB(t, "Workbook", want))
+			if err != nil {
+				t.Fatalf("openCFB() 失败: %v", err)
+			}
+			got, err := c.stream("workbook")
+			if err != nil {
+				t.Fatalf("读取流失败: %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("流内容不一致: 长度 %d, want %d", len(got), len(want))
+			}
+			if _, err := c.stream("Book"); err == nil {
+				t.Error("不存在的流应该返回错误")
+			}
+		})
+	}
+}
+
+// TestOpenCFB_Invalid 测试无效或损坏的复合文档
+func TestOpenCFB_Invalid(t *testing.T) {
+	valid := createTestCFB(t, "Workbook", make([]byte, 5000))
+	badShift := append([]byte(nil), valid...)
+	binary.LittleEndian.PutUint16(badShift[0x1E:], 7)
+	truncated := valid[:len(valid)-1024]
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"不是复合文档", []byte("plain text")},
+		{"扇区大小无效", badShift},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := openCFB(tt.data); err == nil {
+				t.Error("应该返回错误")
+			}
+		})
+	}
+	c, err := openCFB(truncated)
+	if err == nil {
+		_, err = c.stream("Workbook")
+	}
+	if err == nil {
+		t.Error("截断的复合文档应该返回错误")
+	}
+}
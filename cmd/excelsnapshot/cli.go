@@ -1,13 +1,22 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"image"
-	"image/png"
+	"image/color"
+	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"regexp"
 	"runtime/debug"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,6 +25,12 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
+// stdioPath 表示标准输入/输出的路径参数
+const stdioPath = "-"
+
+// passwordEnv 提供打开密码的环境变量
+const passwordEnv = "EXCELSNAPSHOT_PASSWORD"
+
 // CLI参数结构
 type CLIArgs struct {
 	inPath  string
@@ -23,27 +38,223 @@ type CLIArgs struct {
 	sheet   string
 	index   int
 	all     bool
+	jobs    int
 	verbose bool
+
+	// -all 时的工作表筛选
+	includeHidden bool
+	match         string
+	matchRegex    string
+
+	filterButtons bool
+	dropdowns     bool
+	calc          bool
+	showFormulas  bool
+	errorMarks    bool
+	background    string
+	noGridlines   bool
+	gridlineColor string
+	fontDirs      string
+	fallbackFonts string
+	scale         float64
+	dpi           float64
+	maxWidth      int
+	maxHeight     int
+	thumb         int
+	tiles         int
+	stream        bool
+	cellRange     string
+	format        string
+	quality       int
+	password      string
+	passwordFD    int
+	csvDelimiter  string
+	csvEncoding   string
+	csvHeader     string
+
+	// 资源限制，0 表示不限制
+	maxCells      int
+	maxPixels     int64
+	maxImageBytes int64
+	timeout       time.Duration
+
+	// 由 -format 或输出文件扩展名确定的图片格式
+	imageFormat excelsnapshot.ImageFormat
+	// 由 -include-hidden、-match、-match-regex 确定的工作表筛选条件
+	selector excelsnapshot.SheetSelector
+	// 由 -background、-gridline-color 解析的颜色（为空时使用默认值，背景透明时 transparent 为 true）
+	backgroundColor color.Color
+	transparent     bool
+	gridColor       color.Color
+}
+
+// limits 由 -max-cells、-max-pixels、-max-image-bytes、-timeout 确定的资源限制
+func (args *CLIArgs) limits() excelsnapshot.Limits {
+	return excelsnapshot.Limits{
+		MaxCells:      args.maxCells,
+		MaxPixels:     args.maxPixels,
+		MaxImageBytes: args.maxImageBytes,
+		MaxDuration:   args.timeout,
+	}
+}
+
+// parseColors 解析 -background 与 -gridline-color
+func parseColors(args *CLIArgs) error {
+	switch strings.ToLower(args.background) {
+	case "":
+	case "transparent", "none":
+		args.transparent = true
+	default:
+		c, err := excelsnapshot.HexToRGBA(args.background)
+		if err != nil {
+			return fmt.Errorf("无效的 -background: %w", err)
+		}
+		args.backgroundColor = c
+	}
+	if args.gridlineColor != "" {
+		c, err := excelsnapshot.HexToRGBA(args.gridlineColor)
+		if err != nil {
+			return fmt.Errorf("无效的 -gridline-color: %w", err)
+		}
+		args.gridColor = c
+	}
+	return nil
+}
+
+// limitHint 超出资源限制时提示对应的参数
+func limitHint(err error) string {
+	var le *excelsnapshot.LimitError
+	if !errors.As(err, &le) {
+		return ""
+	}
+	flags := map[excelsnapshot.LimitKind]string{
+		excelsnapshot.LimitCells:      "-max-cells",
+		excelsnapshot.LimitPixels:     "-max-pixels",
+		excelsnapshot.LimitImageBytes: "-max-image-bytes",
+		excelsnapshot.LimitDuration:   "-timeout",
+	}
+	return fmt.Sprintf("（可通过 %s 调整限制）", flags[le.Kind])
 }
 
 // 解析命令行参数
 func parseArgs() *CLIArgs {
 	args := &CLIArgs{}
 
-	flag.StringVar(&args.inPath, "i", "", "输入的 Excel 文件路径 (.xlsx)")
-	flag.StringVar(&args.outPath, "o", ".", "输出目录或文件路径（当渲染单个 sheet 时可指定 .png 文件）")
+	flag.StringVar(&args.inPath, "i", "", "输入的工作簿路径 (.xlsx/.xlsm/.xltx/.xltm、.xls、.ods 按内容识别，.csv/.tsv)，- 表示从标准输入读取")
+	flag.StringVar(&args.outPath, "o", ".", "输出目录或文件路径（当渲染单个 sheet 时可指定 .png/.jpg 等文件），- 表示写入标准输出")
 	flag.StringVar(&args.sheet, "sheet", "", "要渲染的工作表名称（优先于 index）")
 	flag.IntVar(&args.index, "index", -1, "要渲染的工作表索引（0-based）")
 	flag.BoolVar(&args.all, "all", false, "是否渲染所有工作表")
+	flag.BoolVar(&args.includeHidden, "include-hidden", false, "-all 时包含隐藏与深度隐藏的工作表（默认跳过）")
+	flag.StringVar(&args.match, "match", "", "只渲染名称匹配通配符的工作表（多个以逗号分隔，如 2024-*），隐含 -all")
+	flag.StringVar(&args.matchRegex, "match-regex", "", "只渲染名称匹配正则表达式的工作表，隐含 -all；与 -match 任一匹配即渲染")
+	flag.IntVar(&args.jobs, "j", 1, "-all 时并发加载、渲染与编码的工作协程数，0 表示 CPU 核数")
 	flag.BoolVar(&args.verbose, "v", false, "启用调试日志（开发模式）")
+	flag.BoolVar(&args.filterButtons, "filter-buttons", false, "在自动筛选区域与表格标题行绘制筛选按钮")
+	flag.BoolVar(&args.dropdowns, "dropdowns", false, "为下拉列表数据验证单元格绘制下拉箭头")
+	flag.BoolVar(&args.calc, "calc", false, "对没有缓存结果的公式单元格计算其值")
+	flag.BoolVar(&args.showFormulas, "formulas", false, "显示公式而非计算结果（类似 Excel 的 Ctrl+`）")
+	flag.BoolVar(&args.errorMarks, "error-marks", false, "在结果为错误值的公式单元格左上角绘制绿色提示三角")
+	flag.StringVar(&args.background, "background", "", "画布背景色 RRGGBB（如 F5F5F5），transparent 表示透明（JPEG 输出时为白色），默认为白色")
+	flag.BoolVar(&args.noGridlines, "no-gridlines", false, "不绘制默认网格线（单元格设置的边框仍会绘制）")
+	flag.StringVar(&args.gridlineColor, "gridline-color", "", "默认网格线颜色 RRGGBB，默认为浅灰色")
+	flag.StringVar(&args.fontDirs, "font-dir", "", "额外字体目录（多个以逗号分隔），按单元格字体族名匹配 TTF/OTF/TTC 字体")
+	flag.StringVar(&args.fallbackFonts, "fallback-fonts", "", "后备字体族名（多个以逗号分隔，需位于 -font-dir 中），按顺序补全缺失的字形")
+	flag.Float64Var(&args.scale, "scale", 2, "输出缩放比例（相对 96 DPI），如 1 用于缩略图、3 用于打印")
+	flag.Float64Var(&args.dpi, "dpi", 0, "输出分辨率（优先于 -scale），96 DPI 对应 1 倍")
+	flag.IntVar(&args.maxWidth, "max-width", 0, "输出图片最大宽度（像素，0 表示不限制），超出时自动减小缩放比例")
+	flag.IntVar(&args.maxHeight, "max-height", 0, "输出图片最大高度（像素，0 表示不限制），超出时自动减小缩放比例")
+	flag.IntVar(&args.thumb, "thumb", 0, "同时输出缩略图（宽高不超过该像素值，文件名追加 _thumb），0 表示不输出")
+	flag.IntVar(&args.tiles, "tiles", 0, "按 Deep Zoom 格式输出图块（图块边长，像素），生成 .dzi、.json 索引与 _files 目录，0 表示输出整图")
+	flag.BoolVar(&args.stream, "stream", false, "流式加载工作表：逐行读取且只保留有内容的单元格，适合维度很大的稀疏工作表（不加载图片）")
+	flag.StringVar(&args.cellRange, "range", "", "只渲染指定区域（如 B2:H40），以流式方式加载，不能与 -all 同时使用")
+	flag.StringVar(&args.format, "format", "", "输出图片格式：png、jpg、gif、bmp、tiff（默认按 -o 的扩展名，否则为 png）")
+	flag.IntVar(&args.quality, "quality", 0, "JPEG 质量（1-100），0 表示默认值 90")
+	flag.StringVar(&args.password, "password", "", "加密工作簿的打开密码（会出现在进程列表中，建议改用 -password-fd 或环境变量 "+passwordEnv+"）")
+	flag.IntVar(&args.passwordFD, "password-fd", -1, "从指定文件描述符读取打开密码（读取第一行）")
+	flag.StringVar(&args.csvDelimiter, "csv-delimiter", "", "CSV 分隔符（单个字符或 tab），默认根据首行自动判断，.tsv 文件默认为 tab")
+	flag.StringVar(&args.csvEncoding, "csv-encoding", "", "CSV 文本编码：utf-8、gbk、gb18030，默认自动判断（带 BOM 时按 BOM）")
+	flag.StringVar(&args.csvHeader, "csv-header", "auto", "CSV 首行是否为标题行：auto、yes、no")
+	flag.IntVar(&args.maxCells, "max-cells", 0, "单个工作表加载的最大单元格数，超出时报错，0 表示不限制")
+	flag.Int64Var(&args.maxPixels, "max-pixels", 0, "输出画布的最大像素数（宽×高），超出时报错而非缩小，0 表示不限制")
+	flag.Int64Var(&args.maxImageBytes, "max-image-bytes", 0, "单张嵌入图片的最大字节数（压缩数据与解码后的 RGBA 数据），0 表示不限制")
+	flag.DurationVar(&args.timeout, "timeout", 0, "单个工作表加载与渲染各自的最长耗时（如 30s），0 表示不限制")
 	flag.Parse()
 
+	// 按名称筛选工作表时渲染所有匹配的工作表
+	if args.match != "" || args.matchRegex != "" {
+		args.all = true
+	}
+
 	// 参数验证
 	if args.inPath == "" {
 		fmt.Println("错误: 必须指定输入文件路径 -i")
 		flag.Usage()
 		os.Exit(1)
 	}
+	if args.scale <= 0 || args.dpi < 0 {
+		fmt.Println("错误: -scale 必须为正数，-dpi 不能为负数")
+		flag.Usage()
+		os.Exit(1)
+	}
+	if args.format != "" {
+		format, err := excelsnapshot.ParseImageFormat(args.format)
+		if err != nil {
+			fmt.Println("错误:", err)
+			flag.Usage()
+			os.Exit(1)
+		}
+		args.imageFormat = format
+	} else if format, err := excelsnapshot.FormatFromPath(args.outPath); err == nil {
+		args.imageFormat = format
+	} else {
+		args.imageFormat = excelsnapshot.FormatPNG
+	}
+	if args.outPath == stdioPath && (args.all || args.tiles > 0 || args.thumb > 0) {
+		fmt.Println("错误: -o - 只能输出单个工作表的整图，不能与 -all、-tiles、-thumb 同时使用")
+		flag.Usage()
+		os.Exit(1)
+	}
+	if args.quality < 0 || args.quality > 100 {
+		fmt.Println("错误: -quality 必须在 0-100 之间")
+		flag.Usage()
+		os.Exit(1)
+	}
+	if args.all && args.cellRange != "" {
+		fmt.Println("错误: -range 不能与 -all 同时使用")
+		flag.Usage()
+		os.Exit(1)
+	}
+	args.selector = excelsnapshot.SheetSelector{IncludeHidden: args.includeHidden, Globs: splitList(args.match)}
+	if args.matchRegex != "" {
+		re, err := regexp.Compile(args.matchRegex)
+		if err != nil {
+			fmt.Println("错误: 无效的 -match-regex:", err)
+			flag.Usage()
+			os.Exit(1)
+		}
+		args.selector.Regexp = re
+	}
+	if err := args.selector.Validate(); err != nil {
+		fmt.Println("错误:", err)
+		flag.Usage()
+		os.Exit(1)
+	}
+	if args.maxWidth < 0 || args.maxHeight < 0 || args.thumb < 0 || args.tiles < 0 || args.jobs < 0 {
+		fmt.Println("错误: -max-width、-max-height、-thumb、-tiles、-j 不能为负数")
+		flag.Usage()
+		os.Exit(1)
+	}
+	if err := parseColors(args); err != nil {
+		fmt.Println("错误:", err)
+		flag.Usage()
+		os.Exit(1)
+	}
+	if args.maxCells < 0 || args.maxPixels < 0 || args.maxImageBytes < 0 || args.timeout < 0 {
+		fmt.Println("错误: -max-cells、-max-pixels、-max-image-bytes、-timeout 不能为负数")
+		flag.Usage()
+		os.Exit(1)
+	}
 
 	return args
 }
@@ -63,6 +274,147 @@ func setupLogger(verbose bool) (*zap.Logger, func(), error) {
 	return excelsnapshot.SetupLogger("excel_snapshot", level, isDev)
 }
 
+// openWorkbook 按输入路径打开工作簿：.csv/.tsv 按 CSV 解析，其他文件按内容识别格式（xlsx/xlsm/xltx/xltm、xls、ods）；
+// - 从标准输入读取，内容不是可识别的工作簿但像文本时按 CSV 解析
+func openWorkbook(ctx context.Context, args *CLIArgs, logger *zap.Logger, opts []excelsnapshot.ExcelOption) (*excelsnapshot.Excel, error) {
+	ext := strings.ToLower(filepath.Ext(args.inPath))
+	if ext == ".csv" || ext == ".tsv" {
+		csvOpts, err := csvOptions(args, ext)
+		if err != nil {
+			return nil, err
+		}
+		f, err := os.Open(args.inPath)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return excelsnapshot.NewExcelFromCSVContext(ctx, f, logger, csvOpts, opts...)
+	}
+	if args.inPath != stdioPath {
+		return excelsnapshot.NewExcelContext(ctx, args.inPath, logger, opts...)
+	}
+
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return nil, fmt.Errorf("读取标准输入失败: %w", err)
+	}
+	if excelsnapshot.DetectFormat(data) == excelsnapshot.FormatUnknown && looksLikeText(data) {
+		csvOpts, err := csvOptions(args, "")
+		if err != nil {
+			return nil, err
+		}
+		return excelsnapshot.NewExcelFromCSVContext(ctx, bytes.NewReader(data), logger, csvOpts, opts...)
+	}
+	return excelsnapshot.NewExcelFromBytesContext(ctx, data, logger, opts...)
+}
+
+// looksLikeText 判断内容是否像文本：带 UTF-16 BOM，或开头 8KB 内没有 NUL 字节
+func looksLikeText(data []byte) bool {
+	if bytes.HasPrefix(data, []byte{0xFF, 0xFE}) || bytes.HasPrefix(data, []byte{0xFE, 0xFF}) {
+		return true
+	}
+	return len(data) > 0 && bytes.IndexByte(data[:min(len(data), 8<<10)], 0) < 0
+}
+
+// csvOptions 根据命令行参数构造 CSV 解析参数；工作表以文件名命名
+func csvOptions(args *CLIArgs, ext string) (excelsnapshot.CSVOptions, error) {
+	opts := excelsnapshot.CSVOptions{Encoding: args.csvEncoding}
+	switch args.csvDelimiter {
+	case "":
+		if ext == ".tsv" {
+			opts.Comma = '\t'
+		}
+	case "tab", "\\t":
+		opts.Comma = '\t'
+	default:
+		runes := []rune(args.csvDelimiter)
+		if len(runes) != 1 {
+			return opts, fmt.Errorf("无效的 CSV 分隔符: %q", args.csvDelimiter)
+		}
+		opts.Comma = runes[0]
+	}
+	switch args.csvHeader {
+	case "", "auto":
+		opts.Header = excelsnapshot.CSVHeaderAuto
+	case "yes":
+		opts.Header = excelsnapshot.CSVHeaderPresent
+	case "no":
+		opts.Header = excelsnapshot.CSVHeaderAbsent
+	default:
+		return opts, fmt.Errorf("无效的 -csv-header: %s（可选 auto、yes、no）", args.csvHeader)
+	}
+	name := strings.TrimSuffix(filepath.Base(args.inPath), filepath.Ext(args.inPath))
+	if args.inPath == stdioPath {
+		name = "stdin"
+	}
+	opts.SheetName = csvSheetName(name)
+	return opts, nil
+}
+
+// csvSheetName 将文件名转换为合法的工作表名称（去除 []:*?/\ 并截断为 31 个字符）
+func csvSheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune("[]:*?/\\", r) {
+			return '_'
+		}
+		return r
+	}, strings.Trim(name, "'"))
+	if runes := []rune(name); len(runes) > 31 {
+		name = string(runes[:31])
+	}
+	if name == "" {
+		return "Sheet1"
+	}
+	return name
+}
+
+// resolvePassword 按 -password、-password-fd、环境变量的顺序获取打开密码
+func resolvePassword(args *CLIArgs) (string, error) {
+	if args.password != "" {
+		return args.password, nil
+	}
+	if args.passwordFD >= 0 {
+		f := os.NewFile(uintptr(args.passwordFD), "password-fd")
+		if f == nil {
+			return "", fmt.Errorf("无效的文件描述符: %d", args.passwordFD)
+		}
+		defer f.Close()
+		line, err := bufio.NewReader(f).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", fmt.Errorf("从文件描述符 %d 读取密码失败: %w", args.passwordFD, err)
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+	return os.Getenv(passwordEnv), nil
+}
+
+// 加载额外字体目录，未指定时返回 nil（仅使用内置字体）
+func loadFontRegistry(dirs string, logger *zap.Logger) (*excelsnapshot.FontRegistry, error) {
+	if strings.TrimSpace(dirs) == "" {
+		return nil, nil
+	}
+	reg := excelsnapshot.NewFontRegistry(logger)
+	for _, dir := range splitList(dirs) {
+		n, err := reg.LoadDir(dir)
+		if err != nil {
+			return nil, fmt.Errorf("加载字体目录 %s 失败: %w", dir, err)
+		}
+		logger.Debug("加载字体目录", zap.String("dir", dir), zap.Int("fonts", n))
+	}
+	return reg, nil
+}
+
+// splitList 拆分逗号分隔的参数值（忽略空项）
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // 确定要渲染的工作表名称
 func determineTargetSheet(args *CLIArgs, excel *excelsnapshot.Excel) (string, error) {
 	if args.sheet != "" {
@@ -82,10 +434,13 @@ func determineTargetSheet(args *CLIArgs, excel *excelsnapshot.Excel) (string, er
 }
 
 // 生成输出文件路径：
-// - 若 basePath 以 .png 结尾，按文件路径使用；
-// - 否则视为目录：目录必须已存在，否则返回错误；存在则在目录内自动命名（excel名_sheet_时间戳.png）。
-func generateOutputPath(basePath, sheetName, excelPath string) (string, error) {
-	if strings.HasSuffix(strings.ToLower(basePath), ".png") {
+// - 若 basePath 为 - 或以支持的图片扩展名（.png、.jpg 等）结尾，按文件路径使用；
+// - 否则视为目录：目录必须已存在，否则返回错误；存在则在目录内自动命名（excel名_sheet_时间戳.扩展名）。
+func generateOutputPath(basePath, sheetName, excelPath string, format excelsnapshot.ImageFormat) (string, error) {
+	if basePath == stdioPath {
+		return basePath, nil
+	}
+	if _, err := excelsnapshot.FormatFromPath(basePath); err == nil {
 		return basePath, nil
 	}
 
@@ -98,7 +453,10 @@ func generateOutputPath(basePath, sheetName, excelPath string) (string, error) {
 		return "", fmt.Errorf("输出目录不存在或不可用: %s", basePath)
 	}
 
-	// 提取Excel文件名（不含扩展名）
+	// 提取Excel文件名（不含扩展名），从标准输入读取时使用 stdin
+	if excelPath == stdioPath {
+		excelPath = "stdin"
+	}
 	excelFileName := filepath.Base(excelPath)
 	excelFileName = strings.TrimSuffix(excelFileName, filepath.Ext(excelFileName))
 
@@ -115,23 +473,118 @@ func generateOutputPath(basePath, sheetName, excelPath string) (string, error) {
 	timestamp := time.Now().Format("20060102_150405")
 
 	// 组合文件名
-	filename := fmt.Sprintf("%s_%s_%s.png", excelFileNameSafe, sheetNameSafe, timestamp)
+	filename := fmt.Sprintf("%s_%s_%s%s", excelFileNameSafe, sheetNameSafe, timestamp, format.Ext())
 	return filepath.Join(basePath, filename), nil
 }
 
-// 保存渲染结果
-func saveImage(img image.Image, outputPath string) error {
-	outFile, err := os.Create(outputPath)
+// renderers 渲染器与输出配置；缩略图使用同一个渲染器，按次传入尺寸限制
+type renderers struct {
+	main *excelsnapshot.SheetRenderer
+	// 缩略图的最大宽高（像素），0 表示不输出缩略图
+	thumb int
+	// 图块边长（像素），大于 0 时以 Deep Zoom 图块代替整图输出
+	tileSize int
+	// 整图与缩略图的输出格式与编码参数（图块固定为 PNG）
+	format  excelsnapshot.ImageFormat
+	encoder *excelsnapshot.EncodeOptions
+}
+
+// renderAndSave 渲染工作表并保存；启用缩略图时额外输出 *_thumb.png
+func (r *renderers) renderAndSave(ctx context.Context, sheet *excelsnapshot.Sheet, outputPath string, logger *zap.Logger) error {
+	if r.tileSize > 0 {
+		if err := saveTiles(ctx, r.main, sheet, r.tileSize, outputPath, logger); err != nil {
+			return err
+		}
+	} else {
+		img, err := r.main.RenderSheetContext(ctx, sheet)
+		if err != nil {
+			return err
+		}
+		if err := saveImage(img, outputPath, r.format, r.encoder); err != nil {
+			return err
+		}
+	}
+	if r.thumb <= 0 {
+		return nil
+	}
+
+	// 缩略图按尺寸限制重新栅格化，而非缩放主图
+	thumb, err := r.main.RenderSheetContext(ctx, sheet, r.thumbOptions()...)
+	if err != nil {
+		return err
+	}
+	thumbPath := thumbnailPath(outputPath)
+	if err := saveImage(thumb, thumbPath, r.format, r.encoder); err != nil {
+		return err
+	}
+	logger.Debug("缩略图已保存", zap.String("output", thumbPath))
+	return nil
+}
+
+// thumbOptions 渲染缩略图时的单次选项
+func (r *renderers) thumbOptions() []excelsnapshot.RendererOption {
+	return []excelsnapshot.RendererOption{excelsnapshot.WithMaxSize(r.thumb, r.thumb)}
+}
+
+// saveTiles 以 Deep Zoom 格式保存图块：{name}.dzi、{name}.json 与 {name}_files/{level}/{col}_{row}.png
+// ctx 取消时中止渲染
+func saveTiles(ctx context.Context, renderer *excelsnapshot.SheetRenderer, sheet *excelsnapshot.Sheet, tileSize int, outputPath string, logger *zap.Logger) error {
+	base := strings.TrimSuffix(outputPath, filepath.Ext(outputPath))
+	tilesDir := base + "_files"
+
+	manifest, err := renderer.RenderTilePyramidContext(ctx, sheet, tileSize, func(tile excelsnapshot.Tile, img image.Image) error {
+		dir := filepath.Join(tilesDir, strconv.Itoa(tile.Level))
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+		return saveImage(img, filepath.Join(dir, fmt.Sprintf("%d_%d.png", tile.Col, tile.Row)), excelsnapshot.FormatPNG, nil)
+	})
 	if err != nil {
 		return err
 	}
-	defer outFile.Close()
 
-	return png.Encode(outFile, img)
+	dzi, err := manifest.DZI()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(base+".dzi", dzi, 0o644); err != nil {
+		return err
+	}
+	index, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(base+".json", index, 0o644); err != nil {
+		return err
+	}
+	logger.Debug("图块已保存", zap.String("dzi", base+".dzi"), zap.Int("levels", len(manifest.Levels)))
+	return nil
+}
+
+// thumbnailPath 在文件名（扩展名之前）追加 _thumb
+func thumbnailPath(outputPath string) string {
+	ext := filepath.Ext(outputPath)
+	return strings.TrimSuffix(outputPath, ext) + "_thumb" + ext
+}
+
+// 保存渲染结果；outputPath 为 - 时写入标准输出
+func saveImage(img image.Image, outputPath string, format excelsnapshot.ImageFormat, opts *excelsnapshot.EncodeOptions) error {
+	if outputPath == stdioPath {
+		return excelsnapshot.EncodeImage(os.Stdout, img, format, opts)
+	}
+	outFile, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	if err := excelsnapshot.EncodeImage(outFile, img, format, opts); err != nil {
+		outFile.Close()
+		return err
+	}
+	return outFile.Close()
 }
 
 // 渲染单个工作表
-func renderSingleSheet(args *CLIArgs, excel *excelsnapshot.Excel, renderer *excelsnapshot.SheetRenderer, logger *zap.Logger) error {
+func renderSingleSheet(ctx context.Context, args *CLIArgs, excel *excelsnapshot.Excel, renderer *renderers, logger *zap.Logger) error {
 	// 确定目标工作表
 	targetSheet, err := determineTargetSheet(args, excel)
 	if err != nil {
@@ -140,24 +593,23 @@ func renderSingleSheet(args *CLIArgs, excel *excelsnapshot.Excel, renderer *exce
 
 	logger.Info("开始渲染工作表", zap.String("sheet", targetSheet))
 
-	// 获取工作表
-	sheet, err := excel.GetSheet(targetSheet)
-	if err != nil {
-		return err
+	// 获取工作表（指定区域时只加载该区域）
+	var sheet *excelsnapshot.Sheet
+	if args.cellRange != "" {
+		sheet, err = excel.GetSheetRangeContext(ctx, targetSheet, args.cellRange)
+	} else {
+		sheet, err = excel.GetSheetContext(ctx, targetSheet)
 	}
-
-	// 渲染
-	img, err := renderer.RenderSheet(sheet)
 	if err != nil {
 		return err
 	}
 
-	// 生成输出路径并保存
-	outputPath, err := generateOutputPath(args.outPath, targetSheet, args.inPath)
+	// 生成输出路径，渲染并保存
+	outputPath, err := generateOutputPath(args.outPath, targetSheet, args.inPath, args.imageFormat)
 	if err != nil {
 		return err
 	}
-	if err := saveImage(img, outputPath); err != nil {
+	if err := renderer.renderAndSave(ctx, sheet, outputPath, logger); err != nil {
 		return err
 	}
 
@@ -165,27 +617,65 @@ func renderSingleSheet(args *CLIArgs, excel *excelsnapshot.Excel, renderer *exce
 	return nil
 }
 
-// 渲染所有工作表
-func renderAllSheets(args *CLIArgs, excel *excelsnapshot.Excel, renderer *excelsnapshot.SheetRenderer, logger *zap.Logger) error {
-	logger.Info("开始渲染所有工作表")
-
-	for _, sheet := range excel.Sheets() {
-		logger.Info("正在渲染工作表", zap.String("sheet", sheet.Name))
-
-		img, err := renderer.RenderSheet(sheet)
-		if err != nil {
-			return fmt.Errorf("渲染工作表 %s 失败: %w", sheet.Name, err)
+// 渲染所有工作表：按工作簿顺序选取工作表（默认跳过隐藏的工作表），以 -j 个工作协程并发加载、渲染与编码；输出图块时逐个加载与渲染
+func renderAllSheets(ctx context.Context, args *CLIArgs, excel *excelsnapshot.Excel, renderer *renderers, logger *zap.Logger) error {
+	names, err := excel.SelectSheets(args.selector)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("没有符合条件的工作表（隐藏的工作表需指定 -include-hidden）")
+	}
+	logger.Info("开始渲染所有工作表", zap.Strings("sheets", names), zap.Int("workers", args.jobs))
+
+	if renderer.tileSize > 0 {
+		// 图块在渲染时逐个写入文件；工作表逐个加载且不放入缓存，渲染完即释放，内存峰值与工作表数量无关
+		for _, name := range names {
+			sheet := excelsnapshot.NewSheet(excel, name)
+			if err := sheet.LoadContext(ctx); err != nil {
+				return fmt.Errorf("渲染工作表 %s 失败: %w", name, err)
+			}
+			outputPath, err := generateOutputPath(args.outPath, name, args.inPath, args.imageFormat)
+			if err != nil {
+				return fmt.Errorf("输出目录校验失败: %w", err)
+			}
+			if err := renderer.renderAndSave(ctx, sheet, outputPath, logger); err != nil {
+				return fmt.Errorf("渲染工作表 %s 失败: %w", name, err)
+			}
+			logger.Info("工作表渲染完成", zap.String("sheet", name), zap.String("output", outputPath))
 		}
+		logger.Info("所有工作表渲染完成")
+		return nil
+	}
 
-		outputPath, err := generateOutputPath(args.outPath, sheet.Name, args.inPath)
+	// 缩略图按尺寸限制重新栅格化，作为渲染变体与整图在同一个任务中完成，每个工作表只加载一次
+	batch := excelsnapshot.BatchOptions{Workers: args.jobs, Sheets: names, Format: renderer.format, Encode: renderer.encoder}
+	if renderer.thumb > 0 {
+		batch.Variants = [][]excelsnapshot.RendererOption{renderer.thumbOptions()}
+	}
+	err = renderer.main.RenderSheetsContext(ctx, excel, batch, func(res *excelsnapshot.SheetResult) error {
+		if res.Err != nil {
+			return fmt.Errorf("渲染工作表 %s 失败: %w", res.Name, res.Err)
+		}
+		outputPath, err := generateOutputPath(args.outPath, res.Name, args.inPath, args.imageFormat)
 		if err != nil {
 			return fmt.Errorf("输出目录校验失败: %w", err)
 		}
-		if err := saveImage(img, outputPath); err != nil {
-			return fmt.Errorf("保存工作表 %s 失败: %w", sheet.Name, err)
+		if err := os.WriteFile(outputPath, res.Data, 0o644); err != nil {
+			return fmt.Errorf("保存工作表 %s 失败: %w", res.Name, err)
 		}
-
-		logger.Info("工作表渲染完成", zap.String("sheet", sheet.Name), zap.String("output", outputPath))
+		if len(res.Variants) > 0 {
+			thumbPath := thumbnailPath(outputPath)
+			if err := os.WriteFile(thumbPath, res.Variants[0], 0o644); err != nil {
+				return fmt.Errorf("保存工作表 %s 的缩略图失败: %w", res.Name, err)
+			}
+			logger.Debug("缩略图已保存", zap.String("output", thumbPath))
+		}
+		logger.Info("工作表渲染完成", zap.String("sheet", res.Name), zap.String("output", outputPath))
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	logger.Info("所有工作表渲染完成")
@@ -213,27 +703,82 @@ func main() {
 	}
 	defer loggerSync()
 
+	// 加载字体
+	fonts, err := loadFontRegistry(args.fontDirs, logger)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
 	// 初始化渲染器
-	renderer := excelsnapshot.NewSheetRenderer(logger)
+	opts := []excelsnapshot.RendererOption{
+		excelsnapshot.WithScale(args.scale),
+		excelsnapshot.WithDPI(args.dpi),
+		excelsnapshot.WithFontRegistry(fonts),
+		excelsnapshot.WithFallbackFonts(splitList(args.fallbackFonts)...),
+		excelsnapshot.WithFilterButtons(args.filterButtons),
+		excelsnapshot.WithValidationDropdowns(args.dropdowns),
+		excelsnapshot.WithShowFormulas(args.showFormulas),
+		excelsnapshot.WithErrorIndicators(args.errorMarks),
+		excelsnapshot.WithRenderLimits(args.limits()),
+		excelsnapshot.WithMaxSize(args.maxWidth, args.maxHeight),
+		excelsnapshot.WithGridlines(!args.noGridlines),
+		excelsnapshot.WithGridlineColor(args.gridColor),
+	}
+	if args.transparent {
+		opts = append(opts, excelsnapshot.WithBackground(nil))
+	} else if args.backgroundColor != nil {
+		opts = append(opts, excelsnapshot.WithBackground(args.backgroundColor))
+	}
+	renderer := &renderers{
+		main:     excelsnapshot.NewSheetRenderer(logger, opts...),
+		thumb:    args.thumb,
+		tileSize: args.tiles,
+		format:   args.imageFormat,
+		encoder:  &excelsnapshot.EncodeOptions{JPEGQuality: args.quality},
+	}
 
 	// 加载Excel文件
 	logger.Info("加载 Excel 文件", zap.String("path", args.inPath))
-	excel, err := excelsnapshot.NewExcel(args.inPath, logger)
+	password, err := resolvePassword(args)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "加载Excel文件失败: %v\n", err)
+		fmt.Fprintf(os.Stderr, "%v\n", err)
 		os.Exit(1)
 	}
+	excelOpts := []excelsnapshot.ExcelOption{
+		excelsnapshot.WithPassword(password),
+		excelsnapshot.WithFormulaEvaluation(args.calc),
+		excelsnapshot.WithStreaming(args.stream),
+		excelsnapshot.WithMeasureFonts(fonts, splitList(args.fallbackFonts)...),
+		excelsnapshot.WithLoadLimits(args.limits()),
+	}
+	// Ctrl+C 时中止转换、加载与渲染；再次按下时恢复默认行为，立即退出
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
 
-	// 根据参数决定渲染模式
-	if args.all {
-		// 渲染所有工作表
-		if err := excel.ParseAll(); err != nil {
-			fmt.Fprintf(os.Stderr, "解析Excel失败: %v\n", err)
-			os.Exit(1)
+	excel, err := openWorkbook(ctx, args, logger, excelOpts)
+	if err != nil {
+		hint := limitHint(err)
+		var pe *excelsnapshot.PasswordError
+		if errors.As(err, &pe) {
+			hint = fmt.Sprintf("（可通过 -password、-password-fd 或环境变量 %s 提供）", passwordEnv)
+		}
+		var fe *excelsnapshot.UnsupportedFormatError
+		if errors.As(err, &fe) && fe.Format != excelsnapshot.FormatUnknown {
+			hint = "（请先在 Excel 中另存为 .xlsx）"
 		}
+		fmt.Fprintf(os.Stderr, "加载Excel文件失败: %v%s\n", err, hint)
+		os.Exit(1)
+	}
 
-		if err := renderAllSheets(args, excel, renderer, logger); err != nil {
-			fmt.Fprintf(os.Stderr, "渲染所有工作表失败: %v\n", err)
+	// 根据参数决定渲染模式
+	if args.all {
+		// 渲染所有工作表（由 renderAllSheets 并发加载）
+		if err := renderAllSheets(ctx, args, excel, renderer, logger); err != nil {
+			fmt.Fprintf(os.Stderr, "渲染所有工作表失败: %v%s\n", err, limitHint(err))
 			os.Exit(1)
 		}
 	} else {
@@ -244,13 +789,16 @@ func main() {
 			os.Exit(1)
 		}
 
-		if err := excel.Parse(targetSheet); err != nil {
-			fmt.Fprintf(os.Stderr, "解析工作表失败: %v\n", err)
-			os.Exit(1)
+		// 指定区域时由 renderSingleSheet 只加载该区域
+		if args.cellRange == "" {
+			if _, err := excel.GetSheetContext(ctx, targetSheet); err != nil {
+				fmt.Fprintf(os.Stderr, "解析工作表失败: %v%s\n", err, limitHint(err))
+				os.Exit(1)
+			}
 		}
 
-		if err := renderSingleSheet(args, excel, renderer, logger); err != nil {
-			fmt.Fprintf(os.Stderr, "渲染失败: %v\n", err)
+		if err := renderSingleSheet(ctx, args, excel, renderer, logger); err != nil {
+			fmt.Fprintf(os.Stderr, "渲染失败: %v%s\n", err, limitHint(err))
 			os.Exit(1)
 		}
 	}
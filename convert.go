@@ -0,0 +1,185 @@
+package excelsnapshot
+
+import (
+	"bytes"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// convertedSheet 由其他格式（ODS、XLS）转换而来的工作表，尺寸使用 Excel 的单位
+type convertedSheet struct {
+	name     string
+	hidden   bool
+	tabColor string // RRGGBB，空表示无
+	// 默认列宽（字符数）与默认行高（磅），0 表示使用 Excel 默认值
+	defaultColWidth, defaultRowHeight float64
+	cols                              []convertedCols
+	rows                              map[int]excelize.RowOpts
+	// 行号 → 该行的单元格（excelize.Cell，下标为列号减 1，nil 表示空）
+	cells  map[int][]interface{}
+	merges [][2]string // 左上、右下单元格
+	// 转换期间的取消、超时与单元格数量检查，count 为已设置的单元格数
+	guard *loadGuard
+	count int
+}
+
+// convertedCols 连续若干列的列宽（字符数，0 表示未设置）与隐藏状态
+type convertedCols struct {
+	first, last int
+	width       float64
+	hidden      bool
+}
+
+func newConvertedSheet(name string, guard *loadGuard) *convertedSheet {
+	return &convertedSheet{
+		name:  name,
+		rows:  make(map[int]excelize.RowOpts),
+		cells: make(map[int][]interface{}),
+		guard: guard,
+	}
+}
+
+// setCell 设置单元格（1-based 行列），超出 Excel 最大行列数的单元格被忽略
+func (s *convertedSheet) setCell(row, col int, cell excelize.Cell) error {
+	if row > excelize.TotalRows || col > excelize.MaxColumns {
+		return nil
+	}
+	values := s.cells[row]
+	if len(values) < col {
+		values = append(values, make([]interface{}, col-len(values))...)
+	}
+	if values[col-1] == nil {
+		s.count++
+	}
+	values[col-1] = cell
+	s.cells[row] = values
+	return s.guard.step(s.count)
+}
+
+// addMerge 记录合并区域（1-based 起止行列），超出 Excel 最大行列数的部分被截断
+func (s *convertedSheet) addMerge(row1, col1, row2, col2 int) {
+	row2, col2 = min(row2, excelize.TotalRows), min(col2, excelize.MaxColumns)
+	if row1 > row2 || col1 > col2 {
+		return
+	}
+	tl, _ := excelize.CoordinatesToCellName(col1, row1)
+	br, _ := excelize.CoordinatesToCellName(col2, row2)
+	s.merges = append(s.merges, [2]string{tl, br})
+}
+
+// writeConvertedWorkbook 将转换后的工作表写入 f（样式须已在 f 中创建）并返回可读取的工作簿
+// 工作表以流式方式写入，可同时保留公式与其缓存结果（普通写入接口设置值时会清除公式）；
+// 流式写入的内容需保存后才能读取，因此序列化后重新打开，原始内容同时用于流式加载
+func (e *Excel) writeConvertedWorkbook(f *excelize.File, sheets []*convertedSheet) (*excelize.File, error) {
+	for i, s := range sheets {
+		if err := writeConvertedSheet(f, i, s); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	f, err := e.reopenWorkbook(f)
+	if err != nil {
+		return nil, err
+	}
+
+	// 流式写入不支持的属性：隐藏列与隐藏工作表
+	for _, s := range sheets {
+		for _, c := range s.cols {
+			if !c.hidden {
+				continue
+			}
+			first, _ := excelize.ColumnNumberToName(c.first)
+			last, _ := excelize.ColumnNumberToName(c.last)
+			if err := f.SetColVisible(s.name, first+":"+last, false); err != nil {
+				f.Close()
+				return nil, err
+			}
+		}
+		if s.hidden {
+			// 工作簿中只剩一个可见工作表时 excelize 不会隐藏，忽略错误
+			_ = f.SetSheetVisible(s.name, false)
+		}
+	}
+	return f, nil
+}
+
+// reopenWorkbook 序列化在内存中构建的工作簿并重新打开（关闭 f）
+// 原始内容保存在 e.data 中，供流式加载、区域加载与工作表列表读取工作表部件
+func (e *Excel) reopenWorkbook(f *excelize.File) (*excelize.File, error) {
+	buf, err := f.WriteToBuffer()
+	f.Close()
+	if err != nil {
+		return nil, err
+	}
+	e.data = buf.Bytes()
+	return excelize.OpenReader(bytes.NewReader(e.data), e.openOptions)
+}
+
+// writeConvertedSheet 创建工作表（第一个工作表沿用默认的 Sheet1）并流式写入列宽、行与合并区域
+func writeConvertedSheet(f *excelize.File, index int, s *convertedSheet) error {
+	if index == 0 {
+		if err := f.SetSheetName("Sheet1", s.name); err != nil {
+			return err
+		}
+	} else if _, err := f.NewSheet(s.name); err != nil {
+		return err
+	}
+
+	// 工作表属性需在创建流式写入器之前设置
+	props := &excelize.SheetPropsOptions{}
+	if s.tabColor != "" {
+		props.TabColorRGB = &s.tabColor
+	}
+	if s.defaultColWidth > 0 {
+		props.DefaultColWidth = &s.defaultColWidth
+	}
+	if s.defaultRowHeight > 0 {
+		props.DefaultRowHeight = &s.defaultRowHeight
+		custom := true
+		props.CustomHeight = &custom
+	}
+	if *props != (excelize.SheetPropsOptions{}) {
+		if err := f.SetSheetProps(s.name, props); err != nil {
+			return err
+		}
+	}
+
+	sw, err := f.NewStreamWriter(s.name)
+	if err != nil {
+		return err
+	}
+	for _, c := range s.cols {
+		if c.width > 0 {
+			if err := sw.SetColWidth(c.first, c.last, math.Min(c.width, excelize.MaxColumnWidth)); err != nil {
+				return err
+			}
+		}
+	}
+
+	rows := make([]int, 0, len(s.cells)+len(s.rows))
+	for row := range s.cells {
+		rows = append(rows, row)
+	}
+	for row := range s.rows {
+		if _, ok := s.cells[row]; !ok {
+			rows = append(rows, row)
+		}
+	}
+	sort.Ints(rows)
+	for _, row := range rows {
+		opts := s.rows[row]
+		opts.Height = math.Min(opts.Height, excelize.MaxRowHeight)
+		if err := sw.SetRow("A"+strconv.Itoa(row), s.cells[row], opts); err != nil {
+			return err
+		}
+	}
+	for _, m := range s.merges {
+		if err := sw.MergeCell(m[0], m[1]); err != nil {
+			return err
+		}
+	}
+	return sw.Flush()
+}
@@ -0,0 +1,298 @@
+package excelsnapshot
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// CSVHeader CSV 首行是否为标题行
+type CSVHeader int
+
+const (
+	// CSVHeaderAuto 根据内容判断首行是否为标题行
+	CSVHeaderAuto CSVHeader = iota
+	// CSVHeaderPresent 首行为标题行
+	CSVHeaderPresent
+	// CSVHeaderAbsent 没有标题行
+	CSVHeaderAbsent
+)
+
+// defaultCSVTableStyle CSV 有标题行时使用的默认表格样式
+const defaultCSVTableStyle = "TableStyleMedium2"
+
+// CSVOptions CSV/TSV 的解析参数
+type CSVOptions struct {
+	// 分隔符，0 表示根据首行自动判断（逗号、制表符、分号、竖线）
+	Comma rune
+	// 文本编码：utf-8、gbk、gb18030，空值表示自动判断（有效 UTF-8 按 UTF-8，否则按 GB18030）
+	// 带 BOM 的 UTF-8/UTF-16 总是按 BOM 解码
+	Encoding string
+	// 首行是否为标题行
+	Header CSVHeader
+	// 工作表名称，默认为 Sheet1
+	SheetName string
+	// 有标题行时使用的表格样式，默认为 TableStyleMedium2；"none" 表示不创建表格
+	TableStyle string
+}
+
+// NewExcelFromCSV 读取 CSV/TSV 并构建只有一个工作表的工作簿
+// 有标题行时整个区域创建为表格（默认 TableStyleMedium2），否则为所有单元格添加细边框
+func NewExcelFromCSV(r io.Reader, logger *zap.Logger, csvOpts CSVOptions, opts ...ExcelOption) (*Excel, error) {
+	return NewExcelFromCSVContext(context.Background(), r, logger, csvOpts, opts...)
+}
+
+// NewExcelFromCSVContext 同 NewExcelFromCSV，构建工作簿时按 WithLoadLimits 检查单元格数与耗时，ctx 取消时中止
+func NewExcelFromCSVContext(ctx context.Context, r io.Reader, logger *zap.Logger, csvOpts CSVOptions, opts ...ExcelOption) (*Excel, error) {
+	records, err := readCSVRecords(r, csvOpts)
+	if err != nil {
+		return nil, err
+	}
+	excel := newExcel("", logger, opts)
+	guard, cancel := excel.limits.newGuard(ctx, csvSheetName(csvOpts))
+	f, err := buildCSVWorkbook(records, csvOpts, guard)
+	cancel()
+	if err != nil {
+		return nil, err
+	}
+	if f, err = excel.reopenWorkbook(f); err != nil {
+		return nil, err
+	}
+	excel.logger.Debug("CSV 加载完成", zap.Int("rows", len(records)))
+	return excel.init(f)
+}
+
+// readCSVRecords 解码文本并解析所有记录
+func readCSVRecords(r io.Reader, opts CSVOptions) ([][]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("读取 CSV 失败: %w", err)
+	}
+	text, err := decodeCSVText(data, opts.Encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	comma := opts.Comma
+	if comma == 0 {
+		comma = detectCSVDelimiter(text)
+	}
+	reader := csv.NewReader(bytes.NewReader(text))
+	reader.Comma = comma
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("解析 CSV 失败: %w", err)
+	}
+	return records, nil
+}
+
+// decodeCSVText 按 BOM 或指定编码将内容转换为 UTF-8
+func decodeCSVText(data []byte, name string) ([]byte, error) {
+	var fallback encoding.Encoding
+	switch strings.ToLower(strings.ReplaceAll(name, "-", "")) {
+	case "", "auto":
+		fallback = unicode.UTF8
+		if !utf8.Valid(data) {
+			fallback = simplifiedchinese.GB18030
+		}
+	case "utf8":
+		fallback = unicode.UTF8
+	case "gbk", "cp936":
+		fallback = simplifiedchinese.GBK
+	case "gb18030":
+		fallback = simplifiedchinese.GB18030
+	default:
+		return nil, fmt.Errorf("不支持的 CSV 编码: %s", name)
+	}
+	// BOMOverride：存在 UTF-8/UTF-16 BOM 时按 BOM 解码并去除 BOM，否则使用指定编码
+	decoder := unicode.BOMOverride(fallback.NewDecoder())
+	text, _, err := transform.Bytes(decoder, data)
+	if err != nil {
+		return nil, fmt.Errorf("CSV 编码转换失败: %w", err)
+	}
+	return text, nil
+}
+
+// detectCSVDelimiter 统计首行（引号外）各候选分隔符的出现次数，取最多者，默认为逗号
+func detectCSVDelimiter(text []byte) rune {
+	line, _ := bufio.NewReader(bytes.NewReader(text)).ReadString('\n')
+	counts := map[rune]int{}
+	quoted := false
+	for _, r := range line {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case !quoted && strings.ContainsRune(",\t;|", r):
+			counts[r]++
+		}
+	}
+	best := ','
+	for _, r := range []rune{'\t', ';', '|'} {
+		if counts[r] > counts[best] {
+			best = r
+		}
+	}
+	return best
+}
+
+// detectCSVHeader 判断首行是否为标题行：
+// 首行各列非空、互不相同且都不是数字，并且首行的值不在该列的后续行中重复出现
+func detectCSVHeader(records [][]string) bool {
+	if len(records) == 0 || len(records[0]) == 0 {
+		return false
+	}
+	seen := make(map[string]bool)
+	for _, v := range records[0] {
+		v = strings.TrimSpace(v)
+		if v == "" || seen[v] || isCSVNumber(v) {
+			return false
+		}
+		seen[v] = true
+	}
+	for _, rec := range records[1:] {
+		for i, v := range rec {
+			if i < len(records[0]) && strings.TrimSpace(v) == strings.TrimSpace(records[0][i]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isCSVNumber 判断文本是否为数字
+func isCSVNumber(v string) bool {
+	_, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+	return err == nil
+}
+
+// csvCellValue 将文本转换为单元格值：能无损往返的数字按数字写入，其余保持原文本
+func csvCellValue(v string) interface{} {
+	if n, err := strconv.ParseInt(v, 10, 64); err == nil && strconv.FormatInt(n, 10) == v {
+		return n
+	}
+	if n, err := strconv.ParseFloat(v, 64); err == nil && strconv.FormatFloat(n, 'f', -1, 64) == v {
+		return n
+	}
+	return v
+}
+
+// csvSheetName 返回 CSV 工作簿的工作表名称
+func csvSheetName(opts CSVOptions) string {
+	if opts.SheetName == "" {
+		return "Sheet1"
+	}
+	return opts.SheetName
+}
+
+// buildCSVWorkbook 将记录写入新的工作簿并应用默认样式，guard 检查单元格数（按记录的矩形区域计算）与取消
+func buildCSVWorkbook(records [][]string, opts CSVOptions, guard *loadGuard) (*excelize.File, error) {
+	if err := guard.err(); err != nil {
+		return nil, err
+	}
+	maxCol := 0
+	for _, rec := range records {
+		maxCol = max(maxCol, len(rec))
+	}
+	// 加载时会补齐矩形区域内的空单元格，因此在写入之前按整个区域检查
+	if err := guard.reserve(cellRange{StartRow: 1, StartCol: 1, EndRow: len(records), EndCol: maxCol}); err != nil {
+		return nil, err
+	}
+
+	f := excelize.NewFile()
+	sheet := csvSheetName(opts)
+	if sheet != "Sheet1" {
+		if err := f.SetSheetName("Sheet1", sheet); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("无效的工作表名称 %q: %w", sheet, err)
+		}
+	}
+
+	cells := 0
+	for i, rec := range records {
+		row := make([]interface{}, len(rec))
+		for j, v := range rec {
+			row[j] = csvCellValue(v)
+		}
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		if err := f.SetSheetRow(sheet, cell, &row); err != nil {
+			f.Close()
+			return nil, err
+		}
+		cells += len(rec)
+		if err := guard.step(cells); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	if len(records) == 0 || maxCol == 0 {
+		return f, nil
+	}
+	last, _ := excelize.CoordinatesToCellName(maxCol, len(records))
+	// excelize 写入单元格时不更新工作表记录的已用区域
+	if err := f.SetSheetDimension(sheet, "A1:"+last); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	header := opts.Header == CSVHeaderPresent || (opts.Header == CSVHeaderAuto && detectCSVHeader(records))
+	tableStyle := opts.TableStyle
+	if tableStyle == "" {
+		tableStyle = defaultCSVTableStyle
+	}
+	if header && tableStyle != "none" {
+		// 表格要求标题非空且互不相同，excelize 会为空标题补全 ColumnN
+		err := f.AddTable(sheet, &excelize.Table{
+			Range:          "A1:" + last,
+			Name:           "CSVData",
+			StyleName:      tableStyle,
+			ShowRowStripes: boolPtr(true),
+		})
+		if err == nil {
+			return f, nil
+		}
+		// 标题重复等原因无法创建表格时，按无标题的方式添加边框
+	}
+	border, err := f.NewStyle(&excelize.Style{Border: []excelize.Border{
+		{Type: "left", Color: "BFBFBF", Style: 1},
+		{Type: "top", Color: "BFBFBF", Style: 1},
+		{Type: "right", Color: "BFBFBF", Style: 1},
+		{Type: "bottom", Color: "BFBFBF", Style: 1},
+	}})
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := f.SetCellStyle(sheet, "A1", last, border); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if header {
+		bold, err := f.NewStyle(&excelize.Style{
+			Font:   &excelize.Font{Bold: true},
+			Border: []excelize.Border{{Type: "bottom", Color: "808080", Style: 1}},
+		})
+		if err == nil {
+			lastHeader, _ := excelize.CoordinatesToCellName(maxCol, 1)
+			f.SetCellStyle(sheet, "A1", lastHeader, bold)
+		}
+	}
+	return f, nil
+}
+
+// boolPtr 返回 bool 指针
+func boolPtr(v bool) *bool { return &v }
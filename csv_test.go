@@ -0,0 +1,204 @@
+package excelsnapshot
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+// TestDetectCSVDelimiter 测试分隔符自动判断
+func TestDetectCSVDelimiter(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want rune
+	}{
+		{"逗号", "a,b,c\n1,2,3\n", ','},
+		{"制表符", "a\tb\tc\n", '\t'},
+		{"分号", "a;b;c\n", ';'},
+		{"竖线", "a|b\n", '|'},
+		{"引号内的逗号不计数", "\"x,y,z\";b;c\n", ';'},
+		{"单列默认逗号", "abc\n", ','},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectCSVDelimiter([]byte(tt.text)); got != tt.want {
+				t.Errorf("detectCSVDelimiter() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestDetectCSVHeader 测试标题行自动判断
+func TestDetectCSVHeader(t *testing.T) {
+	tests := []struct {
+		name    string
+		records [][]string
+		want    bool
+	}{
+		{"文本标题与数字数据", [][]string{{"名称", "数量"}, {"苹果", "10"}}, true},
+		{"首行为数字", [][]string{{"1", "2"}, {"3", "4"}}, false},
+		{"首行有空值", [][]string{{"名称", ""}, {"苹果", "10"}}, false},
+		{"首行有重复值", [][]string{{"名称", "名称"}, {"苹果", "10"}}, false},
+		{"首行的值在后续行重复", [][]string{{"是", "否"}, {"是", "是"}}, false},
+		{"空记录", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectCSVHeader(tt.records); got != tt.want {
+				t.Errorf("detectCSVHeader() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestDecodeCSVText 测试编码转换
+func TestDecodeCSVText(t *testing.T) {
+	gbk, err := simplifiedchinese.GBK.NewEncoder().Bytes([]byte("名称,数量"))
+	if err != nil {
+		t.Fatalf("GBK 编码失败: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		data     []byte
+		encoding string
+		want     string
+		wantErr  bool
+	}{
+		{"UTF-8", []byte("名称,数量"), "", "名称,数量", false},
+		{"UTF-8 BOM", append([]byte{0xEF, 0xBB, 0xBF}, "名称,数量"...), "", "名称,数量", false},
+		{"UTF-16LE BOM", []byte{0xFF, 0xFE, 'a', 0, ',', 0, 'b', 0}, "", "a,b", false},
+		{"自动判断 GBK", gbk, "", "名称,数量", false},
+		{"指定 GBK", gbk, "gbk", "名称,数量", false},
+		{"指定 GB18030", gbk, "GB18030", "名称,数量", false},
+		{"不支持的编码", []byte("a"), "latin9", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeCSVText(tt.data, tt.encoding)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeCSVText() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(got) != tt.want {
+				t.Errorf("decodeCSVText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestNewExcelFromCSV 测试从 CSV 构建工作簿
+func TestNewExcelFromCSV(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+
+	tests := []struct {
+		name      string
+		text      string
+		opts      CSVOptions
+		sheet     string
+		wantTable bool
+		wantCells map[string]string
+	}{
+		{
+			name:      "有标题行",
+			text:      "名称,数量,备注\n苹果,10,\"红色, 新鲜\"\n香蕉,0.50,\n",
+			sheet:     "Sheet1",
+			wantTable: true,
+			wantCells: map[string]string{"A1": "名称", "B2": "10", "C2": "红色, 新鲜", "B3": "0.50"},
+		},
+		{
+			name:      "无标题行的 TSV",
+			text:      "1\t2\n3\t4\n",
+			opts:      CSVOptions{Comma: '\t', SheetName: "数据"},
+			sheet:     "数据",
+			wantCells: map[string]string{"A1": "1", "B2": "4"},
+		},
+		{
+			name:      "强制无标题行",
+			text:      "名称,数量\n苹果,10\n",
+			opts:      CSVOptions{Header: CSVHeaderAbsent},
+			sheet:     "Sheet1",
+			wantCells: map[string]string{"A1": "名称"},
+		},
+		{
+			name:      "不创建表格",
+			text:      "名称,数量\n苹果,10\n",
+			opts:      CSVOptions{TableStyle: "none"},
+			sheet:     "Sheet1",
+			wantCells: map[string]string{"B2": "10"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			excel, err := NewExcelFromCSV(strings.NewReader(tt.text), logger, tt.opts)
+			if err != nil {
+				t.Fatalf("NewExcelFromCSV() 失败: %v", err)
+			}
+			defer excel.Close()
+			sheet, err := excel.GetSheet(tt.sheet)
+			if err != nil {
+				t.Fatalf("获取工作表失败: %v", err)
+			}
+			for addr, want := range tt.wantCells {
+				if cell := sheet.cells.get(addr); cell == nil || cell.Value != want {
+					t.Errorf("%s = %+v, want %q", addr, cell, want)
+				}
+			}
+			if got := len(sheet.Tables()) == 1; got != tt.wantTable {
+				t.Errorf("是否创建表格 = %v, want %v", got, tt.wantTable)
+			}
+			// 无表格时所有单元格带有边框
+			if !tt.wantTable {
+				style, err := sheet.cells.get("A1").RenderStyle()
+				if err != nil || style == nil || len(style.Border) == 0 {
+					t.Errorf("A1 应带有边框样式: %+v, %v", style, err)
+				}
+			}
+
+			var buf bytes.Buffer
+			if err := NewSheetRenderer(logger).RenderTo(&buf, sheet, FormatPNG, nil); err != nil {
+				t.Errorf("渲染失败: %v", err)
+			}
+		})
+	}
+
+	if _, err := NewExcelFromCSV(strings.NewReader("a,b"), logger, CSVOptions{SheetName: "a/b"}); err == nil {
+		t.Error("无效的工作表名称应该返回错误")
+	}
+}
+
+// TestNewExcelFromCSV_Stream 测试 CSV 工作簿的流式加载与区域加载
+func TestNewExcelFromCSV_Stream(t *testing.T) {
+	const text = "名称,数量\n苹果,10\n香蕉,20\n橙子,30\n"
+	logger := zaptest.NewLogger(t)
+
+	excel, err := NewExcelFromCSV(strings.NewReader(text), logger, CSVOptions{}, WithStreaming(true))
+	if err != nil {
+		t.Fatalf("NewExcelFromCSV() 失败: %v", err)
+	}
+	defer excel.Close()
+	sheet, err := excel.GetSheet("Sheet1")
+	if err != nil {
+		t.Fatalf("流式加载工作表失败: %v", err)
+	}
+	if cell := sheet.cells.get("B4"); cell == nil || cell.Value != "30" {
+		t.Errorf("B4 = %+v, want %q", cell, "30")
+	}
+	if len(sheet.Tables()) != 1 {
+		t.Errorf("流式加载后表格数量 = %d, want 1", len(sheet.Tables()))
+	}
+
+	part, err := excel.GetSheetRange("Sheet1", "A2:B3")
+	if err != nil {
+		t.Fatalf("GetSheetRange() 失败: %v", err)
+	}
+	if cell := part.cells.get("A3"); cell == nil || cell.Value != "香蕉" {
+		t.Errorf("A3 = %+v, want %q", cell, "香蕉")
+	}
+	if cell := part.cells.get("A4"); cell != nil {
+		t.Errorf("区域外的单元格 A4 不应被加载: %+v", cell)
+	}
+}
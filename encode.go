@@ -0,0 +1,122 @@
+package excelsnapshot
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/image/bmp"
+	"golang.org/x/image/tiff"
+)
+
+// ImageFormat 输出图片格式
+type ImageFormat string
+
+const (
+	FormatPNG  ImageFormat = "png"
+	FormatJPEG ImageFormat = "jpeg"
+	FormatGIF  ImageFormat = "gif"
+	FormatBMP  ImageFormat = "bmp"
+	FormatTIFF ImageFormat = "tiff"
+)
+
+// defaultJPEGQuality JPEG 默认质量（文字边缘在较低质量下会出现明显的压缩噪点）
+const defaultJPEGQuality = 90
+
+// ParseImageFormat 根据格式名或扩展名（可带点，不区分大小写）解析图片格式
+// 支持 png、jpg/jpeg、gif、bmp、tif/tiff
+func ParseImageFormat(name string) (ImageFormat, error) {
+	switch strings.ToLower(strings.TrimPrefix(name, ".")) {
+	case "png":
+		return FormatPNG, nil
+	case "jpg", "jpeg":
+		return FormatJPEG, nil
+	case "gif":
+		return FormatGIF, nil
+	case "bmp":
+		return FormatBMP, nil
+	case "tif", "tiff":
+		return FormatTIFF, nil
+	}
+	return "", fmt.Errorf("不支持的图片格式: %s", name)
+}
+
+// FormatFromPath 根据文件扩展名判断图片格式
+func FormatFromPath(path string) (ImageFormat, error) {
+	return ParseImageFormat(filepath.Ext(path))
+}
+
+// Ext 返回格式对应的文件扩展名（含点）
+func (f ImageFormat) Ext() string {
+	switch f {
+	case FormatJPEG:
+		return ".jpg"
+	case FormatTIFF:
+		return ".tiff"
+	case "":
+		return ".png"
+	}
+	return "." + string(f)
+}
+
+// EncodeOptions 编码参数，为 nil 时使用默认值
+type EncodeOptions struct {
+	// JPEG 质量（1-100），0 表示默认值 90
+	JPEGQuality int
+	// PNG 压缩级别，默认为 png.DefaultCompression
+	PNGCompression png.CompressionLevel
+}
+
+// EncodeImage 将图片按指定格式编码写入 w；format 为空时使用 PNG
+// GIF 使用 256 色调色板，适合颜色较少的表格；JPEG 不支持透明，透明部分按白色输出
+func EncodeImage(w io.Writer, img image.Image, format ImageFormat, opts *EncodeOptions) error {
+	if opts == nil {
+		opts = &EncodeOptions{}
+	}
+	switch format {
+	case FormatPNG, "":
+		enc := png.Encoder{CompressionLevel: opts.PNGCompression}
+		return enc.Encode(w, img)
+	case FormatJPEG:
+		quality := opts.JPEGQuality
+		if quality <= 0 {
+			quality = defaultJPEGQuality
+		}
+		return jpeg.Encode(w, flattenImage(img, color.White), &jpeg.Options{Quality: min(quality, 100)})
+	case FormatGIF:
+		return gif.Encode(w, img, nil)
+	case FormatBMP:
+		return bmp.Encode(w, img)
+	case FormatTIFF:
+		return tiff.Encode(w, img, &tiff.Options{Compression: tiff.Deflate})
+	}
+	return fmt.Errorf("不支持的图片格式: %s", format)
+}
+
+// RenderTo 渲染工作表并按指定格式编码写入 w，renderOpts 只作用于本次渲染
+func (sr *SheetRenderer) RenderTo(w io.Writer, sheet *Sheet, format ImageFormat, opts *EncodeOptions, renderOpts ...RendererOption) error {
+	img, err := sr.RenderSheet(sheet, renderOpts...)
+	if err != nil {
+		return err
+	}
+	return EncodeImage(w, img, format, opts)
+}
+
+// flattenImage 将含透明像素的图片合成到 bg 背景上，不透明的图片原样返回
+func flattenImage(img image.Image, bg color.Color) image.Image {
+	if o, ok := img.(interface{ Opaque() bool }); !ok || o.Opaque() {
+		return img
+	}
+	b := img.Bounds()
+	out := image.NewRGBA(b)
+	draw.Draw(out, b, image.NewUniform(bg), image.Point{}, draw.Src)
+	draw.Draw(out, b, img, b.Min, draw.Over)
+	return out
+}
@@ -0,0 +1,148 @@
+package excelsnapshot
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/tiff"
+)
+
+// TestParseImageFormat 测试图片格式解析
+func TestParseImageFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    ImageFormat
+		wantErr bool
+	}{
+		{"png", FormatPNG, false},
+		{".PNG", FormatPNG, false},
+		{"jpg", FormatJPEG, false},
+		{"jpeg", FormatJPEG, false},
+		{"gif", FormatGIF, false},
+		{"bmp", FormatBMP, false},
+		{".tif", FormatTIFF, false},
+		{"tiff", FormatTIFF, false},
+		{"webp", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseImageFormat(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseImageFormat(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseImageFormat(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+
+	if got, err := FormatFromPath("out/report.JPG"); err != nil || got != FormatJPEG {
+		t.Errorf("FormatFromPath() = %q, %v, want jpeg", got, err)
+	}
+}
+
+// TestEncodeImage 测试各格式编码后可被解码且尺寸一致
+func TestEncodeImage(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 30, 20))
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 30; x++ {
+			img.Set(x, y, color.RGBA{uint8(x * 8), uint8(y * 12), 128, 255})
+		}
+	}
+
+	tests := []struct {
+		format ImageFormat
+		want   string // image.Decode 返回的格式名
+	}{
+		{FormatPNG, "png"},
+		{"", "png"},
+		{FormatJPEG, "jpeg"},
+		{FormatGIF, "gif"},
+		{FormatBMP, "bmp"},
+		{FormatTIFF, "tiff"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := EncodeImage(&buf, img, tt.format, nil); err != nil {
+				t.Fatalf("EncodeImage() 失败: %v", err)
+			}
+			cfg, name, err := image.DecodeConfig(&buf)
+			if err != nil {
+				t.Fatalf("解码失败: %v", err)
+			}
+			if name != tt.want || cfg.Width != 30 || cfg.Height != 20 {
+				t.Errorf("解码结果 = %s %dx%d, want %s 30x20", name, cfg.Width, cfg.Height, tt.want)
+			}
+		})
+	}
+
+	if err := EncodeImage(&bytes.Buffer{}, img, "webp", nil); err == nil {
+		t.Error("不支持的格式应该返回错误")
+	}
+
+	// JPEG 质量越低，输出越小
+	var low, high bytes.Buffer
+	EncodeImage(&low, img, FormatJPEG, &EncodeOptions{JPEGQuality: 10})
+	EncodeImage(&high, img, FormatJPEG, &EncodeOptions{JPEGQuality: 100})
+	if low.Len() >= high.Len() {
+		t.Errorf("JPEG 质量 10 的大小 %d 应小于质量 100 的大小 %d", low.Len(), high.Len())
+	}
+
+	// JPEG 不支持透明，透明部分按白色输出
+	var transparent bytes.Buffer
+	if err := EncodeImage(&transparent, image.NewRGBA(image.Rect(0, 0, 8, 8)), FormatJPEG, nil); err != nil {
+		t.Fatalf("EncodeImage() 失败: %v", err)
+	}
+	decoded, _, err := image.Decode(&transparent)
+	if err != nil {
+		t.Fatalf("解码失败: %v", err)
+	}
+	if r, g, b, _ := decoded.At(4, 4).RGBA(); r>>8 < 250 || g>>8 < 250 || b>>8 < 250 {
+		t.Errorf("透明像素编码为 JPEG 后应为白色, got %d,%d,%d", r>>8, g>>8, b>>8)
+	}
+}
+
+// TestSheetRenderer_RenderTo 测试渲染结果直接写入 io.Writer
+func TestSheetRenderer_RenderTo(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "test.xlsx")
+	if err := createTestExcelFile(testFile); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+	logger := zaptest.NewLogger(t)
+	excel, err := NewExcel(testFile, logger)
+	if err != nil {
+		t.Fatalf("加载Excel文件失败: %v", err)
+	}
+	defer excel.Close()
+	sheet, err := excel.GetSheet("Sheet1")
+	if err != nil {
+		t.Fatalf("获取工作表失败: %v", err)
+	}
+
+	renderer := NewSheetRenderer(logger)
+	want, err := renderer.RenderSheet(sheet)
+	if err != nil {
+		t.Fatalf("RenderSheet() 失败: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := renderer.RenderTo(&buf, sheet, FormatJPEG, nil); err != nil {
+		t.Fatalf("RenderTo() 失败: %v", err)
+	}
+	cfg, name, err := image.DecodeConfig(&buf)
+	if err != nil {
+		t.Fatalf("解码失败: %v", err)
+	}
+	if name != "jpeg" || cfg.Width != want.Bounds().Dx() || cfg.Height != want.Bounds().Dy() {
+		t.Errorf("RenderTo() = %s %dx%d, want jpeg %dx%d", name, cfg.Width, cfg.Height, want.Bounds().Dx(), want.Bounds().Dy())
+	}
+}
@@ -1,38 +1,231 @@
 package excelsnapshot
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sync"
 
 	"github.com/xuri/excelize/v2"
 	"go.uber.org/zap"
 )
 
 // Excel 结构体
+// GetSheet、GetSheetRange、LoadAllSheets 等读取方法可被多个 goroutine 并发调用：
+// 同一工作表只加载一次，并发的调用等待同一次加载；Close 不能与其他方法并发调用
 type Excel struct {
-	path       string
-	file       *excelize.File
+	path string
+	// 从内存或 fs.FS 打开时保留的原始文件内容（流式加载读取超出解压大小限制的工作表时使用）
+	data []byte
+	file *excelize.File
+	// mu 保护已加载的工作表与正在加载的工作表
+	mu         sync.Mutex
 	sheets     map[string]*Sheet
+	loading    map[string]*sheetCall
 	indexSheet map[int]string
 	logger     *zap.Logger
+
+	// 缺少缓存值时是否计算公式结果
+	evalFormulas bool
+	// 是否以流式方式加载工作表
+	streaming bool
+	// 加载工作表的资源限制
+	limits Limits
+	// 按内容识别的文件格式
+	format WorkbookFormat
+	// 传递给 excelize 的打开选项与加密工作簿的打开密码
+	openOptions excelize.Options
+	password    string
+
+	// 自动列宽/行高度量所用字体（应与渲染器的字体配置一致）
+	fontRegistry  *FontRegistry
+	fallbackFonts []string
+	fonts         *fontCache
+	// 工作簿默认字体及其最大数字宽度（像素），用于列宽单位换算
+	defaultFont   textFont
+	maxDigitWidth float64
+}
+
+// ExcelOption Excel 的可选配置
+type ExcelOption func(*Excel)
+
+// WithFormulaEvaluation 加载工作表时，对没有缓存结果的公式单元格计算其值
+func WithFormulaEvaluation(enable bool) ExcelOption {
+	return func(e *Excel) {
+		e.evalFormulas = enable
+	}
+}
+
+// WithStreaming 以流式方式加载工作表：逐行读取，仅保留有值、有样式或有公式的单元格，
+// 适合维度很大但内容稀疏的工作表；此模式不加载图片，公式计算（WithFormulaEvaluation）仍需读取完整工作表
+func WithStreaming(enable bool) ExcelOption {
+	return func(e *Excel) {
+		e.streaming = enable
+	}
+}
+
+// WithLoadLimits 设置加载工作表的资源限制（MaxCells、MaxImageBytes 与 MaxDuration），超出时加载返回 *LimitError
+func WithLoadLimits(limits Limits) ExcelOption {
+	return func(e *Excel) {
+		e.limits = limits
+	}
+}
+
+// WithOpenOptions 设置打开工作簿时传递给 excelize 的选项（如 UnzipSizeLimit、UnzipXMLSizeLimit）
+func WithOpenOptions(opts excelize.Options) ExcelOption {
+	return func(e *Excel) {
+		e.openOptions = opts
+	}
+}
+
+// WithMeasureFonts 设置自动列宽与行高度量所用的字体注册表与后备字体
+// 应与渲染器的 WithFontRegistry/WithFallbackFonts 保持一致，使度量结果与绘制一致
+func WithMeasureFonts(fonts *FontRegistry, fallback ...string) ExcelOption {
+	return func(e *Excel) {
+		e.fontRegistry = fonts
+		e.fallbackFonts = append([]string(nil), fallback...)
+	}
 }
 
-// NewExcel 创建 Excel struct
-func NewExcel(path string, logger *zap.Logger) (*Excel, error) {
-	f, err := excelize.OpenFile(path)
+// NewExcel 创建 Excel struct，按文件内容（而非扩展名）识别格式：
+// xlsx、xlsm、xltx、xltm 与加密的工作簿由 excelize 打开，xls、ods 转换后打开，其他格式返回 UnsupportedFormatError。
+// logger 为 nil 时不输出日志；使用 log/slog 时可传入 NewSlogLogger 的返回值
+func NewExcel(path string, logger *zap.Logger, opts ...ExcelOption) (*Excel, error) {
+	return NewExcelContext(context.Background(), path, logger, opts...)
+}
+
+// NewExcelContext 同 NewExcel，转换 xls、ods 时按 WithLoadLimits 检查每个工作表的单元格数与耗时，ctx 取消时中止
+func NewExcelContext(ctx context.Context, path string, logger *zap.Logger, opts ...ExcelOption) (*Excel, error) {
+	excel := newExcel(path, logger, opts)
+	format, err := detectFileFormat(path)
 	if err != nil {
 		return nil, err
 	}
+	if !format.spreadsheetML() {
+		// 需要转换或解密的格式读取完整内容后识别
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return excel.open(ctx, data)
+	}
+	excel.format = format
+	f, err := excelize.OpenFile(path, excel.excelizeOptions())
+	if err != nil {
+		return nil, excel.openError(err, func() []byte {
+			data, _ := os.ReadFile(path)
+			return data
+		})
+	}
+	return excel.init(f)
+}
+
+// NewExcelFromReader 从 io.Reader 读取工作簿（如上传的文件、对象存储的读取流）
+func NewExcelFromReader(r io.Reader, logger *zap.Logger, opts ...ExcelOption) (*Excel, error) {
+	return NewExcelFromReaderContext(context.Background(), r, logger, opts...)
+}
+
+// NewExcelFromReaderContext 同 NewExcelFromReader，转换时的限制与取消同 NewExcelContext
+func NewExcelFromReaderContext(ctx context.Context, r io.Reader, logger *zap.Logger, opts ...ExcelOption) (*Excel, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("读取工作簿失败: %w", err)
+	}
+	return NewExcelFromBytesContext(ctx, data, logger, opts...)
+}
+
+// NewExcelFromBytes 从内存中的文件内容打开工作簿（格式识别同 NewExcel），data 在 Excel 关闭前不应被修改
+func NewExcelFromBytes(data []byte, logger *zap.Logger, opts ...ExcelOption) (*Excel, error) {
+	return NewExcelFromBytesContext(context.Background(), data, logger, opts...)
+}
+
+// NewExcelFromBytesContext 同 NewExcelFromBytes，转换时的限制与取消同 NewExcelContext
+func NewExcelFromBytesContext(ctx context.Context, data []byte, logger *zap.Logger, opts ...ExcelOption) (*Excel, error) {
+	return newExcel("", logger, opts).open(ctx, data)
+}
+
+// NewExcelFromFS 从 fs.FS 中打开工作簿（如 embed.FS、os.DirFS），Path 返回 name
+func NewExcelFromFS(fsys fs.FS, name string, logger *zap.Logger, opts ...ExcelOption) (*Excel, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	return newExcel(name, logger, opts).open(context.Background(), data)
+}
+
+// open 按内容识别格式并打开内存中的工作簿，ctx 用于 xls、ods 的转换
+func (e *Excel) open(ctx context.Context, data []byte) (*Excel, error) {
+	e.format = DetectFormat(data)
+	var f *excelize.File
+	var err error
+	switch {
+	case e.format == FormatXLS:
+		f, err = e.buildXLSWorkbook(ctx, data)
+	case e.format == FormatODS:
+		f, err = e.buildODSWorkbook(ctx, data)
+	case e.format.spreadsheetML() || e.format == FormatEncrypted:
+		e.data = data
+		f, err = excelize.OpenReader(bytes.NewReader(data), e.excelizeOptions())
+		if err != nil {
+			err = e.openError(err, func() []byte { return data })
+		}
+	default:
+		err = &UnsupportedFormatError{Format: e.format}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return e.init(f)
+}
+
+// newExcel 创建尚未打开文件的 Excel 并应用配置
+func newExcel(path string, logger *zap.Logger, opts []ExcelOption) *Excel {
 	excel := &Excel{
 		path:       path,
-		file:       f,
 		sheets:     make(map[string]*Sheet),
+		loading:    make(map[string]*sheetCall),
 		indexSheet: make(map[int]string),
-		logger:     logger,
+		logger:     orNop(logger),
+	}
+	for _, opt := range opts {
+		opt(excel)
 	}
-	if err := excel.parseSheetListToMap(); err != nil {
+	return excel
+}
+
+// init 关联已打开的文件并读取字体与工作表列表
+func (e *Excel) init(f *excelize.File) (*Excel, error) {
+	e.file = f
+	e.fonts = newFontCache(e.fontRegistry, e.fallbackFonts)
+	e.loadDefaultFont()
+	if err := e.parseSheetListToMap(); err != nil {
+		f.Close()
 		return nil, err
 	}
-	return excel, nil
+	return e, nil
+}
+
+// loadDefaultFont 读取工作簿默认字体（样式表中的第一个字体）并计算最大数字宽度
+func (e *Excel) loadDefaultFont() {
+	e.defaultFont = textFont{family: "Calibri", size: 11}
+	if name, err := e.file.GetDefaultFont(); err == nil && name != "" {
+		e.defaultFont.family = name
+	}
+	if st, err := e.file.GetStyle(0); err == nil && st != nil && st.Font != nil && st.Font.Size > 0 {
+		e.defaultFont.size = st.Font.Size
+	}
+	fonts := e.fonts.get()
+	defer e.fonts.put(fonts)
+	e.maxDigitWidth = fonts.maxDigitWidth(e.defaultFont)
+}
+
+// MaxDigitWidth 返回工作簿默认字体的最大数字宽度（96 DPI 像素），即一个列宽单位对应的像素数
+func (e *Excel) MaxDigitWidth() float64 {
+	return e.maxDigitWidth
 }
 
 // GetSheetList 获取工作表列表
@@ -48,46 +241,160 @@ func (e *Excel) parseSheetListToMap() error {
 	return nil
 }
 
+// sheetCall 正在进行的工作表加载，并发的 GetSheet 等待其完成
+type sheetCall struct {
+	done  chan struct{}
+	sheet *Sheet
+	err   error
+}
+
 // LoadSheets 预加载所有工作表信息（名称、行列数、单元格值等）
 func (e *Excel) LoadAllSheets() error {
-	names := e.file.GetSheetList()
-	for idx, name := range names {
-		if _, ok := e.sheets[name]; ok {
-			continue
-		}
-		e.logger.Info("加载工作表", zap.String("name", name))
-		sh := NewSheet(e, name)
-		sh.Index = idx
-		if err := sh.Load(); err != nil {
-			return fmt.Errorf("加载工作表 %s 失败: %w", name, err)
-		}
-		e.sheets[name] = sh
+	return e.LoadAllSheetsParallel(1)
+}
+
+// LoadAllSheetsParallel 以 workers 个工作协程并发预加载所有工作表，workers 小于等于 0 时为 runtime.GOMAXPROCS(0)
+// 按工作簿顺序返回第一个加载失败的工作表的错误
+func (e *Excel) LoadAllSheetsParallel(workers int) error {
+	if e.file == nil {
+		return fmt.Errorf("Excel 文件未打开")
 	}
-	return nil
+	return e.LoadSheets(e.file.GetSheetList(), workers)
+}
+
+// LoadSheets 以 workers 个工作协程并发加载指定的工作表，按 names 的顺序返回第一个加载失败的工作表的错误
+func (e *Excel) LoadSheets(names []string, workers int) error {
+	return e.LoadSheetsContext(context.Background(), names, workers)
+}
+
+// LoadSheetsContext 同 LoadSheets，ctx 取消时中止加载
+func (e *Excel) LoadSheetsContext(ctx context.Context, names []string, workers int) error {
+	return runSheets(names, workers, func(res *SheetResult) {
+		res.Sheet, res.Err = e.GetSheetContext(ctx, res.Name)
+	}, func(res *SheetResult) error {
+		if res.Err != nil {
+			return fmt.Errorf("加载工作表 %s 失败: %w", res.Name, res.Err)
+		}
+		return nil
+	})
 }
 
 // GetSheet 获取指定名称的工作表（如未缓存则加载）
+// 可并发调用：同一工作表同时只有一次加载，其他调用等待并共享其结果；加载失败不会被缓存
 func (e *Excel) GetSheet(name string) (*Sheet, error) {
+	return e.GetSheetContext(context.Background(), name)
+}
+
+// GetSheetContext 同 GetSheet，ctx 取消时停止加载或等待；
+// 负责加载的调用被取消时，仍在等待的调用会重新加载而非共享取消的结果
+func (e *Excel) GetSheetContext(ctx context.Context, name string) (*Sheet, error) {
 	if e.file == nil {
 		return nil, fmt.Errorf("Excel 文件未打开")
 	}
-	if sh, ok := e.sheets[name]; ok {
+	for {
+		e.mu.Lock()
+		if sh, ok := e.sheets[name]; ok {
+			e.mu.Unlock()
+			return sh, nil
+		}
+		call, waiting := e.loading[name]
+		if !waiting {
+			call = &sheetCall{done: make(chan struct{})}
+			e.loading[name] = call
+		}
+		e.mu.Unlock()
+		if !waiting {
+			return e.loadSheet(ctx, name, call)
+		}
+
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		var le *LimitError
+		if call.err != nil && ctx.Err() == nil && isAbort(call.err) && !errors.As(call.err, &le) {
+			continue
+		}
+		return call.sheet, call.err
+	}
+}
+
+// loadSheet 加载工作表并完成 call，成功时缓存结果
+func (e *Excel) loadSheet(ctx context.Context, name string, call *sheetCall) (*Sheet, error) {
+	sh := NewSheet(e, name)
+	sh.Index, _ = e.file.GetSheetIndex(name)
+	if call.err = sh.LoadContext(ctx); call.err == nil {
+		call.sheet = sh
+	}
+	e.mu.Lock()
+	delete(e.loading, name)
+	if call.err == nil {
+		e.sheets[name] = sh
+	}
+	e.mu.Unlock()
+	close(call.done)
+	return call.sheet, call.err
+}
+
+// sheetUncached 返回已缓存的工作表；未缓存时加载一个新实例但不放入缓存，用完后随调用方释放
+func (e *Excel) sheetUncached(ctx context.Context, name string) (*Sheet, error) {
+	e.mu.Lock()
+	sh, ok := e.sheets[name]
+	e.mu.Unlock()
+	if ok {
 		return sh, nil
 	}
+	sh = NewSheet(e, name)
+	sh.Index, _ = e.file.GetSheetIndex(name)
+	if err := sh.LoadContext(ctx); err != nil {
+		return nil, err
+	}
+	return sh, nil
+}
+
+// GetSheetRange 以流式方式加载工作表的指定区域（如 "B2:H40"），渲染结果仅包含该区域
+// 区域加载的结果不会被缓存
+func (e *Excel) GetSheetRange(name, ref string) (*Sheet, error) {
+	return e.GetSheetRangeContext(context.Background(), name, ref)
+}
+
+// GetSheetRangeContext 同 GetSheetRange，ctx 取消或超出资源限制时中止加载
+func (e *Excel) GetSheetRangeContext(ctx context.Context, name, ref string) (*Sheet, error) {
+	if e.file == nil {
+		return nil, fmt.Errorf("Excel 文件未打开")
+	}
+	sc, sr, ec, er, err := parseRangeRef(ref)
+	if err != nil {
+		return nil, fmt.Errorf("无效的区域 %q: %w", ref, err)
+	}
 	sh := NewSheet(e, name)
-	if err := sh.Load(); err != nil {
+	release := sh.beginLoad(ctx)
+	defer release()
+	if err := sh.guard.err(); err != nil {
+		return nil, err
+	}
+	if err := sh.loadStream(cellRange{StartCol: sc, StartRow: sr, EndCol: ec, EndRow: er}); err != nil {
 		return nil, err
 	}
-	e.sheets[name] = sh
 	return sh, nil
 }
 
-// Sheets 返回已加载的工作表（名称到结构的映射）
+// Sheets 返回已加载的工作表（名称到结构的映射，为调用时的快照）
 func (e *Excel) Sheets() map[string]*Sheet {
-	return e.sheets
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	sheets := make(map[string]*Sheet, len(e.sheets))
+	for name, sh := range e.sheets {
+		sheets[name] = sh
+	}
+	return sheets
 }
 
-// Path 返回 Excel 文件路径
+// Format 返回按内容识别的文件格式（由 NewExcelFromCSV 创建时为 FormatUnknown）
+func (e *Excel) Format() WorkbookFormat { return e.format }
+
+// Path 返回 Excel 文件路径（从 io.Reader 或字节切片打开时为空）
 func (e *Excel) Path() string { return e.path }
 
 // Close 关闭 Excel 文件
@@ -1,8 +1,12 @@
 package excelsnapshot
 
 import (
+	"bytes"
+	"os"
 	"path/filepath"
+	"sync"
 	"testing"
+	"testing/fstest"
 
 	"github.com/xuri/excelize/v2"
 	"go.uber.org/zap"
@@ -40,6 +44,109 @@ func TestNewExcel(t *testing.T) {
 	}
 }
 
+// TestNewExcelFromReader 测试从 io.Reader、字节切片与 fs.FS 打开工作簿
+func TestNewExcelFromReader(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "test.xlsx")
+	if err := createTestExcelFile(testFile); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+	data, err := os.ReadFile(testFile)
+	if err != nil {
+		t.Fatalf("读取测试文件失败: %v", err)
+	}
+	logger := zaptest.NewLogger(t)
+
+	tests := []struct {
+		name     string
+		open     func() (*Excel, error)
+		wantPath string
+		wantErr  bool
+	}{
+		{
+			name:     "io.Reader",
+			open:     func() (*Excel, error) { return NewExcelFromReader(bytes.NewReader(data), logger) },
+			wantPath: "",
+		},
+		{
+			name:     "字节切片",
+			open:     func() (*Excel, error) { return NewExcelFromBytes(data, logger) },
+			wantPath: "",
+		},
+		{
+			name: "fs.FS",
+			open: func() (*Excel, error) {
+				return NewExcelFromFS(fstest.MapFS{"in/test.xlsx": {Data: data}}, "in/test.xlsx", logger)
+			},
+			wantPath: "in/test.xlsx",
+		},
+		{
+			name:    "fs.FS 中不存在的文件",
+			open:    func() (*Excel, error) { return NewExcelFromFS(fstest.MapFS{}, "none.xlsx", logger) },
+			wantErr: true,
+		},
+		{
+			name:    "无效内容",
+			open:    func() (*Excel, error) { return NewExcelFromBytes([]byte("not a workbook"), logger) },
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			excel, err := tt.open()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("打开工作簿 error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			defer excel.Close()
+			if excel.Path() != tt.wantPath {
+				t.Errorf("Path() = %q, want %q", excel.Path(), tt.wantPath)
+			}
+			sheet, err := excel.GetSheet("Sheet1")
+			if err != nil {
+				t.Fatalf("获取工作表失败: %v", err)
+			}
+			if cell := sheet.cells.get("B1"); cell == nil || cell.Value != "Hello" {
+				t.Errorf("B1 = %+v, want Hello", cell)
+			}
+		})
+	}
+}
+
+// TestNewExcelFromBytes_StreamingSpilled 测试超出解压大小限制的工作表在流式加载时从原始内容读取
+func TestNewExcelFromBytes_StreamingSpilled(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "test.xlsx")
+	if err := createTestExcelFile(testFile); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+	data, err := os.ReadFile(testFile)
+	if err != nil {
+		t.Fatalf("读取测试文件失败: %v", err)
+	}
+
+	// 解压大小限制很小时，excelize 将工作表写入临时文件而不保留在内存中
+	excel, err := NewExcelFromBytes(data, zaptest.NewLogger(t),
+		WithStreaming(true),
+		WithOpenOptions(excelize.Options{UnzipXMLSizeLimit: 1}),
+	)
+	if err != nil {
+		t.Fatalf("打开工作簿失败: %v", err)
+	}
+	defer excel.Close()
+	if readWorksheetXML(excel.file, "Sheet1") != nil {
+		t.Skip("工作表仍在内存中，无法验证回退路径")
+	}
+	sheet, err := excel.GetSheet("Sheet1")
+	if err != nil {
+		t.Fatalf("获取工作表失败: %v", err)
+	}
+	if cell := sheet.cells.get("A2"); cell == nil || cell.Value != "123" {
+		t.Errorf("A2 = %+v, want 123", cell)
+	}
+}
+
 // TestExcel_GetSheetList 测试获取工作表名称
 func TestExcel_GetSheetList(t *testing.T) {
 	// 创建临时Excel文件进行测试
@@ -201,3 +308,59 @@ func BenchmarkLoadSheet(b *testing.B) {
 		}
 	}
 }
+
+// TestExcel_GetSheet_Concurrent 测试并发获取工作表：同名工作表只加载一次并返回同一实例
+func TestExcel_GetSheet_Concurrent(t *testing.T) {
+	f := excelize.NewFile()
+	names := []string{"Sheet1", "Sheet2", "Sheet3"}
+	for i, name := range names {
+		if i > 0 {
+			f.NewSheet(name)
+		}
+		if err := createComplexWorksheet(f, name, 30, 8); err != nil {
+			t.Fatalf("创建工作表失败: %v", err)
+		}
+	}
+	buf, err := f.WriteToBuffer()
+	f.Close()
+	if err != nil {
+		t.Fatalf("写入工作簿失败: %v", err)
+	}
+
+	excel, err := NewExcelFromBytes(buf.Bytes(), zaptest.NewLogger(t))
+	if err != nil {
+		t.Fatalf("加载Excel失败: %v", err)
+	}
+	defer excel.Close()
+
+	const workers = 8
+	got := make([][]*Sheet, workers)
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, name := range names {
+				sheet, err := excel.GetSheet(name)
+				if err != nil {
+					t.Errorf("GetSheet(%q) 失败: %v", name, err)
+					return
+				}
+				got[w] = append(got[w], sheet)
+			}
+			excel.Sheets()
+		}()
+	}
+	wg.Wait()
+
+	for w := 1; w < workers; w++ {
+		for i := range got[w] {
+			if got[w][i] != got[0][i] {
+				t.Errorf("工作表 %q 被重复加载", names[i])
+			}
+		}
+	}
+	if len(excel.Sheets()) != len(names) {
+		t.Errorf("已加载的工作表数量 = %d, want %d", len(excel.Sheets()), len(names))
+	}
+}
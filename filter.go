@@ -0,0 +1,93 @@
+package excelsnapshot
+
+import (
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap"
+)
+
+// cellRange 以行列号表示的矩形区域（1-based，含两端）
+type cellRange struct {
+	StartCol, StartRow int
+	EndCol, EndRow     int
+}
+
+// contains 判断坐标是否位于区域内
+func (r cellRange) contains(row, col int) bool {
+	return row >= r.StartRow && row <= r.EndRow && col >= r.StartCol && col <= r.EndCol
+}
+
+// loadAutoFilter 从工作表级定义名称 _xlnm._FilterDatabase 中读取自动筛选区域
+func (s *Sheet) loadAutoFilter() {
+	s.autoFilter = nil
+	for _, dn := range s.excel.file.GetDefinedName() {
+		if dn.Name != "_xlnm._FilterDatabase" || dn.Scope != s.Name {
+			continue
+		}
+		sc, sr, ec, er, err := parseRangeRef(dn.RefersTo)
+		if err != nil {
+			s.excel.logger.Debug("解析自动筛选区域失败", zap.String("ref", dn.RefersTo), zap.Error(err))
+			continue
+		}
+		s.autoFilter = &cellRange{StartCol: sc, StartRow: sr, EndCol: ec, EndRow: er}
+		return
+	}
+}
+
+// loadListValidations 读取“序列”类型的数据验证区域（用于绘制下拉箭头提示）
+func (s *Sheet) loadListValidations() error {
+	s.listValidations = nil
+	dvs, err := s.excel.file.GetDataValidations(s.Name)
+	if err != nil {
+		return err
+	}
+	for _, dv := range dvs {
+		// 注意：OOXML 中 showDropDown=true 表示“隐藏”下拉箭头
+		if dv == nil || dv.Type != "list" || dv.ShowDropDown {
+			continue
+		}
+		for _, ref := range strings.Fields(dv.Sqref) {
+			sc, sr, ec, er, err := parseRangeRef(ref)
+			if err != nil {
+				continue
+			}
+			s.listValidations = append(s.listValidations, cellRange{StartCol: sc, StartRow: sr, EndCol: ec, EndRow: er})
+		}
+	}
+	return nil
+}
+
+// FilterButtonCells 返回需要绘制筛选按钮的单元格地址（自动筛选区域与表格的标题行）
+func (s *Sheet) FilterButtonCells() []string {
+	var addrs []string
+	seen := make(map[string]bool)
+	add := func(row, startCol, endCol int) {
+		for c := startCol; c <= endCol; c++ {
+			addr, _ := excelize.CoordinatesToCellName(c, row)
+			if !seen[addr] {
+				seen[addr] = true
+				addrs = append(addrs, addr)
+			}
+		}
+	}
+	if s.autoFilter != nil {
+		add(s.autoFilter.StartRow, s.autoFilter.StartCol, s.autoFilter.EndCol)
+	}
+	for _, t := range s.tables {
+		if t.HasAutoFilter {
+			add(t.StartRow+t.HeaderRows-1, t.StartCol, t.EndCol)
+		}
+	}
+	return addrs
+}
+
+// HasListValidation 判断单元格是否设置了下拉列表数据验证
+func (s *Sheet) HasListValidation(row, col int) bool {
+	for _, r := range s.listValidations {
+		if r.contains(row, col) {
+			return true
+		}
+	}
+	return false
+}
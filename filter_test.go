@@ -0,0 +1,115 @@
+package excelsnapshot
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap/zaptest"
+)
+
+// createTestExcelWithFilter 创建包含自动筛选与下拉列表数据验证的测试Excel文件
+func createTestExcelWithFilter(filename string) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	f.SetSheetRow("Sheet1", "A1", &[]interface{}{"名称", "状态", "备注"})
+	f.SetSheetRow("Sheet1", "A2", &[]interface{}{"任务1", "进行中", ""})
+	f.SetSheetRow("Sheet1", "A3", &[]interface{}{"任务2", "完成", ""})
+
+	if err := f.AutoFilter("Sheet1", "A1:C3", nil); err != nil {
+		return err
+	}
+
+	dv := excelize.NewDataValidation(true)
+	dv.Sqref = "B2:B3"
+	if err := dv.SetDropList([]string{"进行中", "完成"}); err != nil {
+		return err
+	}
+	if err := f.AddDataValidation("Sheet1", dv); err != nil {
+		return err
+	}
+	return f.SaveAs(filename)
+}
+
+// TestSheet_FilterAndValidation 测试自动筛选区域与数据验证区域的加载
+func TestSheet_FilterAndValidation(t *testing.T) {
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "filter.xlsx")
+	if err := createTestExcelWithFilter(testFile); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+
+	logger := zaptest.NewLogger(t)
+	excel, err := NewExcel(testFile, logger)
+	if err != nil {
+		t.Fatalf("加载Excel文件失败: %v", err)
+	}
+	defer excel.Close()
+
+	sheet, err := excel.GetSheet("Sheet1")
+	if err != nil {
+		t.Fatalf("获取工作表失败: %v", err)
+	}
+
+	got := sheet.FilterButtonCells()
+	want := []string{"A1", "B1", "C1"}
+	if len(got) != len(want) {
+		t.Fatalf("FilterButtonCells() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("FilterButtonCells()[%d] = %s, want %s", i, got[i], want[i])
+		}
+	}
+
+	if !sheet.HasListValidation(2, 2) || !sheet.HasListValidation(3, 2) {
+		t.Error("B2:B3 应带有下拉列表数据验证")
+	}
+	if sheet.HasListValidation(2, 1) {
+		t.Error("A2 不应带有下拉列表数据验证")
+	}
+}
+
+// TestSheetRenderer_DropdownHints 测试开启筛选按钮与下拉箭头后的渲染
+func TestSheetRenderer_DropdownHints(t *testing.T) {
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "filter.xlsx")
+	if err := createTestExcelWithFilter(testFile); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+
+	logger := zaptest.NewLogger(t)
+	excel, err := NewExcel(testFile, logger)
+	if err != nil {
+		t.Fatalf("加载Excel文件失败: %v", err)
+	}
+	defer excel.Close()
+
+	sheet, err := excel.GetSheet("Sheet1")
+	if err != nil {
+		t.Fatalf("获取工作表失败: %v", err)
+	}
+
+	plain, err := NewSheetRenderer(logger).RenderSheet(sheet)
+	if err != nil {
+		t.Fatalf("RenderSheet() 失败: %v", err)
+	}
+	renderer := NewSheetRenderer(logger, WithFilterButtons(true), WithValidationDropdowns(true))
+	img, err := renderer.RenderSheet(sheet)
+	if err != nil {
+		t.Fatalf("RenderSheet() 失败: %v", err)
+	}
+	if img.Bounds() != plain.Bounds() {
+		t.Errorf("开启下拉提示不应改变图片尺寸: %v vs %v", img.Bounds(), plain.Bounds())
+	}
+
+	// A1 右下角区域应绘制了按钮（与未开启时像素不同）
+	cellRects := renderer.calculateCellRects(sheet)
+	rect := cellRects["A1"]
+	px := int((rect.x + rect.w - 4) * renderer.scale)
+	py := int((rect.y + rect.h - 4) * renderer.scale)
+	if img.At(px, py) == plain.At(px, py) {
+		t.Error("A1 中未绘制筛选按钮")
+	}
+}
@@ -0,0 +1,236 @@
+package excelsnapshot
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+)
+
+// fontVariant 字体的字重/字形组合
+type fontVariant int
+
+const (
+	fontRegular fontVariant = iota
+	fontBold
+	fontItalic
+	fontBoldItalic
+)
+
+// variantOf 根据粗体/斜体得到字体变体
+func variantOf(bold, italic bool) fontVariant {
+	switch {
+	case bold && italic:
+		return fontBoldItalic
+	case bold:
+		return fontBold
+	case italic:
+		return fontItalic
+	}
+	return fontRegular
+}
+
+// defaultFontAliases 常见 Office 字体到开源等宽度替代字体的映射
+var defaultFontAliases = map[string][]string{
+	"calibri":           {"Carlito"},
+	"cambria":           {"Caladea"},
+	"arial":             {"Liberation Sans", "Arimo", "Helvetica"},
+	"helvetica":         {"Liberation Sans", "Arimo", "Arial"},
+	"times new roman":   {"Liberation Serif", "Tinos"},
+	"courier new":       {"Liberation Mono", "Cousine"},
+	"consolas":          {"Inconsolata", "DejaVu Sans Mono", "Liberation Mono"},
+	"verdana":           {"DejaVu Sans"},
+	"宋体":                {"SimSun", "Noto Serif CJK SC", "Source Han Serif SC"},
+	"simsun":            {"宋体", "Noto Serif CJK SC", "Source Han Serif SC"},
+	"微软雅黑":              {"Microsoft YaHei", "Noto Sans CJK SC", "Source Han Sans SC"},
+	"microsoft yahei":   {"微软雅黑", "Noto Sans CJK SC", "Source Han Sans SC"},
+	"等线":                {"DengXian", "Noto Sans CJK SC", "Source Han Sans SC"},
+	"dengxian":          {"等线", "Noto Sans CJK SC", "Source Han Sans SC"},
+	"黑体":                {"SimHei", "Noto Sans CJK SC", "Source Han Sans SC"},
+	"simhei":            {"黑体", "Noto Sans CJK SC", "Source Han Sans SC"},
+	"ms gothic":         {"Noto Sans CJK JP", "Source Han Sans JP"},
+	"malgun gothic":     {"Noto Sans CJK KR", "Source Han Sans KR"},
+	"segoe ui":          {"Noto Sans", "DejaVu Sans"},
+	"tahoma":            {"DejaVu Sans"},
+	"georgia":           {"Gelasio"},
+	"century gothic":    {"URW Gothic"},
+	"book antiqua":      {"TeX Gyre Pagella"},
+	"palatino linotype": {"TeX Gyre Pagella"},
+}
+
+// FontRegistry 字体注册表：从目录或 fs.FS 加载 TTF/OTF/TTC 字体，按字体族名（含别名）查找
+// 查找不到时由调用方回退到内置字体
+type FontRegistry struct {
+	mu       sync.RWMutex
+	families map[string]map[fontVariant]*opentype.Font
+	aliases  map[string][]string
+	logger   *zap.Logger
+}
+
+// NewFontRegistry 创建字体注册表（预置常见字体别名），logger 为 nil 时不输出日志
+func NewFontRegistry(logger *zap.Logger) *FontRegistry {
+	r := &FontRegistry{
+		families: make(map[string]map[fontVariant]*opentype.Font),
+		aliases:  make(map[string][]string),
+		logger:   orNop(logger),
+	}
+	for family, targets := range defaultFontAliases {
+		r.aliases[family] = append([]string(nil), targets...)
+	}
+	return r
+}
+
+// AddAlias 为字体族名添加替代字体（按顺序尝试），追加在已有别名之前
+func (r *FontRegistry) AddAlias(family string, targets ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := normalizeFamily(family)
+	r.aliases[key] = append(append([]string(nil), targets...), r.aliases[key]...)
+}
+
+// LoadDir 递归加载目录中的字体文件，返回成功加载的字体数量
+func (r *FontRegistry) LoadDir(dir string) (int, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return 0, err
+	}
+	if !info.IsDir() {
+		return 0, fmt.Errorf("字体目录不可用: %s", dir)
+	}
+	return r.LoadFS(os.DirFS(dir))
+}
+
+// LoadFS 递归加载 fs.FS 中的 .ttf/.otf/.ttc/.otc 字体文件，返回成功加载的字体数量
+// 无法读取的文件或子目录、无法解析的字体会被跳过并记录警告；仅根目录不可读时返回错误
+func (r *FontRegistry) LoadFS(fsys fs.FS) (int, error) {
+	loaded := 0
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == "." {
+				return err
+			}
+			r.logger.Warn("读取字体路径失败，已跳过", zap.String("path", p), zap.Error(err))
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		switch strings.ToLower(path.Ext(p)) {
+		case ".ttf", ".otf", ".ttc", ".otc":
+		default:
+			return nil
+		}
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			r.logger.Warn("读取字体文件失败，已跳过", zap.String("path", p), zap.Error(err))
+			return nil
+		}
+		n, err := r.LoadFont(data)
+		if err != nil {
+			r.logger.Warn("解析字体失败，已跳过", zap.String("path", p), zap.Error(err))
+			return nil
+		}
+		loaded += n
+		return nil
+	})
+	return loaded, err
+}
+
+// LoadFont 加载字体数据（支持字体集合 TTC/OTC），返回注册的字体数量
+func (r *FontRegistry) LoadFont(data []byte) (int, error) {
+	coll, err := opentype.ParseCollection(data)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for i := 0; i < coll.NumFonts(); i++ {
+		f, err := coll.Font(i)
+		if err != nil {
+			continue
+		}
+		if r.register(f) {
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, fmt.Errorf("字体缺少族名信息")
+	}
+	return count, nil
+}
+
+// register 按族名（name ID 1）与排版族名（name ID 16）注册字体
+func (r *FontRegistry) register(f *opentype.Font) bool {
+	var buf sfnt.Buffer
+	registered := false
+	for _, ids := range [][2]sfnt.NameID{
+		{sfnt.NameIDFamily, sfnt.NameIDSubfamily},
+		{sfnt.NameIDTypographicFamily, sfnt.NameIDTypographicSubfamily},
+	} {
+		family, err := f.Name(&buf, ids[0])
+		if err != nil || strings.TrimSpace(family) == "" {
+			continue
+		}
+		sub, _ := f.Name(&buf, ids[1])
+		sub = strings.ToLower(sub)
+		variant := variantOf(strings.Contains(sub, "bold"), strings.Contains(sub, "italic") || strings.Contains(sub, "oblique"))
+
+		r.mu.Lock()
+		key := normalizeFamily(family)
+		if r.families[key] == nil {
+			r.families[key] = make(map[fontVariant]*opentype.Font)
+		}
+		if _, exists := r.families[key][variant]; !exists {
+			r.families[key][variant] = f
+		}
+		r.mu.Unlock()
+		registered = true
+	}
+	return registered
+}
+
+// Lookup 按字体族名查找字体，依次尝试族名本身及其别名
+// 缺少所需字重/字形时退回同族的其他变体
+func (r *FontRegistry) Lookup(family string, bold, italic bool) (*opentype.Font, bool) {
+	if r == nil || strings.TrimSpace(family) == "" {
+		return nil, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	want := variantOf(bold, italic)
+	candidates := append([]string{family}, r.aliases[normalizeFamily(family)]...)
+	for _, name := range candidates {
+		variants, ok := r.families[normalizeFamily(name)]
+		if !ok {
+			continue
+		}
+		for _, v := range []fontVariant{want, variantOf(bold, false), variantOf(false, italic), fontRegular, fontBold, fontItalic, fontBoldItalic} {
+			if f, ok := variants[v]; ok {
+				return f, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// Families 返回已注册的字体族名（已规范化为小写）
+func (r *FontRegistry) Families() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	return names
+}
+
+// normalizeFamily 规范化字体族名用于匹配
+func normalizeFamily(family string) string {
+	return strings.ToLower(strings.TrimSpace(family))
+}
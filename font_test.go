@@ -0,0 +1,138 @@
+package excelsnapshot
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"go.uber.org/zap/zaptest"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// newTestFontRegistry 使用 Go 字体构造测试用字体注册表
+func newTestFontRegistry(t *testing.T) *FontRegistry {
+	t.Helper()
+	reg := NewFontRegistry(zaptest.NewLogger(t))
+	n, err := reg.LoadFS(fstest.MapFS{
+		"go/Go-Regular.ttf": {Data: goregular.TTF},
+		"go/Go-Bold.ttf":    {Data: gobold.TTF},
+		"mono/Go-Mono.ttf":  {Data: gomono.TTF},
+		"readme.txt":        {Data: []byte("not a font")},
+		"broken/Broken.ttf": {Data: []byte("broken")},
+	})
+	if err != nil {
+		t.Fatalf("LoadFS() 失败: %v", err)
+	}
+	if n != 3 {
+		t.Fatalf("LoadFS() 加载数量 = %d, want 3", n)
+	}
+	return reg
+}
+
+// TestFontRegistry_Lookup 测试按族名、字重与别名查找字体
+func TestFontRegistry_Lookup(t *testing.T) {
+	reg := newTestFontRegistry(t)
+	reg.AddAlias("Calibri", "Go")
+	reg.AddAlias("Consolas", "Go Mono")
+
+	regular, ok := reg.Lookup("Go", false, false)
+	if !ok {
+		t.Fatal("Lookup(Go) 未找到")
+	}
+	bold, ok := reg.Lookup("go", true, false)
+	if !ok {
+		t.Fatal("Lookup(go, bold) 未找到")
+	}
+	if regular == bold {
+		t.Error("粗体应使用 Go Bold 而非常规字体")
+	}
+	if f, ok := reg.Lookup("Calibri", false, false); !ok || f != regular {
+		t.Error("Calibri 应通过别名映射到 Go")
+	}
+	if f, ok := reg.Lookup("Go Mono", true, false); !ok || f == regular {
+		t.Error("Go Mono 缺少粗体时应退回同族常规字体")
+	}
+	for _, family := range []string{"", "Wingdings"} {
+		if _, ok := reg.Lookup(family, false, false); ok {
+			t.Errorf("Lookup(%q) 不应匹配", family)
+		}
+	}
+	var nilReg *FontRegistry
+	if _, ok := nilReg.Lookup("Go", false, false); ok {
+		t.Error("nil 注册表不应匹配")
+	}
+}
+
+// unreadableFS 打开指定路径时返回权限错误的 fs.FS，用于模拟无法读取的文件或目录
+type unreadableFS struct {
+	fs.FS
+	paths map[string]bool
+}
+
+func (u unreadableFS) Open(name string) (fs.File, error) {
+	if u.paths[name] {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrPermission}
+	}
+	return u.FS.Open(name)
+}
+
+// TestFontRegistry_LoadFS_Unreadable 测试无法读取的文件与子目录被跳过，其余字体照常加载
+func TestFontRegistry_LoadFS_Unreadable(t *testing.T) {
+	reg := NewFontRegistry(zaptest.NewLogger(t))
+	fsys := unreadableFS{
+		FS: fstest.MapFS{
+			"a/Go-Regular.ttf":   {Data: goregular.TTF},
+			"b/Go-Bold.ttf":      {Data: gobold.TTF},
+			"locked/Go-Mono.ttf": {Data: gomono.TTF},
+		},
+		paths: map[string]bool{"a/Go-Regular.ttf": true, "locked": true},
+	}
+	n, err := reg.LoadFS(fsys)
+	if err != nil {
+		t.Fatalf("LoadFS() 不应因单个条目无法读取而失败: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("LoadFS() 加载数量 = %d, want 1", n)
+	}
+	if _, ok := reg.Lookup("Go", true, false); !ok {
+		t.Error("可读取的 Go Bold 应被加载")
+	}
+
+	// 根目录不可读时返回错误
+	if _, err := reg.LoadFS(unreadableFS{FS: fstest.MapFS{}, paths: map[string]bool{".": true}}); err == nil {
+		t.Error("根目录不可读时应返回错误")
+	}
+}
+
+// TestSheetRenderer_GetFontFace 测试渲染器按字体族名取字体并回退到内置字体
+func TestSheetRenderer_GetFontFace(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	reg := newTestFontRegistry(t)
+	reg.AddAlias("Consolas", "Go Mono")
+	renderer := NewSheetRenderer(logger, WithFontRegistry(reg))
+
+	mono, err := renderer.GetFontFace("Consolas", 22, false, false)
+	if err != nil {
+		t.Fatalf("GetFontFace(Consolas) 失败: %v", err)
+	}
+	builtin, err := renderer.GetFontFace("Unknown Family", 22, false, false)
+	if err != nil {
+		t.Fatalf("GetFontFace(Unknown Family) 失败: %v", err)
+	}
+	if mono == builtin {
+		t.Error("已注册字体与内置字体不应为同一 Face")
+	}
+	again, _ := renderer.GetFont(22, false)
+	if again != builtin {
+		t.Error("无匹配时应复用内置字体缓存")
+	}
+
+	// 等宽字体中 "i" 与 "W" 宽度一致
+	iw, _ := mono.GlyphAdvance('i')
+	ww, _ := mono.GlyphAdvance('W')
+	if iw != ww {
+		t.Errorf("Consolas→Go Mono 字宽不一致: i=%v W=%v", iw, ww)
+	}
+}
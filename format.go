@@ -0,0 +1,134 @@
+package excelsnapshot
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// WorkbookFormat 按文件内容识别的工作簿格式
+type WorkbookFormat string
+
+// 可识别的工作簿格式
+const (
+	FormatUnknown WorkbookFormat = ""
+	FormatXLSX    WorkbookFormat = "xlsx"
+	FormatXLSM    WorkbookFormat = "xlsm" // 启用宏的工作簿
+	FormatXLTX    WorkbookFormat = "xltx" // 模板
+	FormatXLTM    WorkbookFormat = "xltm" // 启用宏的模板
+	FormatXLSB    WorkbookFormat = "xlsb" // 二进制工作簿，不受支持
+	FormatXLS     WorkbookFormat = "xls"  // Excel 97-2003（BIFF）
+	FormatODS     WorkbookFormat = "ods"
+	// 加密的 OOXML 工作簿，解密前无法区分 xlsx/xlsm 等
+	FormatEncrypted WorkbookFormat = "encrypted"
+)
+
+// zipSignature ZIP 压缩包（OOXML、ODS）的文件头
+var zipSignature = []byte("PK\x03\x04")
+
+// spreadsheetMLFormats 工作簿主部件的内容类型 → 格式
+var spreadsheetMLFormats = map[string]WorkbookFormat{
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml":    FormatXLSX,
+	"application/vnd.ms-excel.sheet.macroEnabled.main+xml":                          FormatXLSM,
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.template.main+xml": FormatXLTX,
+	"application/vnd.ms-excel.template.macroEnabled.main+xml":                       FormatXLTM,
+	"application/vnd.ms-excel.sheet.binary.macroEnabled.main":                       FormatXLSB,
+}
+
+// UnsupportedFormatError 工作簿格式无法识别或不受支持（如 .xlsb）
+type UnsupportedFormatError struct {
+	// 识别出的格式，无法识别时为 FormatUnknown
+	Format WorkbookFormat
+}
+
+func (e *UnsupportedFormatError) Error() string {
+	if e.Format == FormatUnknown {
+		return "无法识别的工作簿格式（支持 xlsx、xlsm、xltx、xltm、xls 与 ods）"
+	}
+	return fmt.Sprintf("不支持的工作簿格式: %s", e.Format)
+}
+
+// spreadsheetML 是否为 excelize 可直接打开的 OOXML 工作簿（不含加密的工作簿）
+func (f WorkbookFormat) spreadsheetML() bool {
+	switch f {
+	case FormatXLSX, FormatXLSM, FormatXLTX, FormatXLTM:
+		return true
+	}
+	return false
+}
+
+// DetectFormat 按内容识别工作簿格式：ZIP 压缩包根据 [Content_Types].xml 或 ODS 的 mimetype 区分，
+// OLE 复合文档根据其中的流区分加密的 OOXML 工作簿与 .xls；文本等其他内容返回 FormatUnknown
+func DetectFormat(data []byte) WorkbookFormat {
+	switch {
+	case bytes.HasPrefix(data, zipSignature):
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return FormatUnknown
+		}
+		return detectZipFormat(zr)
+	case bytes.HasPrefix(data, oleSignature):
+		return detectCFBFormat(data)
+	}
+	return FormatUnknown
+}
+
+// detectZipFormat 识别压缩包中的工作簿格式
+func detectZipFormat(zr *zip.Reader) WorkbookFormat {
+	parts := make(map[string]*zip.File, len(zr.File))
+	for _, zf := range zr.File {
+		parts[strings.ToLower(zf.Name)] = zf
+	}
+	if data := readZipFile(parts["[content_types].xml"]); data != nil {
+		var types struct {
+			Overrides []struct {
+				ContentType string `xml:",attr"`
+			} `xml:"Override"`
+		}
+		if xml.Unmarshal(data, &types) == nil {
+			for _, o := range types.Overrides {
+				if format, ok := spreadsheetMLFormats[o.ContentType]; ok {
+					return format
+				}
+			}
+		}
+	}
+	if mime := readZipFile(parts["mimetype"]); strings.TrimSpace(string(mime)) == odsMimeType {
+		return FormatODS
+	}
+	return FormatUnknown
+}
+
+// detectCFBFormat 识别复合文档中的工作簿格式
+func detectCFBFormat(data []byte) WorkbookFormat {
+	if isEncryptedWorkbook(data) {
+		return FormatEncrypted
+	}
+	c, err := openCFB(data)
+	if err != nil {
+		return FormatUnknown
+	}
+	for _, e := range c.entries {
+		// BIFF5 及更早版本的流名为 Book，打开时返回版本不受支持的错误
+		if e.typ == cfbStreamObject && (strings.EqualFold(e.name, "Workbook") || strings.EqualFold(e.name, "Book")) {
+			return FormatXLS
+		}
+	}
+	return FormatUnknown
+}
+
+// detectFileFormat 识别文件中的 ZIP 工作簿格式，只读取压缩包目录与 [Content_Types].xml；
+// 不是 ZIP 压缩包时返回 FormatUnknown，由调用方读取完整内容后识别
+func detectFileFormat(path string) (WorkbookFormat, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		if err == zip.ErrFormat {
+			return FormatUnknown, nil
+		}
+		return FormatUnknown, err
+	}
+	defer zr.Close()
+	return detectZipFormat(&zr.Reader), nil
+}
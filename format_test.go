@@ -0,0 +1,165 @@
+package excelsnapshot
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap/zaptest"
+)
+
+// createTestWorkbookFormat 创建测试工作簿，并将工作簿主部件的内容类型改为 contentType（为空时保持 xlsx）
+func createTestWorkbookFormat(t *testing.T, contentType string) []byte {
+	t.Helper()
+	f := excelize.NewFile()
+	defer f.Close()
+	f.SetCellValue("Sheet1", "A1", "format")
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		t.Fatalf("写入工作簿失败: %v", err)
+	}
+	if contentType == "" {
+		return buf.Bytes()
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("读取工作簿失败: %v", err)
+	}
+	var out bytes.Buffer
+	zw := zip.NewWriter(&out)
+	for _, zf := range zr.File {
+		data := readZipFile(zf)
+		if zf.Name == "[Content_Types].xml" {
+			data = []byte(strings.Replace(string(data),
+				"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml", contentType, 1))
+		}
+		w, err := zw.Create(zf.Name)
+		if err != nil {
+			t.Fatalf("写入工作簿失败: %v", err)
+		}
+		w.Write(data)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("写入工作簿失败: %v", err)
+	}
+	return out.Bytes()
+}
+
+// TestDetectFormat 测试按内容识别工作簿格式
+func TestDetectFormat(t *testing.T) {
+	encrypted := filepath.Join(t.TempDir(), "encrypted.xlsx")
+	if err := createEncryptedTestExcel(encrypted, "secret"); err != nil {
+		t.Fatalf("创建加密工作簿失败: %v", err)
+	}
+	encryptedData, err := os.ReadFile(encrypted)
+	if err != nil {
+		t.Fatalf("读取加密工作簿失败: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		want WorkbookFormat
+	}{
+		{"xlsx", createTestWorkbookFormat(t, ""), FormatXLSX},
+		{"xlsm", createTestWorkbookFormat(t, "application/vnd.ms-excel.sheet.macroEnabled.main+xml"), FormatXLSM},
+		{"xltx", createTestWorkbookFormat(t, "application/vnd.openxmlformats-officedocument.spreadsheetml.template.main+xml"), FormatXLTX},
+		{"xltm", createTestWorkbookFormat(t, "application/vnd.ms-excel.template.macroEnabled.main+xml"), FormatXLTM},
+		{"xlsb", createTestWorkbookFormat(t, "application/vnd.ms-excel.sheet.binary.macroEnabled.main"), FormatXLSB},
+		{"其他 OOXML 文档", createTestWorkbookFormat(t, "application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"), FormatUnknown},
+		{"ods", createTestODS(t, testODSContent), FormatODS},
+		{"xls", createTestXLS(t), FormatXLS},
+		{"加密的工作簿", encryptedData, FormatEncrypted},
+		{"其他复合文档", createTestCFB(t, "WordDocument", []byte("doc")), FormatUnknown},
+		{"文本", []byte("a,b\n1,2\n"), FormatUnknown},
+		{"损坏的压缩包", []byte("PK\x03\x04broken"), FormatUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectFormat(tt.data); got != tt.want {
+				t.Errorf("DetectFormat() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestNewExcel_Format 测试按内容而非扩展名打开各格式的工作簿
+func TestNewExcel_Format(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	dir := t.TempDir()
+	tests := []struct {
+		name string
+		// 文件扩展名与内容不一致，确认不依赖扩展名
+		file string
+		data []byte
+		want WorkbookFormat
+	}{
+		{"xlsm", "macro.xlsx", createTestWorkbookFormat(t, "application/vnd.ms-excel.sheet.macroEnabled.main+xml"), FormatXLSM},
+		{"xltx", "template.xlsx", createTestWorkbookFormat(t, "application/vnd.openxmlformats-officedocument.spreadsheetml.template.main+xml"), FormatXLTX},
+		{"xltm", "template.xlsm", createTestWorkbookFormat(t, "application/vnd.ms-excel.template.macroEnabled.main+xml"), FormatXLTM},
+		{"ods", "partner.xlsx", createTestODS(t, testODSContent), FormatODS},
+		{"xls", "legacy.bin", createTestXLS(t), FormatXLS},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			if err := os.WriteFile(path, tt.data, 0o644); err != nil {
+				t.Fatalf("写入测试文件失败: %v", err)
+			}
+			for _, open := range []func() (*Excel, error){
+				func() (*Excel, error) { return NewExcel(path, logger) },
+				func() (*Excel, error) { return NewExcelFromBytes(tt.data, logger) },
+			} {
+				excel, err := open()
+				if err != nil {
+					t.Fatalf("打开工作簿失败: %v", err)
+				}
+				if excel.Format() != tt.want {
+					t.Errorf("Format() = %q, want %q", excel.Format(), tt.want)
+				}
+				if excel.GetSheetNameByIndex(0) == "" {
+					t.Error("工作表列表为空")
+				}
+				excel.Close()
+			}
+		})
+	}
+}
+
+// TestNewExcel_UnsupportedFormat 测试不支持的格式返回 UnsupportedFormatError
+func TestNewExcel_UnsupportedFormat(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	xlsb := createTestWorkbookFormat(t, "application/vnd.ms-excel.sheet.binary.macroEnabled.main")
+	path := filepath.Join(t.TempDir(), "binary.xlsb")
+	if err := os.WriteFile(path, xlsb, 0o644); err != nil {
+		t.Fatalf("写入测试文件失败: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		open func() (*Excel, error)
+		want WorkbookFormat
+	}{
+		{"xlsb 文件", func() (*Excel, error) { return NewExcel(path, logger) }, FormatXLSB},
+		{"xlsb 字节", func() (*Excel, error) { return NewExcelFromBytes(xlsb, logger) }, FormatXLSB},
+		{"文本", func() (*Excel, error) { return NewExcelFromReader(strings.NewReader("a,b\n1,2\n"), logger) }, FormatUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.open()
+			var fe *UnsupportedFormatError
+			if !errors.As(err, &fe) {
+				t.Fatalf("错误类型 = %T (%v), want *UnsupportedFormatError", err, err)
+			}
+			if fe.Format != tt.want {
+				t.Errorf("UnsupportedFormatError.Format = %q, want %q", fe.Format, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package excelsnapshot
+
+import (
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// 日志中最多列出的计算失败单元格数量
+const maxFormulaFailuresLogged = 20
+
+// excelErrorValues Excel 的错误值
+var excelErrorValues = map[string]bool{
+	"#NULL!": true, "#DIV/0!": true, "#VALUE!": true, "#REF!": true, "#NAME?": true,
+	"#NUM!": true, "#N/A": true, "#GETTING_DATA": true, "#SPILL!": true, "#CALC!": true,
+	"#FIELD!": true, "#BLOCKED!": true, "#CONNECT!": true, "#BUSY!": true, "#UNKNOWN!": true,
+}
+
+// isExcelError 判断文本是否为 Excel 错误值
+func isExcelError(v string) bool {
+	return excelErrorValues[strings.TrimSpace(v)]
+}
+
+// loadFormulas 读取所有单元格的公式文本
+func (s *Sheet) loadFormulas() error {
+	return s.cells.each(func(cell *Cell) error {
+		formula, err := s.excel.file.GetCellFormula(s.Name, cell.Address)
+		if err != nil {
+			return err
+		}
+		cell.Formula = formula
+		return nil
+	})
+}
+
+// evaluateFormulas 对值为空的公式单元格调用 CalcCellValue 计算结果
+// 计算失败时：若返回 Excel 错误值（如 #DIV/0!）则显示该错误值，否则保持为空，并在最后汇总记录
+// 计算单个公式可能较慢，每个公式之前检查加载是否已取消或超时
+func (s *Sheet) evaluateFormulas() error {
+	evaluated := 0
+	var failed []string
+
+	err := s.cells.each(func(cell *Cell) error {
+		addr := cell.Address
+		if cell.Value != "" || cell.Formula == "" {
+			return nil
+		}
+		if err := s.guard.err(); err != nil {
+			return err
+		}
+		result, err := s.excel.file.CalcCellValue(s.Name, addr)
+		if err != nil {
+			// excelize 对公式错误可能通过 result 或 err 返回错误值
+			if isExcelError(result) {
+				cell.Value = result
+			} else if msg := err.Error(); isExcelError(msg) {
+				cell.Value = msg
+			}
+			failed = append(failed, addr)
+			s.excel.logger.Debug("公式计算失败", zap.String("sheet", s.Name), zap.String("cell", addr), zap.String("formula", cell.Formula), zap.Error(err))
+			return nil
+		}
+		cell.Value = result
+		evaluated++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		sample := failed
+		if len(sample) > maxFormulaFailuresLogged {
+			sample = sample[:maxFormulaFailuresLogged]
+		}
+		s.excel.logger.Warn("部分公式无法计算",
+			zap.String("sheet", s.Name),
+			zap.Int("evaluated", evaluated),
+			zap.Int("failed", len(failed)),
+			zap.Strings("cells", sample))
+		return nil
+	}
+	s.excel.logger.Debug("公式计算完成", zap.String("sheet", s.Name), zap.Int("evaluated", evaluated))
+	return nil
+}
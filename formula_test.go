@@ -0,0 +1,142 @@
+package excelsnapshot
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap/zaptest"
+)
+
+// createTestExcelWithFormulas 创建仅含公式、没有缓存结果的测试Excel文件
+func createTestExcelWithFormulas(filename string) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	f.SetCellValue("Sheet1", "A1", 1)
+	f.SetCellValue("Sheet1", "A2", 2)
+	f.SetCellFormula("Sheet1", "A3", "SUM(A1:A2)")
+	f.SetCellFormula("Sheet1", "A4", "A1/0")
+	f.SetCellFormula("Sheet1", "B3", "A3*10")
+
+	return f.SaveAs(filename)
+}
+
+// TestSheet_EvaluateFormulas 测试缺少缓存值时的公式计算
+func TestSheet_EvaluateFormulas(t *testing.T) {
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "formula.xlsx")
+	if err := createTestExcelWithFormulas(testFile); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+
+	logger := zaptest.NewLogger(t)
+
+	tests := []struct {
+		name string
+		opts []ExcelOption
+		want map[string]string
+	}{
+		{
+			name: "默认不计算",
+			want: map[string]string{"A3": "", "A4": "", "B3": ""},
+		},
+		{
+			name: "开启公式计算",
+			opts: []ExcelOption{WithFormulaEvaluation(true)},
+			want: map[string]string{"A3": "3", "A4": "#DIV/0!", "B3": "30"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			excel, err := NewExcel(testFile, logger, tt.opts...)
+			if err != nil {
+				t.Fatalf("加载Excel文件失败: %v", err)
+			}
+			defer excel.Close()
+
+			sheet, err := excel.GetSheet("Sheet1")
+			if err != nil {
+				t.Fatalf("获取工作表失败: %v", err)
+			}
+			for addr, want := range tt.want {
+				if got := sheet.cells.get(addr).String(); got != want {
+					t.Errorf("%s = %q, want %q", addr, got, want)
+				}
+			}
+		})
+	}
+}
+
+// TestSheet_LoadFormulas 测试公式文本加载
+func TestSheet_LoadFormulas(t *testing.T) {
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "formula.xlsx")
+	if err := createTestExcelWithFormulas(testFile); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+
+	logger := zaptest.NewLogger(t)
+	excel, err := NewExcel(testFile, logger)
+	if err != nil {
+		t.Fatalf("加载Excel文件失败: %v", err)
+	}
+	defer excel.Close()
+
+	sheet, err := excel.GetSheet("Sheet1")
+	if err != nil {
+		t.Fatalf("获取工作表失败: %v", err)
+	}
+
+	if got := sheet.cells.get("A3").Formula; got != "SUM(A1:A2)" {
+		t.Errorf("A3.Formula = %q, want %q", got, "SUM(A1:A2)")
+	}
+	if sheet.cells.get("A1").HasFormula() {
+		t.Error("A1 不应包含公式")
+	}
+}
+
+// TestSheetRenderer_FormulaModes 测试显示公式模式与错误提示三角
+func TestSheetRenderer_FormulaModes(t *testing.T) {
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "formula.xlsx")
+	if err := createTestExcelWithFormulas(testFile); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+
+	logger := zaptest.NewLogger(t)
+	excel, err := NewExcel(testFile, logger, WithFormulaEvaluation(true))
+	if err != nil {
+		t.Fatalf("加载Excel文件失败: %v", err)
+	}
+	defer excel.Close()
+
+	sheet, err := excel.GetSheet("Sheet1")
+	if err != nil {
+		t.Fatalf("获取工作表失败: %v", err)
+	}
+
+	plain := NewSheetRenderer(logger)
+	if got := plain.displayText(sheet.cells.get("A3")); got != "3" {
+		t.Errorf("displayText(A3) = %q, want %q", got, "3")
+	}
+	formulas := NewSheetRenderer(logger, WithShowFormulas(true))
+	if got := formulas.displayText(sheet.cells.get("A3")); got != "=SUM(A1:A2)" {
+		t.Errorf("显示公式模式 displayText(A3) = %q, want %q", got, "=SUM(A1:A2)")
+	}
+	if got := formulas.displayText(sheet.cells.get("A1")); got != "1" {
+		t.Errorf("显示公式模式 displayText(A1) = %q, want %q", got, "1")
+	}
+
+	renderer := NewSheetRenderer(logger, WithErrorIndicators(true))
+	img, err := renderer.RenderSheet(sheet)
+	if err != nil {
+		t.Fatalf("RenderSheet() 失败: %v", err)
+	}
+	rect := renderer.calculateCellRects(sheet)["A4"]
+	r, g, b, _ := img.At(int(rect.x*renderer.scale)+3, int(rect.y*renderer.scale)+3).RGBA()
+	if r>>8 != 0 || g>>8 != 128 || b>>8 != 0 {
+		t.Errorf("A4 左上角应为绿色错误提示三角, got (%d,%d,%d)", r>>8, g>>8, b>>8)
+	}
+}
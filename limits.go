@@ -0,0 +1,152 @@
+package excelsnapshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Limits 加载与渲染的资源限制，零值字段表示不限制
+// 用于防止恶意上传的工作簿耗尽服务的内存与时间；超出限制时返回 *LimitError
+type Limits struct {
+	// 单个工作表加载的最大单元格数（含补齐的空单元格与合并区域内的单元格）
+	MaxCells int
+	// 输出画布的最大像素数（宽×高）；按尺寸自动缩小请使用 WithMaxSize
+	MaxPixels int64
+	// 单张嵌入图片的最大字节数，同时限制压缩数据与解码后的 RGBA 数据（防止解压炸弹）
+	MaxImageBytes int64
+	// 单个工作表加载或单次渲染的最长耗时
+	MaxDuration time.Duration
+}
+
+// LimitKind 超出的资源限制类型
+type LimitKind string
+
+// 资源限制类型
+const (
+	LimitCells      LimitKind = "cells"
+	LimitPixels     LimitKind = "pixels"
+	LimitImageBytes LimitKind = "image_bytes"
+	LimitDuration   LimitKind = "duration"
+)
+
+// LimitError 超出资源限制；耗时超限时可通过 errors.Is(err, context.DeadlineExceeded) 判断
+type LimitError struct {
+	Kind  LimitKind
+	Sheet string
+	// 限制值与实际值（耗时为纳秒，超时时实际值未知，为 0）
+	Limit, Actual int64
+}
+
+func (e *LimitError) Error() string {
+	switch e.Kind {
+	case LimitDuration:
+		return fmt.Sprintf("工作表 %s 超出耗时限制 %s", e.Sheet, time.Duration(e.Limit))
+	case LimitPixels:
+		return fmt.Sprintf("工作表 %s 的画布为 %d 像素，超出限制 %d", e.Sheet, e.Actual, e.Limit)
+	case LimitImageBytes:
+		return fmt.Sprintf("工作表 %s 的图片为 %d 字节，超出限制 %d", e.Sheet, e.Actual, e.Limit)
+	}
+	return fmt.Sprintf("工作表 %s 的单元格数 %d 超出限制 %d", e.Sheet, e.Actual, e.Limit)
+}
+
+// Unwrap 耗时超限时返回 context.DeadlineExceeded
+func (e *LimitError) Unwrap() error {
+	if e.Kind == LimitDuration {
+		return context.DeadlineExceeded
+	}
+	return nil
+}
+
+// withTimeout 按 MaxDuration 为 ctx 设置超时，超时的原因为 LimitError
+func (l Limits) withTimeout(ctx context.Context, sheet string) (context.Context, context.CancelFunc) {
+	if l.MaxDuration <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeoutCause(ctx, l.MaxDuration, &LimitError{Kind: LimitDuration, Sheet: sheet, Limit: int64(l.MaxDuration)})
+}
+
+// checkPixels 检查画布像素数
+func (l Limits) checkPixels(sheet string, width, height int) error {
+	if pixels := int64(width) * int64(height); l.MaxPixels > 0 && pixels > l.MaxPixels {
+		return &LimitError{Kind: LimitPixels, Sheet: sheet, Limit: l.MaxPixels, Actual: pixels}
+	}
+	return nil
+}
+
+// checkImageBytes 检查图片的字节数
+func (l Limits) checkImageBytes(sheet string, n int64) error {
+	if l.MaxImageBytes > 0 && n > l.MaxImageBytes {
+		return &LimitError{Kind: LimitImageBytes, Sheet: sheet, Limit: l.MaxImageBytes, Actual: n}
+	}
+	return nil
+}
+
+// contextError 返回 ctx 结束的原因：MaxDuration 超时时为 *LimitError，否则为 ctx.Err()
+func contextError(ctx context.Context) error {
+	if ctx.Err() == nil {
+		return nil
+	}
+	var le *LimitError
+	if cause := context.Cause(ctx); errors.As(cause, &le) {
+		return cause
+	}
+	return ctx.Err()
+}
+
+// isAbort 是否为应中止加载的错误（取消、超时或超出资源限制），而非可以忽略的局部错误
+func isAbort(err error) bool {
+	var le *LimitError
+	return errors.As(err, &le) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// guardInterval 热循环中每处理多少个单元格检查一次 ctx，避免频繁加锁
+const guardInterval = 1024
+
+// loadGuard 工作表加载期间的取消与单元格数量检查
+type loadGuard struct {
+	ctx      context.Context
+	sheet    string
+	maxCells int
+	steps    int
+}
+
+// newGuard 创建加载工作表 sheet 时的检查，ctx 按 MaxDuration 设置超时；用完后须调用返回的 cancel
+func (l Limits) newGuard(ctx context.Context, sheet string) (*loadGuard, context.CancelFunc) {
+	ctx, cancel := l.withTimeout(ctx, sheet)
+	return &loadGuard{ctx: ctx, sheet: sheet, maxCells: l.MaxCells}, cancel
+}
+
+// step 处理单元格的循环中每次迭代调用，cells 为当前的单元格数
+func (g *loadGuard) step(cells int) error {
+	if g == nil {
+		return nil
+	}
+	if g.maxCells > 0 && cells > g.maxCells {
+		return &LimitError{Kind: LimitCells, Sheet: g.sheet, Limit: int64(g.maxCells), Actual: int64(cells)}
+	}
+	if g.steps++; g.steps%guardInterval != 0 {
+		return nil
+	}
+	return contextError(g.ctx)
+}
+
+// reserve 在新建 rng 内的单元格之前检查区域大小，避免超大区域（如整表合并）先分配再报错
+func (g *loadGuard) reserve(rng cellRange) error {
+	if g == nil || g.maxCells <= 0 {
+		return nil
+	}
+	if area := int64(rng.EndRow-rng.StartRow+1) * int64(rng.EndCol-rng.StartCol+1); area > int64(g.maxCells) {
+		return &LimitError{Kind: LimitCells, Sheet: g.sheet, Limit: int64(g.maxCells), Actual: area}
+	}
+	return nil
+}
+
+// err 立即检查 ctx
+func (g *loadGuard) err() error {
+	if g == nil {
+		return nil
+	}
+	return contextError(g.ctx)
+}
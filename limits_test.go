@@ -0,0 +1,268 @@
+package excelsnapshot
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap/zaptest"
+)
+
+// createTestLimitsWorkbook 创建包含 rows×cols 个单元格、一个大范围合并区域与一张嵌入图片的测试工作簿
+func createTestLimitsWorkbook(t *testing.T, rows, cols int) []byte {
+	t.Helper()
+	f := excelize.NewFile()
+	defer f.Close()
+	for r := 1; r <= rows; r++ {
+		for c := 1; c <= cols; c++ {
+			cell, _ := excelize.CoordinatesToCellName(c, r)
+			f.SetCellValue("Sheet1", cell, r*c)
+		}
+	}
+
+	f.NewSheet("合并")
+	f.SetCellValue("合并", "A1", "标题")
+	f.MergeCell("合并", "A1", "Z1000")
+
+	// 图片按单元格查找，锚点单元格需在已用区域内
+	f.NewSheet("图片")
+	f.SetCellValue("图片", "C3", "图片")
+	img := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	for i := range img.Pix {
+		img.Pix[i] = byte(i)
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("编码图片失败: %v", err)
+	}
+	if err := f.AddPictureFromBytes("图片", "B2", &excelize.Picture{Extension: ".png", File: buf.Bytes()}); err != nil {
+		t.Fatalf("添加图片失败: %v", err)
+	}
+
+	out, err := f.WriteToBuffer()
+	if err != nil {
+		t.Fatalf("写入工作簿失败: %v", err)
+	}
+	return out.Bytes()
+}
+
+// TestLimits_Load 测试加载工作表时的单元格与图片限制
+func TestLimits_Load(t *testing.T) {
+	data := createTestLimitsWorkbook(t, 40, 10)
+	tests := []struct {
+		name      string
+		sheet     string
+		limits    Limits
+		streaming bool
+		wantKind  LimitKind
+	}{
+		{"单元格数", "Sheet1", Limits{MaxCells: 100}, false, LimitCells},
+		{"流式加载的单元格数", "Sheet1", Limits{MaxCells: 100}, true, LimitCells},
+		{"合并区域", "合并", Limits{MaxCells: 10000}, false, LimitCells},
+		{"流式加载的合并区域", "合并", Limits{MaxCells: 10000}, true, LimitCells},
+		{"图片字节数", "图片", Limits{MaxImageBytes: 1024}, false, LimitImageBytes},
+		{"未超出限制", "Sheet1", Limits{MaxCells: 1000, MaxImageBytes: 1 << 20}, false, ""},
+		{"未超出限制的图片", "图片", Limits{MaxImageBytes: 1 << 20}, false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			excel, err := NewExcelFromBytes(data, zaptest.NewLogger(t), WithLoadLimits(tt.limits), WithStreaming(tt.streaming))
+			if err != nil {
+				t.Fatalf("加载Excel失败: %v", err)
+			}
+			defer excel.Close()
+
+			_, err = excel.GetSheet(tt.sheet)
+			if tt.wantKind == "" {
+				if err != nil {
+					t.Fatalf("GetSheet() 失败: %v", err)
+				}
+				return
+			}
+			var le *LimitError
+			if !errors.As(err, &le) {
+				t.Fatalf("GetSheet() error = %v, want *LimitError", err)
+			}
+			if le.Kind != tt.wantKind || le.Sheet != tt.sheet || le.Actual <= le.Limit {
+				t.Errorf("LimitError = %+v, want Kind %s", le, tt.wantKind)
+			}
+			if errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("%s 限制不应匹配 context.DeadlineExceeded", tt.wantKind)
+			}
+		})
+	}
+}
+
+// TestLimits_Cancel 测试取消与超时：返回的错误可被识别，失败的加载不被缓存
+func TestLimits_Cancel(t *testing.T) {
+	data := createTestLimitsWorkbook(t, 40, 10)
+	excel, err := NewExcelFromBytes(data, zaptest.NewLogger(t))
+	if err != nil {
+		t.Fatalf("加载Excel失败: %v", err)
+	}
+	defer excel.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := excel.GetSheetContext(ctx, "Sheet1"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("GetSheetContext() error = %v, want context.Canceled", err)
+	}
+	if _, err := excel.GetSheetRangeContext(ctx, "Sheet1", "A1:C3"); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetSheetRangeContext() error = %v, want context.Canceled", err)
+	}
+	sheet, err := excel.GetSheet("Sheet1")
+	if err != nil {
+		t.Fatalf("取消后重新加载失败: %v", err)
+	}
+
+	renderer := NewSheetRenderer(zaptest.NewLogger(t))
+	if _, err := renderer.RenderSheetContext(ctx, sheet); !errors.Is(err, context.Canceled) {
+		t.Errorf("RenderSheetContext() error = %v, want context.Canceled", err)
+	}
+	tiles := 0
+	if _, err := renderer.RenderTilesContext(ctx, sheet, 64, func(Tile, image.Image) error {
+		tiles++
+		return nil
+	}); !errors.Is(err, context.Canceled) {
+		t.Errorf("RenderTilesContext() error = %v, want context.Canceled", err)
+	}
+	if _, err := renderer.RenderTilePyramidContext(ctx, sheet, 64, func(Tile, image.Image) error {
+		tiles++
+		return nil
+	}); !errors.Is(err, context.Canceled) {
+		t.Errorf("RenderTilePyramidContext() error = %v, want context.Canceled", err)
+	}
+	if tiles != 0 {
+		t.Errorf("取消后不应渲染图块, 渲染了 %d 个", tiles)
+	}
+
+	// 极短的 MaxDuration 在开始时即已超时
+	renderer = NewSheetRenderer(zaptest.NewLogger(t), WithRenderLimits(Limits{MaxDuration: time.Nanosecond}))
+	_, err = renderer.RenderSheet(sheet)
+	var le *LimitError
+	if !errors.As(err, &le) || le.Kind != LimitDuration {
+		t.Fatalf("RenderSheet() error = %v, want 耗时超限的 *LimitError", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("耗时超限应匹配 context.DeadlineExceeded")
+	}
+
+	excel2, err := NewExcelFromBytes(data, zaptest.NewLogger(t), WithLoadLimits(Limits{MaxDuration: time.Nanosecond}))
+	if err != nil {
+		t.Fatalf("加载Excel失败: %v", err)
+	}
+	defer excel2.Close()
+	if _, err := excel2.GetSheet("Sheet1"); !errors.As(err, &le) || le.Kind != LimitDuration {
+		t.Errorf("GetSheet() error = %v, want 耗时超限的 *LimitError", err)
+	}
+}
+
+// TestLimits_Convert 测试转换 ODS、xls 与 CSV 时的单元格限制与取消
+func TestLimits_Convert(t *testing.T) {
+	ods := createTestODS(t, testODSContent)
+	xls := createTestXLS(t)
+	csv := []byte("a,b,c\n1,2,3\n4,5,6\n")
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name string
+		open func(ctx context.Context, opts ...ExcelOption) (*Excel, error)
+	}{
+		{"ODS", func(ctx context.Context, opts ...ExcelOption) (*Excel, error) {
+			return NewExcelFromODSContext(ctx, bytes.NewReader(ods), zaptest.NewLogger(t), opts...)
+		}},
+		{"按内容识别的 ODS", func(ctx context.Context, opts ...ExcelOption) (*Excel, error) {
+			return NewExcelFromBytesContext(ctx, ods, zaptest.NewLogger(t), opts...)
+		}},
+		{"xls", func(ctx context.Context, opts ...ExcelOption) (*Excel, error) {
+			return NewExcelFromXLSContext(ctx, bytes.NewReader(xls), zaptest.NewLogger(t), opts...)
+		}},
+		{"按内容识别的 xls", func(ctx context.Context, opts ...ExcelOption) (*Excel, error) {
+			return NewExcelFromReaderContext(ctx, bytes.NewReader(xls), zaptest.NewLogger(t), opts...)
+		}},
+		{"CSV", func(ctx context.Context, opts ...ExcelOption) (*Excel, error) {
+			return NewExcelFromCSVContext(ctx, bytes.NewReader(csv), zaptest.NewLogger(t), CSVOptions{}, opts...)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			excel, err := tt.open(context.Background(), WithLoadLimits(Limits{MaxCells: 1000}))
+			if err != nil {
+				t.Fatalf("限制以内的转换失败: %v", err)
+			}
+			excel.Close()
+
+			_, err = tt.open(context.Background(), WithLoadLimits(Limits{MaxCells: 3}))
+			var le *LimitError
+			if !errors.As(err, &le) || le.Kind != LimitCells || le.Sheet == "" {
+				t.Errorf("error = %v, want 单元格数超限的 *LimitError", err)
+			}
+			if _, err := tt.open(canceled); !errors.Is(err, context.Canceled) {
+				t.Errorf("error = %v, want context.Canceled", err)
+			}
+		})
+	}
+}
+
+// TestLimits_Pixels 测试渲染画布的像素限制
+func TestLimits_Pixels(t *testing.T) {
+	excel, err := NewExcelFromBytes(createTestLimitsWorkbook(t, 40, 10), zaptest.NewLogger(t))
+	if err != nil {
+		t.Fatalf("加载Excel失败: %v", err)
+	}
+	defer excel.Close()
+	sheet, err := excel.GetSheet("Sheet1")
+	if err != nil {
+		t.Fatalf("GetSheet() 失败: %v", err)
+	}
+
+	renderer := NewSheetRenderer(zaptest.NewLogger(t), WithRenderLimits(Limits{MaxPixels: 10000}))
+	_, err = renderer.RenderSheet(sheet)
+	var le *LimitError
+	if !errors.As(err, &le) || le.Kind != LimitPixels || le.Actual <= le.Limit {
+		t.Fatalf("RenderSheet() error = %v, want 像素超限的 *LimitError", err)
+	}
+
+	tiles := 0
+	_, err = renderer.RenderTiles(sheet, 64, func(Tile, image.Image) error {
+		tiles++
+		return nil
+	})
+	if !errors.As(err, &le) || le.Kind != LimitPixels {
+		t.Errorf("RenderTiles() error = %v, want 像素超限的 *LimitError", err)
+	}
+	if tiles != 0 {
+		t.Errorf("超出限制时不应渲染图块, 渲染了 %d 个", tiles)
+	}
+
+	// 按次传入的资源限制只作用于本次渲染
+	_, err = NewSheetRenderer(zaptest.NewLogger(t)).RenderTilePyramid(sheet, 64, func(Tile, image.Image) error {
+		tiles++
+		return nil
+	}, WithRenderLimits(Limits{MaxPixels: 10000}))
+	if !errors.As(err, &le) || le.Kind != LimitPixels {
+		t.Errorf("RenderTilePyramid() error = %v, want 像素超限的 *LimitError", err)
+	}
+	if tiles != 0 {
+		t.Errorf("超出限制时不应渲染图块, 渲染了 %d 个", tiles)
+	}
+	if _, err := renderer.RenderTiles(sheet, 64, func(Tile, image.Image) error { return nil }, WithRenderLimits(Limits{})); err != nil {
+		t.Errorf("按次取消限制后 RenderTiles() 失败: %v", err)
+	}
+
+	renderer = NewSheetRenderer(zaptest.NewLogger(t), WithRenderLimits(Limits{MaxPixels: 1 << 30}))
+	img, err := renderer.RenderSheet(sheet)
+	if err != nil {
+		t.Fatalf("RenderSheet() 失败: %v", err)
+	}
+	if c := color.RGBAModel.Convert(img.At(0, 0)).(color.RGBA); c.A == 0 {
+		t.Errorf("渲染结果为空")
+	}
+}
@@ -1,6 +1,9 @@
 package excelsnapshot
 
 import (
+	"context"
+	"log/slog"
+
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -23,3 +26,77 @@ func SetupLogger(name string, level zapcore.Level, isDev bool) (*zap.Logger, fun
 	logger = logger.Named(name)
 	return logger, func() { _ = logger.Sync() }, nil
 }
+
+// orNop 将 nil 替换为不输出任何日志的 Logger，使调用方可以不提供日志
+func orNop(logger *zap.Logger) *zap.Logger {
+	if logger == nil {
+		return zap.NewNop()
+	}
+	return logger
+}
+
+// NewSlogLogger 返回将日志转发到 slog.Handler 的 *zap.Logger，使用 log/slog 的调用方可将其传给 NewExcel 与 NewSheetRenderer，
+// 无需配置 zap。h 为 nil 时返回不输出任何日志的 Logger
+func NewSlogLogger(h slog.Handler) *zap.Logger {
+	if h == nil {
+		return zap.NewNop()
+	}
+	return zap.New(&slogCore{handler: h})
+}
+
+// slogCore 将 zap 的日志条目转换为 slog.Record 的 zapcore.Core
+type slogCore struct {
+	handler slog.Handler
+}
+
+func (c *slogCore) Enabled(level zapcore.Level) bool {
+	return c.handler.Enabled(context.Background(), slogLevel(level))
+}
+
+func (c *slogCore) With(fields []zapcore.Field) zapcore.Core {
+	return &slogCore{handler: c.handler.WithAttrs(slogAttrs(fields))}
+}
+
+func (c *slogCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *slogCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	r := slog.NewRecord(ent.Time, slogLevel(ent.Level), ent.Message, 0)
+	if ent.LoggerName != "" {
+		r.AddAttrs(slog.String("logger", ent.LoggerName))
+	}
+	r.AddAttrs(slogAttrs(fields)...)
+	return c.handler.Handle(context.Background(), r)
+}
+
+func (c *slogCore) Sync() error { return nil }
+
+// slogLevel 将 zap 的级别对应到 slog 的级别，DPanic 及以上按 Error 输出
+func slogLevel(level zapcore.Level) slog.Level {
+	switch {
+	case level <= zapcore.DebugLevel:
+		return slog.LevelDebug
+	case level == zapcore.InfoLevel:
+		return slog.LevelInfo
+	case level == zapcore.WarnLevel:
+		return slog.LevelWarn
+	}
+	return slog.LevelError
+}
+
+// slogAttrs 按字段顺序将 zap 字段编码为 slog 属性（错误编码为其文本）
+func slogAttrs(fields []zapcore.Field) []slog.Attr {
+	enc := zapcore.NewMapObjectEncoder()
+	attrs := make([]slog.Attr, 0, len(fields))
+	for _, f := range fields {
+		f.AddTo(enc)
+		if v, ok := enc.Fields[f.Key]; ok {
+			attrs = append(attrs, slog.Any(f.Key, v))
+		}
+	}
+	return attrs
+}
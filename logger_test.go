@@ -0,0 +1,151 @@
+package excelsnapshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap"
+)
+
+// TestNilLogger 测试不提供日志时加载与渲染不会 panic
+func TestNilLogger(t *testing.T) {
+	f := excelize.NewFile()
+	f.SetCellValue("Sheet1", "A1", "无日志")
+	buf, err := f.WriteToBuffer()
+	f.Close()
+	if err != nil {
+		t.Fatalf("写入工作簿失败: %v", err)
+	}
+
+	excel, err := NewExcelFromBytes(buf.Bytes(), nil)
+	if err != nil {
+		t.Fatalf("加载Excel失败: %v", err)
+	}
+	defer excel.Close()
+	sheet, err := excel.GetSheet("Sheet1")
+	if err != nil {
+		t.Fatalf("获取工作表失败: %v", err)
+	}
+	if _, err := NewSheetRenderer(nil).RenderSheet(sheet); err != nil {
+		t.Fatalf("RenderSheet() 失败: %v", err)
+	}
+	if _, err := NewExcelFromCSV(strings.NewReader("a,b\n1,2\n"), nil, CSVOptions{}); err != nil {
+		t.Fatalf("NewExcelFromCSV() 失败: %v", err)
+	}
+}
+
+// TestNewSlogLogger 测试日志转发到 slog.Handler：级别、字段与过滤
+func TestNewSlogLogger(t *testing.T) {
+	tests := []struct {
+		name  string
+		level slog.Level
+		log   func(l *zap.Logger)
+		want  []map[string]any
+	}{
+		{
+			"字段",
+			slog.LevelDebug,
+			func(l *zap.Logger) {
+				l.Debug("加载工作表", zap.String("sheet", "Sheet1"), zap.Int("rows", 3), zap.Error(errors.New("失败")))
+			},
+			[]map[string]any{{"level": "DEBUG", "msg": "加载工作表", "sheet": "Sheet1", "rows": float64(3), "error": "失败"}},
+		},
+		{
+			"级别",
+			slog.LevelDebug,
+			func(l *zap.Logger) {
+				l.Info("信息")
+				l.Warn("警告")
+				l.Error("错误")
+			},
+			[]map[string]any{
+				{"level": "INFO", "msg": "信息"},
+				{"level": "WARN", "msg": "警告"},
+				{"level": "ERROR", "msg": "错误"},
+			},
+		},
+		{
+			"按 Handler 的级别过滤",
+			slog.LevelWarn,
+			func(l *zap.Logger) {
+				l.Debug("调试")
+				l.Info("信息")
+				l.Warn("警告")
+			},
+			[]map[string]any{{"level": "WARN", "msg": "警告"}},
+		},
+		{
+			"With 与 Named",
+			slog.LevelDebug,
+			func(l *zap.Logger) {
+				l.Named("render").With(zap.String("sheet", "汇总")).Info("完成")
+			},
+			[]map[string]any{{"level": "INFO", "msg": "完成", "logger": "render", "sheet": "汇总"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			h := slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+				Level: tt.level,
+				// 去掉时间以便比较
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					if a.Key == slog.TimeKey && len(groups) == 0 {
+						return slog.Attr{}
+					}
+					return a
+				},
+			})
+			tt.log(NewSlogLogger(h))
+
+			var got []map[string]any
+			dec := json.NewDecoder(&buf)
+			for dec.More() {
+				var m map[string]any
+				if err := dec.Decode(&m); err != nil {
+					t.Fatalf("解析日志失败: %v", err)
+				}
+				got = append(got, m)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("日志条数 = %d, want %d: %v", len(got), len(tt.want), got)
+			}
+			for i := range tt.want {
+				if len(got[i]) != len(tt.want[i]) {
+					t.Errorf("日志[%d] = %v, want %v", i, got[i], tt.want[i])
+					continue
+				}
+				for k, v := range tt.want[i] {
+					if got[i][k] != v {
+						t.Errorf("日志[%d][%q] = %v, want %v", i, k, got[i][k], v)
+					}
+				}
+			}
+		})
+	}
+
+	// 默认级别（Info）下加载与渲染工作表不输出日志
+	var buf bytes.Buffer
+	logger := NewSlogLogger(slog.NewTextHandler(&buf, nil))
+	excel := createTestBatchExcel(t, 1)
+	excel.logger = logger
+	sheet, err := excel.GetSheet("Sheet1")
+	if err != nil {
+		t.Fatalf("获取工作表失败: %v", err)
+	}
+	if _, err := NewSheetRenderer(logger).RenderSheet(sheet); err != nil {
+		t.Fatalf("RenderSheet() 失败: %v", err)
+	}
+	if buf.Len() > 0 {
+		t.Errorf("默认级别下不应输出日志, got:\n%s", buf.String())
+	}
+
+	if l := NewSlogLogger(nil); l.Core().Enabled(zap.ErrorLevel) {
+		t.Errorf("NewSlogLogger(nil) 应不输出日志")
+	}
+}
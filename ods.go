@@ -0,0 +1,695 @@
+package excelsnapshot
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap"
+)
+
+// odsMimeType ODS 文档 mimetype 文件的内容
+const odsMimeType = "application/vnd.oasis.opendocument.spreadsheet"
+
+// LibreOffice 会以重复的空白单元格/行填充到表尾（或整行整列设置格式），
+// 到达这些行列的重复区域视为填充，不计入内容范围
+const (
+	odsFillerCol = 1024
+	odsFillerRow = 65536
+)
+
+// 没有数字样式的日期、时间单元格使用的格式
+const (
+	odsDefaultDateFormat = "yyyy-mm-dd"
+	odsDefaultTimeFormat = "hh:mm:ss"
+)
+
+// NewExcelFromODS 读取 OpenDocument 电子表格（.ods）并转换为工作簿
+// 支持单元格值、公式文本、合并单元格、列宽、行高、隐藏行列与工作表，以及字体、填充、边框、对齐与数字格式；
+// 图片、图表与条件格式不会被转换
+func NewExcelFromODS(r io.Reader, logger *zap.Logger, opts ...ExcelOption) (*Excel, error) {
+	return NewExcelFromODSContext(context.Background(), r, logger, opts...)
+}
+
+// NewExcelFromODSContext 同 NewExcelFromODS，转换时按 WithLoadLimits 检查每个工作表的单元格数与耗时，ctx 取消时中止
+func NewExcelFromODSContext(ctx context.Context, r io.Reader, logger *zap.Logger, opts ...ExcelOption) (*Excel, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("读取 ODS 失败: %w", err)
+	}
+	excel := newExcel("", logger, opts)
+	excel.format = FormatODS
+	f, err := excel.buildODSWorkbook(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	return excel.init(f)
+}
+
+// odsReader 将 ODS 内容写入 excelize 工作簿
+type odsReader struct {
+	file   *excelize.File
+	styles *odsStyles
+	// 工作簿默认字体的最大数字宽度，用于列宽换算
+	mdw float64
+	// 单元格样式名（及默认数字格式）→ excelize 样式 ID
+	styleIDs map[string]int
+}
+
+// odsTable 读取到的一个工作表
+type odsTable struct {
+	name     string
+	hidden   bool
+	tabColor string
+	cols     []odsColumn
+	rows     []odsRow
+	cells    []odsCell
+	styled   []odsStyledRange
+	merges   [][4]int // 起始行、起始列、结束行、结束列（1-based）
+	// 已读取的行列数
+	row, col int
+	// 读取期间的取消、超时与单元格数量检查
+	guard *loadGuard
+}
+
+// odsColumn 连续的若干列（table:number-columns-repeated）
+type odsColumn struct {
+	first, last int
+	width       float64 // 磅，0 表示未设置
+	hidden      bool
+	cellStyle   string // table:default-cell-style-name
+}
+
+// odsRow 连续的若干行（table:number-rows-repeated）
+type odsRow struct {
+	first, last int
+	height      float64 // 磅，0 表示未设置
+	hidden      bool
+}
+
+// odsCell 有值或公式的单元格
+type odsCell struct {
+	row, col int
+	value    interface{}
+	formula  string
+	style    string
+	// 没有数字样式时使用的格式（日期、时间）
+	fallbackFmt string
+}
+
+// odsStyledRange 只有样式的空白单元格区域
+type odsStyledRange struct {
+	row1, col1, row2, col2 int
+	style                  string
+}
+
+// buildODSWorkbook 解析 ODS 压缩包并构建等价的 excelize 工作簿
+func (e *Excel) buildODSWorkbook(ctx context.Context, data []byte) (*excelize.File, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("无效的 ODS 文件: %w", err)
+	}
+	parts := make(map[string]*zip.File)
+	for _, zf := range zr.File {
+		parts[zf.Name] = zf
+	}
+	if mime := readZipFile(parts["mimetype"]); mime != nil && strings.TrimSpace(string(mime)) != odsMimeType {
+		return nil, fmt.Errorf("不是 ODS 电子表格: %s", mime)
+	}
+	if bytes.Contains(readZipFile(parts["META-INF/manifest.xml"]), []byte("encryption-data")) {
+		return nil, fmt.Errorf("不支持加密的 ODS 文件")
+	}
+	content := parts["content.xml"]
+	if content == nil {
+		return nil, fmt.Errorf("无效的 ODS 文件: 缺少 content.xml")
+	}
+
+	styles := newODSStyles()
+	if data := readZipFile(parts["styles.xml"]); data != nil {
+		if err := styles.parse(xml.NewDecoder(bytes.NewReader(data))); err != nil {
+			return nil, fmt.Errorf("解析 ODS styles.xml 失败: %w", err)
+		}
+	}
+	rc, err := content.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	d := xml.NewDecoder(rc)
+	if err := styles.parse(d); err != nil {
+		return nil, fmt.Errorf("解析 ODS content.xml 失败: %w", err)
+	}
+	tables, err := readODSTables(ctx, d, styles, e.limits)
+	if err != nil {
+		return nil, fmt.Errorf("解析 ODS content.xml 失败: %w", err)
+	}
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("ODS 文件中没有工作表")
+	}
+
+	// 以默认单元格样式的字体作为工作簿默认字体，列宽按其最大数字宽度换算
+	f := excelize.NewFile()
+	o := &odsReader{file: f, styles: styles, styleIDs: make(map[string]int)}
+	defaultFont := textFont{family: "Calibri", size: 11}
+	if family := styles.excelStyle(styles.resolve("table-cell", "Default")).Font.Family; family != "" {
+		defaultFont.family = family
+		f.SetDefaultFont(family)
+	}
+	o.mdw = newFontSet(e.fontRegistry, e.fallbackFonts).maxDigitWidth(defaultFont)
+
+	sheets := make([]*convertedSheet, 0, len(tables))
+	for _, t := range tables {
+		guard, cancel := e.limits.newGuard(ctx, t.name)
+		sheet, err := o.convert(t, guard)
+		cancel()
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("转换工作表 %s 失败: %w", t.name, err)
+		}
+		sheets = append(sheets, sheet)
+	}
+	f, err = e.writeConvertedWorkbook(f, sheets)
+	if err != nil {
+		return nil, err
+	}
+	e.logger.Debug("ODS 加载完成", zap.Int("sheets", len(tables)))
+	return f, nil
+}
+
+// readZipFile 读取压缩包中的文件，不存在或读取失败时返回 nil
+func readZipFile(zf *zip.File) []byte {
+	if zf == nil {
+		return nil
+	}
+	rc, err := zf.Open()
+	if err != nil {
+		return nil
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// readODSTables 读取正文中的所有工作表（table:table），每个工作表按 limits 检查单元格数与耗时
+func readODSTables(ctx context.Context, d *xml.Decoder, styles *odsStyles, limits Limits) ([]*odsTable, error) {
+	var tables []*odsTable
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return tables, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if t, ok := tok.(xml.StartElement); ok && t.Name.Space == odsNSTable && t.Name.Local == "table" {
+			guard, cancel := limits.newGuard(ctx, xmlAttr(t, "name"))
+			table, err := readODSTable(d, t, styles, guard)
+			cancel()
+			if err != nil {
+				return nil, err
+			}
+			table.guard = nil
+			tables = append(tables, table)
+		}
+	}
+}
+
+// readODSTable 读取一个工作表的列、行与单元格
+func readODSTable(d *xml.Decoder, el xml.StartElement, styles *odsStyles, guard *loadGuard) (*odsTable, error) {
+	t := &odsTable{name: xmlAttr(el, "name"), guard: guard}
+	if err := guard.err(); err != nil {
+		return nil, err
+	}
+	ts := styles.resolve("table", xmlAttr(el, "style-name"))
+	t.hidden = ts.layout["display"] == "false"
+	t.tabColor = odsColor(ts.layout["tab-color"])
+
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch tt := tok.(type) {
+		case xml.StartElement:
+			if tt.Name.Space != odsNSTable {
+				if err := d.Skip(); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			switch tt.Name.Local {
+			case "table-column":
+				t.readColumn(tt, styles)
+				if err := d.Skip(); err != nil {
+					return nil, err
+				}
+			case "table-row":
+				if err := t.readRow(d, tt, styles); err != nil {
+					return nil, err
+				}
+			case "table-columns", "table-column-group", "table-header-columns",
+				"table-rows", "table-row-group", "table-header-rows":
+				// 分组元素，继续读取其中的行列
+			default:
+				if err := d.Skip(); err != nil {
+					return nil, err
+				}
+			}
+		case xml.EndElement:
+			if tt.Name.Space == odsNSTable && tt.Name.Local == "table" {
+				return t, nil
+			}
+		}
+	}
+}
+
+// readColumn 记录列宽、隐藏状态与列的默认单元格样式，超出 Excel 最大列数的列被忽略
+func (t *odsTable) readColumn(el xml.StartElement, styles *odsStyles) {
+	n := odsRepeat(el, "number-columns-repeated")
+	col := odsColumn{
+		first:     t.col + 1,
+		last:      min(t.col+n, excelize.MaxColumns),
+		hidden:    xmlAttr(el, "visibility") == "collapse",
+		cellStyle: xmlAttr(el, "default-cell-style-name"),
+	}
+	t.col = col.last
+	if col.first > col.last {
+		return
+	}
+	if w, ok := odsLength(styles.resolve("table-column", xmlAttr(el, "style-name")).layout["column-width"]); ok {
+		col.width = w
+	}
+	t.cols = append(t.cols, col)
+}
+
+// columnStyle 返回列的默认单元格样式
+func (t *odsTable) columnStyle(col int) string {
+	for _, c := range t.cols {
+		if col >= c.first && col <= c.last {
+			return c.cellStyle
+		}
+	}
+	return ""
+}
+
+// readRow 读取一行（可能重复多次）中的单元格，超出 Excel 最大行列数的行与单元格被忽略
+func (t *odsTable) readRow(d *xml.Decoder, el xml.StartElement, styles *odsStyles) error {
+	n := odsRepeat(el, "number-rows-repeated")
+	first, last := t.row+1, min(t.row+n, excelize.TotalRows)
+	t.row = last
+	if first > last {
+		return d.Skip()
+	}
+	row := odsRow{first: first, last: last, hidden: xmlAttr(el, "visibility") != "" && xmlAttr(el, "visibility") != "visible"}
+	if h, ok := odsLength(styles.resolve("table-row", xmlAttr(el, "style-name")).layout["row-height"]); ok {
+		row.height = h
+	}
+	if row.height > 0 || row.hidden {
+		t.rows = append(t.rows, row)
+	}
+	rowStyle := xmlAttr(el, "default-cell-style-name")
+
+	col := 0
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch tt := tok.(type) {
+		case xml.StartElement:
+			if tt.Name.Space != odsNSTable || (tt.Name.Local != "table-cell" && tt.Name.Local != "covered-table-cell") {
+				if err := d.Skip(); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := t.guard.step(len(t.cells)); err != nil {
+				return err
+			}
+			repeat := odsRepeat(tt, "number-columns-repeated")
+			c1, c2 := col+1, min(col+repeat, excelize.MaxColumns)
+			col = c2
+			if tt.Name.Local == "covered-table-cell" || c1 > c2 {
+				// 被合并覆盖的单元格，内容不显示
+				if err := d.Skip(); err != nil {
+					return err
+				}
+				continue
+			}
+			text, err := odsCellText(d)
+			if err != nil {
+				return err
+			}
+			style := xmlAttr(tt, "style-name")
+			if style == "" {
+				style = rowStyle
+			}
+			if style == "" {
+				style = t.columnStyle(c1)
+			}
+			if style == "" {
+				style = "Default"
+			}
+			if err := t.addCell(tt, text, style, first, last, c1, c2); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// addCell 记录单元格（按行列重复次数展开）及其合并区域
+func (t *odsTable) addCell(el xml.StartElement, text, style string, row1, row2, col1, col2 int) error {
+	value, fallbackFmt, ok := odsCellValue(el, text)
+	formula := ""
+	if f := odsAttr(el, odsNSTable, "formula"); f != "" {
+		formula = odsFormula(f)
+	}
+	switch {
+	case ok || formula != "":
+		if err := t.guard.reserve(cellRange{StartRow: row1, StartCol: col1, EndRow: row2, EndCol: col2}); err != nil {
+			return err
+		}
+		for r := row1; r <= row2; r++ {
+			for c := col1; c <= col2; c++ {
+				t.cells = append(t.cells, odsCell{row: r, col: c, value: value, formula: formula, style: style, fallbackFmt: fallbackFmt})
+				if err := t.guard.step(len(t.cells)); err != nil {
+					return err
+				}
+			}
+		}
+	case style != "Default":
+		t.styled = append(t.styled, odsStyledRange{row1: row1, col1: col1, row2: row2, col2: col2, style: style})
+	}
+	cols := odsRepeat(el, "number-columns-spanned")
+	rows := odsRepeat(el, "number-rows-spanned")
+	if cols > 1 || rows > 1 {
+		t.merges = append(t.merges, [4]int{row1, col1, min(row1+rows-1, excelize.TotalRows), min(col1+cols-1, excelize.MaxColumns)})
+	}
+	return nil
+}
+
+// bounds 返回内容范围：有值的单元格、合并区域以及非填充的样式区域
+func (t *odsTable) bounds() (maxRow, maxCol int) {
+	for _, c := range t.cells {
+		maxRow, maxCol = max(maxRow, c.row), max(maxCol, c.col)
+	}
+	for _, m := range t.merges {
+		maxRow, maxCol = max(maxRow, m[2]), max(maxCol, m[3])
+	}
+	for _, s := range t.styled {
+		if s.row2 < odsFillerRow && s.col2 < odsFillerCol {
+			maxRow, maxCol = max(maxRow, s.row2), max(maxCol, s.col2)
+		}
+	}
+	return maxRow, maxCol
+}
+
+// convert 将工作表转换为 Excel 的单位与样式，行列尺寸与样式区域裁剪到内容范围
+func (o *odsReader) convert(t *odsTable, guard *loadGuard) (*convertedSheet, error) {
+	sheet := newConvertedSheet(t.name, guard)
+	sheet.hidden, sheet.tabColor = t.hidden, t.tabColor
+	maxRow, maxCol := t.bounds()
+
+	for _, c := range t.cols {
+		if last := min(c.last, maxCol); c.first <= last && (c.width > 0 || c.hidden) {
+			cols := convertedCols{first: c.first, last: last, hidden: c.hidden}
+			if c.width > 0 {
+				cols.width = colWidthFromPixels(c.width*pixelsPerPoint, o.mdw)
+			}
+			sheet.cols = append(sheet.cols, cols)
+		}
+	}
+	for _, r := range t.rows {
+		for row := r.first; row <= min(r.last, maxRow); row++ {
+			sheet.rows[row] = excelize.RowOpts{Height: r.height, Hidden: r.hidden}
+		}
+	}
+
+	// 先放置只有样式的区域，再放置有值的单元格
+	for _, s := range t.styled {
+		row2, col2 := min(s.row2, maxRow), min(s.col2, maxCol)
+		if s.row1 > row2 || s.col1 > col2 {
+			continue
+		}
+		if err := guard.reserve(cellRange{StartRow: s.row1, StartCol: s.col1, EndRow: row2, EndCol: col2}); err != nil {
+			return nil, err
+		}
+		id, err := o.styleID(s.style, "")
+		if err != nil {
+			return nil, err
+		}
+		for r := s.row1; r <= row2; r++ {
+			for c := s.col1; c <= col2; c++ {
+				if err := sheet.setCell(r, c, excelize.Cell{StyleID: id}); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	for _, c := range t.cells {
+		id, err := o.styleID(c.style, c.fallbackFmt)
+		if err != nil {
+			return nil, err
+		}
+		if err := sheet.setCell(c.row, c.col, excelize.Cell{StyleID: id, Formula: c.formula, Value: c.value}); err != nil {
+			return nil, err
+		}
+	}
+	for _, m := range t.merges {
+		sheet.addMerge(m[0], m[1], m[2], m[3])
+	}
+	return sheet, nil
+}
+
+// styleID 返回单元格样式对应的 excelize 样式 ID；样式没有数字格式时使用 fallbackFmt
+func (o *odsReader) styleID(name, fallbackFmt string) (int, error) {
+	key := name + "\x00" + fallbackFmt
+	if id, ok := o.styleIDs[key]; ok {
+		return id, nil
+	}
+	style := o.styles.excelStyle(o.styles.resolve("table-cell", name))
+	if style.CustomNumFmt == nil && fallbackFmt != "" {
+		style.CustomNumFmt = &fallbackFmt
+	}
+	id, err := o.file.NewStyle(style)
+	if err != nil {
+		return 0, err
+	}
+	o.styleIDs[key] = id
+	return id, nil
+}
+
+// odsCellText 读取单元格中的段落（text:p），多个段落以换行连接；批注等其他内容被跳过
+func odsCellText(d *xml.Decoder) (string, error) {
+	var lines []string
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return "", err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Space != odsNSText || t.Name.Local != "p" {
+				if err := d.Skip(); err != nil {
+					return "", err
+				}
+				continue
+			}
+			line, err := odsReadText(d)
+			if err != nil {
+				return "", err
+			}
+			lines = append(lines, line)
+		case xml.EndElement:
+			return strings.Join(lines, "\n"), nil
+		}
+	}
+}
+
+// odsReadText 读取当前元素（开始标签已读取）内的文本直到其结束标签：
+// text:s 展开为空格，text:tab 为制表符，text:line-break 为换行，批注与绘图对象被跳过
+func odsReadText(d *xml.Decoder) (string, error) {
+	var b strings.Builder
+	for depth := 0; ; {
+		tok, err := d.Token()
+		if err != nil {
+			return "", err
+		}
+		switch t := tok.(type) {
+		case xml.CharData:
+			b.Write(t)
+		case xml.StartElement:
+			if t.Name.Space == odsNSOffice || t.Name.Space == odsNSDraw {
+				if err := d.Skip(); err != nil {
+					return "", err
+				}
+				continue
+			}
+			if t.Name.Space == odsNSText {
+				switch t.Name.Local {
+				case "s":
+					b.WriteString(strings.Repeat(" ", min(odsRepeat(t, "c"), excelize.TotalCellChars)))
+				case "tab":
+					b.WriteByte('\t')
+				case "line-break":
+					b.WriteByte('\n')
+				}
+			}
+			depth++
+		case xml.EndElement:
+			if depth == 0 {
+				return b.String(), nil
+			}
+			depth--
+		}
+	}
+}
+
+// odsCellValue 按 office:value-type 解析单元格的值；日期与时间转换为 Excel 序列值，并返回其默认格式
+func odsCellValue(el xml.StartElement, text string) (value interface{}, fallbackFmt string, ok bool) {
+	switch odsAttr(el, odsNSOffice, "value-type") {
+	case "float", "percentage", "currency":
+		if v, err := strconv.ParseFloat(odsAttr(el, odsNSOffice, "value"), 64); err == nil {
+			return v, "", true
+		}
+	case "date":
+		if v, ok := odsDateSerial(odsAttr(el, odsNSOffice, "date-value")); ok {
+			return v, odsDefaultDateFormat, true
+		}
+	case "time":
+		if v, ok := odsDurationSerial(odsAttr(el, odsNSOffice, "time-value")); ok {
+			return v, odsDefaultTimeFormat, true
+		}
+	case "boolean":
+		return odsAttr(el, odsNSOffice, "boolean-value") == "true", "", true
+	case "string":
+		if v := odsAttr(el, odsNSOffice, "string-value"); v != "" {
+			return v, "", true
+		}
+	}
+	if text == "" {
+		return nil, "", false
+	}
+	return text, "", true
+}
+
+// odsDateSerial 将 ISO 日期时间转换为 Excel 序列值（1900 日期系统）
+func odsDateSerial(v string) (float64, bool) {
+	for _, layout := range []string{"2006-01-02T15:04:05.999999999", "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, v); err == nil {
+			epoch := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+			return t.Sub(epoch).Hours() / 24, true
+		}
+	}
+	return 0, false
+}
+
+// odsDurationPattern ISO 8601 时长（如 PT13H30M00S）
+var odsDurationPattern = regexp.MustCompile(`^(-)?P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:([\d.]+)S)?)?$`)
+
+// odsDurationSerial 将时长转换为 Excel 序列值（天数）
+func odsDurationSerial(v string) (float64, bool) {
+	m := odsDurationPattern.FindStringSubmatch(v)
+	if m == nil {
+		return 0, false
+	}
+	var seconds float64
+	for i, unit := range []float64{86400, 3600, 60, 1} {
+		if n, err := strconv.ParseFloat(m[i+2], 64); err == nil {
+			seconds += n * unit
+		}
+	}
+	if m[1] != "" {
+		seconds = -seconds
+	}
+	return seconds / 86400, true
+}
+
+// odsFormula 将 OpenFormula（of:=SUM([.A1:.B2];1)）转换为 Excel 公式文本（SUM(A1:B2,1)）
+func odsFormula(v string) string {
+	if prefix, rest, ok := strings.Cut(v, ":="); ok && !strings.ContainsAny(prefix, "([\"") {
+		if prefix == "msoxl" {
+			return rest
+		}
+		v = rest
+	}
+	v = strings.TrimPrefix(v, "=")
+	var b strings.Builder
+	inString := false
+	for i := 0; i < len(v); i++ {
+		c := v[i]
+		switch {
+		case c == '"':
+			inString = !inString
+			b.WriteByte(c)
+		case inString:
+			b.WriteByte(c)
+		case c == '[':
+			end := strings.IndexByte(v[i:], ']')
+			if end < 0 {
+				b.WriteString(v[i:])
+				return b.String()
+			}
+			b.WriteString(odsReference(v[i+1 : i+end]))
+			i += end
+		case c == ';':
+			b.WriteByte(',')
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// odsReference 转换单元格引用：.A1 → A1，.A1:.B2 → A1:B2，$Sheet2.A1 → Sheet2!A1
+func odsReference(ref string) string {
+	parts := strings.Split(ref, ":")
+	for i, p := range parts {
+		dot := strings.LastIndex(p, ".")
+		if dot < 0 {
+			continue
+		}
+		sheet, cell := strings.TrimPrefix(p[:dot], "$"), p[dot+1:]
+		if sheet == "" || i > 0 {
+			parts[i] = cell
+			continue
+		}
+		if !strings.HasPrefix(sheet, "'") && strings.ContainsAny(sheet, " -()") {
+			sheet = "'" + sheet + "'"
+		}
+		parts[i] = sheet + "!" + cell
+	}
+	return strings.Join(parts, ":")
+}
+
+// odsRepeat 读取重复/跨越次数属性，缺省或无效时为 1，最大为 Excel 的最大行数
+func odsRepeat(el xml.StartElement, name string) int {
+	n, err := strconv.Atoi(xmlAttr(el, name))
+	if err != nil || n < 1 {
+		return 1
+	}
+	return min(n, excelize.TotalRows)
+}
+
+// odsAttr 返回指定命名空间的属性值（LibreOffice 会同时写入 office: 与 calcext: 的同名属性）
+func odsAttr(el xml.StartElement, space, name string) string {
+	for _, a := range el.Attr {
+		if a.Name.Space == space && a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,552 @@
+package excelsnapshot
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ODF 命名空间
+const (
+	odsNSOffice = "urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+	odsNSStyle  = "urn:oasis:names:tc:opendocument:xmlns:style:1.0"
+	odsNSTable  = "urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+	odsNSText   = "urn:oasis:names:tc:opendocument:xmlns:text:1.0"
+	odsNSNumber = "urn:oasis:names:tc:opendocument:xmlns:datastyle:1.0"
+	odsNSDraw   = "urn:oasis:names:tc:opendocument:xmlns:drawing:1.0"
+)
+
+// odsStyle ODS 样式（style:style 或 style:default-style），属性按所在的 *-properties 元素分组，键为不含前缀的属性名
+type odsStyle struct {
+	name, family, parent string
+	// 数字样式名称（style:data-style-name）
+	dataStyle string
+	// style:table-cell-properties、style:paragraph-properties、style:text-properties
+	cell, paragraph, text map[string]string
+	// style:table-column-properties、style:table-row-properties、style:table-properties
+	layout map[string]string
+}
+
+func newODSStyle(family, name string) *odsStyle {
+	return &odsStyle{
+		name:      name,
+		family:    family,
+		cell:      make(map[string]string),
+		paragraph: make(map[string]string),
+		text:      make(map[string]string),
+		layout:    make(map[string]string),
+	}
+}
+
+// setProps 记录 *-properties 元素的属性
+func (s *odsStyle) setProps(el xml.StartElement) {
+	props := s.layout
+	switch el.Name.Local {
+	case "table-cell-properties":
+		props = s.cell
+	case "paragraph-properties":
+		props = s.paragraph
+	case "text-properties":
+		props = s.text
+	}
+	for _, a := range el.Attr {
+		props[a.Name.Local] = a.Value
+	}
+}
+
+// merge 用 other 的属性覆盖当前样式（继承链由远及近依次合并）
+func (s *odsStyle) merge(other *odsStyle) {
+	if other.dataStyle != "" {
+		s.dataStyle = other.dataStyle
+	}
+	for _, pair := range [][2]map[string]string{
+		{s.cell, other.cell}, {s.paragraph, other.paragraph}, {s.text, other.text}, {s.layout, other.layout},
+	} {
+		for k, v := range pair[1] {
+			pair[0][k] = v
+		}
+	}
+}
+
+// odsStyleMap 数字样式的条件映射（style:map），如 value()<0 时使用另一个数字样式
+type odsStyleMap struct {
+	condition, style string
+}
+
+// odsDataStyle ODS 数字样式（number:*-style），各子元素按顺序转换为 Excel 数字格式代码
+type odsDataStyle struct {
+	kind  string // number-style、percentage-style、date-style 等
+	code  string
+	color string
+	maps  []odsStyleMap
+}
+
+// odsStyles ODS 文档中的字体声明、单元格/行列/表样式与数字样式
+type odsStyles struct {
+	fonts    map[string]string    // style:font-face 名称 → 字体族名
+	styles   map[string]*odsStyle // "族:名称" → 样式
+	defaults map[string]*odsStyle // 族 → style:default-style
+	data     map[string]*odsDataStyle
+	resolved map[string]*odsStyle
+}
+
+func newODSStyles() *odsStyles {
+	return &odsStyles{
+		fonts:    make(map[string]string),
+		styles:   make(map[string]*odsStyle),
+		defaults: make(map[string]*odsStyle),
+		data:     make(map[string]*odsDataStyle),
+		resolved: make(map[string]*odsStyle),
+	}
+}
+
+// parse 读取 styles.xml 或 content.xml 中的样式定义，遇到 office:body 时返回（解码器停在正文开始处）
+func (st *odsStyles) parse(d *xml.Decoder) error {
+	var cur *odsStyle
+	var data *odsDataStyle
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch {
+			case t.Name.Space == odsNSOffice && t.Name.Local == "body":
+				return nil
+			case t.Name.Local == "font-face":
+				st.fonts[xmlAttr(t, "name")] = strings.Trim(xmlAttr(t, "font-family"), `'"`)
+			case t.Name.Space == odsNSStyle && t.Name.Local == "style":
+				cur = newODSStyle(xmlAttr(t, "family"), xmlAttr(t, "name"))
+				cur.parent = xmlAttr(t, "parent-style-name")
+				cur.dataStyle = xmlAttr(t, "data-style-name")
+			case t.Name.Space == odsNSStyle && t.Name.Local == "default-style":
+				cur = newODSStyle(xmlAttr(t, "family"), "")
+			case cur != nil && strings.HasSuffix(t.Name.Local, "-properties"):
+				cur.setProps(t)
+			case t.Name.Space == odsNSNumber && strings.HasSuffix(t.Name.Local, "-style"):
+				data = &odsDataStyle{kind: t.Name.Local}
+				st.data[xmlAttr(t, "name")] = data
+			case data != nil:
+				if err := data.add(d, t); err != nil {
+					return err
+				}
+			}
+		case xml.EndElement:
+			switch {
+			case cur != nil && t.Name.Space == odsNSStyle && t.Name.Local == "style":
+				st.styles[cur.family+":"+cur.name] = cur
+				cur = nil
+			case cur != nil && t.Name.Space == odsNSStyle && t.Name.Local == "default-style":
+				st.defaults[cur.family] = cur
+				cur = nil
+			case data != nil && t.Name.Space == odsNSNumber && strings.HasSuffix(t.Name.Local, "-style"):
+				data = nil
+			}
+		}
+	}
+}
+
+// resolve 按继承链（默认样式 → 父样式 → 自身）合并出样式的最终属性
+func (st *odsStyles) resolve(family, name string) *odsStyle {
+	key := family + ":" + name
+	if s, ok := st.resolved[key]; ok {
+		return s
+	}
+	var chain []*odsStyle
+	seen := make(map[string]bool)
+	for n := name; n != "" && !seen[n]; {
+		seen[n] = true
+		s := st.styles[family+":"+n]
+		if s == nil {
+			break
+		}
+		chain = append(chain, s)
+		n = s.parent
+	}
+	if def := st.defaults[family]; def != nil {
+		chain = append(chain, def)
+	}
+	out := newODSStyle(family, name)
+	for i := len(chain) - 1; i >= 0; i-- {
+		out.merge(chain[i])
+	}
+	st.resolved[key] = out
+	return out
+}
+
+// excelStyle 将单元格样式转换为 excelize 样式
+func (st *odsStyles) excelStyle(s *odsStyle) *excelize.Style {
+	font := &excelize.Font{
+		Bold:   odsBold(s.text["font-weight"]),
+		Italic: s.text["font-style"] == "italic" || s.text["font-style"] == "oblique",
+		Color:  odsColor(s.text["color"]),
+	}
+	if name := s.text["font-name"]; name != "" {
+		font.Family = st.fonts[name]
+		if font.Family == "" {
+			font.Family = name
+		}
+	} else if family := s.text["font-family"]; family != "" {
+		font.Family = strings.Trim(family, `'"`)
+	}
+	if size, ok := odsLength(s.text["font-size"]); ok {
+		font.Size = size
+	}
+	if u := s.text["text-underline-style"]; u != "" && u != "none" {
+		font.Underline = "single"
+		if s.text["text-underline-type"] == "double" {
+			font.Underline = "double"
+		}
+	}
+	if v := s.text["text-line-through-style"]; v != "" && v != "none" {
+		font.Strike = true
+	}
+
+	style := &excelize.Style{Font: font, Border: odsBorders(s.cell)}
+	if bg := odsColor(s.cell["background-color"]); bg != "" {
+		style.Fill = excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{bg}}
+	}
+
+	align := &excelize.Alignment{
+		Vertical:     odsVerticalAlign(s.cell["vertical-align"]),
+		WrapText:     s.cell["wrap-option"] == "wrap",
+		ShrinkToFit:  s.cell["shrink-to-fit"] == "true",
+		TextRotation: odsRotation(s.cell["rotation-angle"]),
+	}
+	// text-align-source 为 value-type 时按值类型自动对齐，忽略 fo:text-align
+	if s.cell["text-align-source"] != "value-type" {
+		align.Horizontal = odsHorizontalAlign(s.paragraph["text-align"])
+	}
+	if *align != (excelize.Alignment{}) {
+		style.Alignment = align
+	}
+
+	if code := st.numFmt(s.dataStyle); code != "" {
+		style.CustomNumFmt = &code
+	}
+	return style
+}
+
+// numFmt 将数字样式及其条件映射转换为 Excel 数字格式代码（正数;负数;零）
+func (st *odsStyles) numFmt(name string) string {
+	ds := st.data[name]
+	if ds == nil {
+		return ""
+	}
+	var pos, neg string
+	for _, m := range ds.maps {
+		sub := st.data[m.style]
+		if sub == nil {
+			continue
+		}
+		switch strings.ReplaceAll(m.condition, " ", "") {
+		case "value()>=0", "value()>0":
+			pos = sub.format()
+		case "value()<0", "value()<=0":
+			neg = sub.format()
+		}
+	}
+	// LibreOffice 通常将负数格式作为主样式，通过 value()>=0 映射到正数格式
+	switch {
+	case pos != "" && neg != "":
+		return pos + ";" + neg + ";" + ds.format()
+	case pos != "":
+		return pos + ";" + ds.format()
+	case neg != "":
+		return ds.format() + ";" + neg
+	}
+	return ds.format()
+}
+
+// format 返回数字样式自身的格式代码（含颜色）
+func (ds *odsDataStyle) format() string {
+	if name := odsColorNames[ds.color]; name != "" {
+		return "[" + name + "]" + ds.code
+	}
+	return ds.code
+}
+
+// odsColorNames Excel 数字格式支持的颜色名
+var odsColorNames = map[string]string{
+	"000000": "Black", "FF0000": "Red", "00FF00": "Green", "0000FF": "Blue",
+	"FFFF00": "Yellow", "FF00FF": "Magenta", "00FFFF": "Cyan", "FFFFFF": "White",
+}
+
+// add 将数字样式的子元素追加为格式代码
+func (ds *odsDataStyle) add(d *xml.Decoder, el xml.StartElement) error {
+	long := xmlAttr(el, "style") == "long"
+	switch el.Name.Local {
+	case "number":
+		ds.code += odsNumberCode(el)
+	case "scientific-number":
+		exp, _ := strconv.Atoi(xmlAttr(el, "min-exponent-digits"))
+		ds.code += odsNumberCode(el) + "E+" + strings.Repeat("0", max(exp, 2))
+	case "fraction":
+		num, _ := strconv.Atoi(xmlAttr(el, "min-numerator-digits"))
+		den := xmlAttr(el, "denominator-value")
+		if den == "" {
+			n, _ := strconv.Atoi(xmlAttr(el, "min-denominator-digits"))
+			den = strings.Repeat("?", max(n, 1))
+		}
+		ds.code += "# " + strings.Repeat("?", max(num, 1)) + "/" + den
+	case "text", "currency-symbol":
+		text, err := odsReadText(d)
+		if err != nil {
+			return err
+		}
+		ds.code += odsQuote(text, ds.kind == "percentage-style")
+	case "day":
+		ds.code += odsPick(long, "dd", "d")
+	case "month":
+		if xmlAttr(el, "textual") == "true" {
+			ds.code += odsPick(long, "mmmm", "mmm")
+		} else {
+			ds.code += odsPick(long, "mm", "m")
+		}
+	case "year":
+		ds.code += odsPick(long, "yyyy", "yy")
+	case "day-of-week":
+		ds.code += odsPick(long, "dddd", "ddd")
+	case "hours":
+		h := odsPick(long, "hh", "h")
+		if ds.kind == "time-style" && xmlAttr(el, "truncate-on-overflow") == "false" {
+			h = "[" + h + "]"
+		}
+		ds.code += h
+	case "minutes":
+		ds.code += odsPick(long, "mm", "m")
+	case "seconds":
+		ds.code += odsPick(long, "ss", "s")
+		if n, _ := strconv.Atoi(xmlAttr(el, "decimal-places")); n > 0 {
+			ds.code += "." + strings.Repeat("0", n)
+		}
+	case "am-pm":
+		ds.code += "AM/PM"
+	case "text-content":
+		ds.code += "@"
+	case "text-properties":
+		ds.color = odsColor(xmlAttr(el, "color"))
+	case "map":
+		ds.maps = append(ds.maps, odsStyleMap{condition: xmlAttr(el, "condition"), style: xmlAttr(el, "apply-style-name")})
+	}
+	return nil
+}
+
+// odsNumberCode 将 number:number 转换为数字格式代码（整数位、千位分隔与小数位）
+func odsNumberCode(el xml.StartElement) string {
+	minInt, _ := strconv.Atoi(xmlAttr(el, "min-integer-digits"))
+	code := strings.Repeat("0", minInt)
+	if xmlAttr(el, "grouping") == "true" {
+		code = "#,##" + odsPick(minInt == 0, "#", code)
+	} else if minInt == 0 {
+		code = "#"
+	}
+	decimals, _ := strconv.Atoi(xmlAttr(el, "decimal-places"))
+	minDecimals := decimals
+	if v := xmlAttr(el, "min-decimal-places"); v != "" {
+		minDecimals, _ = strconv.Atoi(v)
+		minDecimals = min(minDecimals, decimals)
+	}
+	if decimals > 0 {
+		code += "." + strings.Repeat("0", minDecimals) + strings.Repeat("#", decimals-minDecimals)
+	}
+	return code
+}
+
+// odsQuote 将文本转换为数字格式中的字面量；百分比样式中的 % 保留为百分号
+func odsQuote(text string, percent bool) string {
+	var b strings.Builder
+	quoted := false
+	for _, r := range text {
+		if (percent && r == '%') || r == '"' {
+			if quoted {
+				b.WriteByte('"')
+				quoted = false
+			}
+			if r == '%' {
+				b.WriteRune(r)
+			}
+			continue
+		}
+		if !quoted {
+			b.WriteByte('"')
+			quoted = true
+		}
+		b.WriteRune(r)
+	}
+	if quoted {
+		b.WriteByte('"')
+	}
+	return b.String()
+}
+
+// odsPick 按条件选择字符串
+func odsPick(cond bool, a, b string) string {
+	if cond {
+		return a
+	}
+	return b
+}
+
+// odsBorders 转换单元格边框（fo:border 作用于四边，fo:border-left 等按边覆盖）
+func odsBorders(cell map[string]string) []excelize.Border {
+	var borders []excelize.Border
+	for _, side := range []struct{ attr, typ string }{
+		{"border-left", "left"}, {"border-top", "top"}, {"border-right", "right"}, {"border-bottom", "bottom"},
+		{"diagonal-tl-br", "diagonalDown"}, {"diagonal-bl-tr", "diagonalUp"},
+	} {
+		v, ok := cell[side.attr]
+		if !ok && strings.HasPrefix(side.attr, "border-") {
+			v = cell["border"]
+		}
+		if b, ok := odsBorder(side.typ, v); ok {
+			borders = append(borders, b)
+		}
+	}
+	return borders
+}
+
+// odsBorder 解析边框定义（如 "0.74pt solid #000000"），按线型与宽度映射为 Excel 边框样式
+func odsBorder(typ, v string) (excelize.Border, bool) {
+	if v == "" || v == "none" || v == "hidden" {
+		return excelize.Border{}, false
+	}
+	width, lineStyle, color := 0.75, "solid", "000000"
+	for _, f := range strings.Fields(v) {
+		if strings.HasPrefix(f, "#") {
+			color = odsColor(f)
+		} else if w, ok := odsLength(f); ok {
+			width = w
+		} else {
+			switch f {
+			case "thin":
+				width = 0.75
+			case "medium":
+				width = 1.75
+			case "thick":
+				width = 2.5
+			default:
+				lineStyle = f
+			}
+		}
+	}
+	if lineStyle == "none" || lineStyle == "hidden" {
+		return excelize.Border{}, false
+	}
+	code := 1
+	switch lineStyle {
+	case "dashed", "fine-dashed":
+		code = odsPickInt(width >= 1.5, 8, 3)
+	case "dotted":
+		code = 4
+	case "double", "double-thin":
+		code = 6
+	case "dash-dot":
+		code = odsPickInt(width >= 1.5, 10, 9)
+	case "dash-dot-dot":
+		code = odsPickInt(width >= 1.5, 12, 11)
+	default:
+		switch {
+		case width >= 2.25:
+			code = 5
+		case width >= 1.5:
+			code = 2
+		}
+	}
+	return excelize.Border{Type: typ, Color: color, Style: code}, true
+}
+
+// odsPickInt 按条件选择整数
+func odsPickInt(cond bool, a, b int) int {
+	if cond {
+		return a
+	}
+	return b
+}
+
+// odsColor 将 #RRGGBB 转换为 RRGGBB，transparent 等无效值返回空串
+func odsColor(v string) string {
+	v = strings.TrimPrefix(v, "#")
+	if len(v) != 6 {
+		return ""
+	}
+	if _, err := strconv.ParseUint(v, 16, 32); err != nil {
+		return ""
+	}
+	return strings.ToUpper(v)
+}
+
+// odsBold 判断字重是否为粗体（bold 或不小于 600）
+func odsBold(v string) bool {
+	if v == "bold" {
+		return true
+	}
+	n, err := strconv.Atoi(v)
+	return err == nil && n >= 600
+}
+
+// odsHorizontalAlign 转换水平对齐
+func odsHorizontalAlign(v string) string {
+	switch v {
+	case "start", "left":
+		return "left"
+	case "end", "right":
+		return "right"
+	case "center", "justify":
+		return v
+	}
+	return ""
+}
+
+// odsVerticalAlign 转换垂直对齐
+func odsVerticalAlign(v string) string {
+	switch v {
+	case "top", "bottom":
+		return v
+	case "middle":
+		return "center"
+	}
+	return ""
+}
+
+// odsRotation 将逆时针旋转角度转换为 Excel 的文字方向（1-90 向上，91-180 向下）
+func odsRotation(v string) int {
+	angle, err := strconv.ParseFloat(strings.TrimSuffix(v, "deg"), 64)
+	if err != nil {
+		return 0
+	}
+	a := int(angle) % 360
+	switch {
+	case a > 0 && a <= 90:
+		return a
+	case a >= 270:
+		return 90 + 360 - a
+	}
+	return 0
+}
+
+// odsLengthUnits ODF 长度单位对应的磅数
+var odsLengthUnits = []struct {
+	suffix string
+	points float64
+}{
+	{"cm", 72 / 2.54}, {"mm", 72 / 25.4}, {"in", 72}, {"pt", 1}, {"pc", 12}, {"px", 0.75},
+}
+
+// odsLength 将 ODF 长度（如 2.258cm、0.178in、10pt）换算为磅
+func odsLength(v string) (float64, bool) {
+	for _, u := range odsLengthUnits {
+		if n, ok := strings.CutSuffix(v, u.suffix); ok {
+			f, err := strconv.ParseFloat(n, 64)
+			return f * u.points, err == nil
+		}
+	}
+	return 0, false
+}
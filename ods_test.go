@@ -0,0 +1,362 @@
+package excelsnapshot
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap/zaptest"
+)
+
+// testODSStyles 测试用 styles.xml：默认单元格样式使用 Liberation Sans 10pt
+const testODSStyles = `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-styles xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:style="urn:oasis:names:tc:opendocument:xmlns:style:1.0" xmlns:fo="urn:oasis:names:tc:opendocument:xmlns:xsl-fo-compatible:1.0" xmlns:svg="urn:oasis:names:tc:opendocument:xmlns:svg-compatible:1.0" xmlns:number="urn:oasis:names:tc:opendocument:xmlns:datastyle:1.0">
+ <office:font-face-decls>
+  <style:font-face style:name="Liberation Sans" svg:font-family="'Liberation Sans'"/>
+ </office:font-face-decls>
+ <office:styles>
+  <style:default-style style:family="table-cell">
+   <style:text-properties style:font-name="Liberation Sans" fo:font-size="10pt"/>
+  </style:default-style>
+  <number:percentage-style style:name="N11">
+   <number:number number:decimal-places="2" number:min-decimal-places="2" number:min-integer-digits="1"/>
+   <number:text>%</number:text>
+  </number:percentage-style>
+  <style:style style:name="Default" style:family="table-cell"/>
+  <style:style style:name="Heading" style:family="table-cell" style:parent-style-name="Default">
+   <style:text-properties fo:font-weight="bold" fo:font-size="12pt"/>
+  </style:style>
+ </office:styles>
+</office:document-styles>`
+
+// testODSContent 测试用 content.xml：列宽、行高、样式、数字格式、合并单元格、公式、重复单元格与隐藏工作表
+const testODSContent = `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:style="urn:oasis:names:tc:opendocument:xmlns:style:1.0" xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0" xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0" xmlns:fo="urn:oasis:names:tc:opendocument:xmlns:xsl-fo-compatible:1.0" xmlns:number="urn:oasis:names:tc:opendocument:xmlns:datastyle:1.0" xmlns:calcext="urn:org:documentfoundation:names:experimental:calc:xmlns:calcext:1.0" xmlns:tableooo="http://openoffice.org/2009/table" xmlns:of="urn:oasis:names:tc:opendocument:xmlns:of:1.2">
+ <office:automatic-styles>
+  <style:style style:name="co1" style:family="table-column">
+   <style:table-column-properties style:column-width="2.54cm"/>
+  </style:style>
+  <style:style style:name="co2" style:family="table-column">
+   <style:table-column-properties style:column-width="1in"/>
+  </style:style>
+  <style:style style:name="ro1" style:family="table-row">
+   <style:table-row-properties style:row-height="24pt"/>
+  </style:style>
+  <style:style style:name="ta1" style:family="table">
+   <style:table-properties table:display="true" tableooo:tab-color="#ff0000"/>
+  </style:style>
+  <style:style style:name="ta2" style:family="table">
+   <style:table-properties table:display="false"/>
+  </style:style>
+  <number:number-style style:name="N2P0" style:volatile="true">
+   <number:number number:decimal-places="2" number:min-decimal-places="2" number:min-integer-digits="1" number:grouping="true"/>
+  </number:number-style>
+  <number:number-style style:name="N2">
+   <style:text-properties fo:color="#ff0000"/>
+   <number:text>-</number:text>
+   <number:number number:decimal-places="2" number:min-decimal-places="2" number:min-integer-digits="1" number:grouping="true"/>
+   <style:map style:condition="value()&gt;=0" style:apply-style-name="N2P0"/>
+  </number:number-style>
+  <number:date-style style:name="N37">
+   <number:year number:style="long"/><number:text>/</number:text><number:month number:style="long"/><number:text>/</number:text><number:day number:style="long"/>
+  </number:date-style>
+  <style:style style:name="ce1" style:family="table-cell" style:parent-style-name="Heading">
+   <style:table-cell-properties fo:background-color="#ffff00" fo:border="0.74pt solid #000000" style:vertical-align="middle"/>
+   <style:paragraph-properties fo:text-align="center"/>
+  </style:style>
+  <style:style style:name="ce2" style:family="table-cell" style:parent-style-name="Default" style:data-style-name="N2"/>
+  <style:style style:name="ce3" style:family="table-cell" style:parent-style-name="Default" style:data-style-name="N11"/>
+  <style:style style:name="ce4" style:family="table-cell" style:parent-style-name="Default" style:data-style-name="N37"/>
+  <style:style style:name="ce5" style:family="table-cell" style:parent-style-name="Default">
+   <style:table-cell-properties fo:background-color="#00ff00" fo:border-bottom="2.49pt solid #0000ff" fo:wrap-option="wrap"/>
+   <style:text-properties fo:font-style="italic" fo:color="#333333"/>
+  </style:style>
+ </office:automatic-styles>
+ <office:body>
+  <office:spreadsheet>
+   <table:table table:name="Report" table:style-name="ta1">
+    <table:table-column table:style-name="co1" table:number-columns-repeated="2"/>
+    <table:table-column table:style-name="co2"/>
+    <table:table-column table:style-name="co1" table:number-columns-repeated="1021"/>
+    <table:table-row table:style-name="ro1">
+     <table:table-cell table:style-name="ce1" table:number-columns-spanned="2" table:number-rows-spanned="1" office:value-type="string" calcext:value-type="string"><text:p>Sales  <text:s text:c="2"/>Report</text:p></table:table-cell>
+     <table:covered-table-cell/>
+     <table:table-cell table:style-name="ce5" table:number-columns-repeated="2"/>
+     <table:table-cell table:number-columns-repeated="1020"/>
+    </table:table-row>
+    <table:table-row>
+     <table:table-cell office:value-type="string"><text:p>Line 1</text:p><text:p>Line 2</text:p><office:annotation><text:p>note</text:p></office:annotation></table:table-cell>
+     <table:table-cell table:style-name="ce2" office:value-type="float" office:value="-1234.5" calcext:value-type="float"><text:p>-1,234.50</text:p></table:table-cell>
+     <table:table-cell table:style-name="ce3" office:value-type="percentage" office:value="0.125"><text:p>12.50%</text:p></table:table-cell>
+     <table:table-cell table:style-name="ce4" office:value-type="date" office:date-value="2024-01-15"><text:p>2024/01/15</text:p></table:table-cell>
+    </table:table-row>
+    <table:table-row table:number-rows-repeated="2">
+     <table:table-cell office:value-type="float" office:value="7" table:number-columns-repeated="2"><text:p>7</text:p></table:table-cell>
+     <table:table-cell table:formula="of:=SUM([.A3:.B4];1)" office:value-type="float" office:value="29"><text:p>29</text:p></table:table-cell>
+     <table:table-cell office:value-type="boolean" office:boolean-value="true"><text:p>TRUE</text:p></table:table-cell>
+    </table:table-row>
+    <table:table-row table:number-rows-repeated="1048572">
+     <table:table-cell table:style-name="ce5" table:number-columns-repeated="1024"/>
+    </table:table-row>
+   </table:table>
+   <table:table table:name="Hidden Data" table:style-name="ta2">
+    <table:table-row>
+     <table:table-cell table:formula="of:=[$Report.C3]*2" office:value-type="time" office:time-value="PT13H30M00S"><text:p>13:30:00</text:p></table:table-cell>
+    </table:table-row>
+   </table:table>
+  </office:spreadsheet>
+ </office:body>
+</office:document-content>`
+
+// createTestODS 构建测试用的 ODS 文件内容
+func createTestODS(t *testing.T, content string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, part := range []struct{ name, data string }{
+		{"mimetype", odsMimeType},
+		{"styles.xml", testODSStyles},
+		{"content.xml", content},
+	} {
+		w, err := zw.Create(part.name)
+		if err != nil {
+			t.Fatalf("创建 ODS 部件失败: %v", err)
+		}
+		w.Write([]byte(part.data))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("写入 ODS 失败: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// TestNewExcelFromODS 测试 ODS 的值、样式、合并单元格与行列尺寸转换
+func TestNewExcelFromODS(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	excel, err := NewExcelFromODS(bytes.NewReader(createTestODS(t, testODSContent)), logger)
+	if err != nil {
+		t.Fatalf("NewExcelFromODS() 失败: %v", err)
+	}
+	defer excel.Close()
+
+	if got := excel.GetSheetNameByIndex(1); got != "Hidden Data" {
+		t.Errorf("第二个工作表 = %q, want Hidden Data", got)
+	}
+	if visible, _ := excel.file.GetSheetVisible("Hidden Data"); visible {
+		t.Error("Hidden Data 应该被隐藏")
+	}
+	if props, err := excel.file.GetSheetProps("Report"); err != nil || props.TabColorRGB == nil || *props.TabColorRGB != "FF0000" {
+		t.Errorf("标签颜色 = %+v, %v, want FF0000", props.TabColorRGB, err)
+	}
+
+	sheet, err := excel.GetSheet("Report")
+	if err != nil {
+		t.Fatalf("获取工作表失败: %v", err)
+	}
+	// 第 5 行起为延伸到表尾的样式填充，第 3 列之后为重复的空白列
+	if sheet.Rows != 4 || sheet.Cols != 4 {
+		t.Errorf("工作表尺寸 = %dx%d, want 4x4", sheet.Rows, sheet.Cols)
+	}
+
+	tests := []struct {
+		addr string
+		want string
+	}{
+		{"A1", "Sales    Report"},
+		{"A2", "Line 1\nLine 2"},
+		{"B2", "-1,234.50"},
+		{"C2", "12.50%"},
+		{"D2", "2024/01/15"},
+		{"A3", "7"},
+		{"B4", "7"},
+		{"C4", "29"},
+		{"D3", "TRUE"},
+	}
+	for _, tt := range tests {
+		if cell := sheet.cells.get(tt.addr); cell == nil || cell.Value != tt.want {
+			t.Errorf("%s = %+v, want %q", tt.addr, cell, tt.want)
+		}
+	}
+	if cell := sheet.cells.get("C3"); cell == nil || cell.Formula != "SUM(A3:B4,1)" {
+		t.Errorf("C3 公式 = %+v, want SUM(A3:B4,1)", cell)
+	}
+	if cell := sheet.cells.get("A1"); cell == nil || !cell.IsMerged || len(cell.MergedRange) != 2 || cell.MergedRange[1] != "B1" {
+		t.Errorf("A1 应为 A1:B1 的合并单元格: %+v", cell)
+	}
+
+	style, err := sheet.cells.get("A1").RenderStyle()
+	if err != nil {
+		t.Fatalf("获取样式失败: %v", err)
+	}
+	if !style.Font.Bold || style.Font.Size != 12 || style.Font.Family != "Liberation Sans" {
+		t.Errorf("A1 字体 = %+v, want 继承 Heading 的粗体 12pt Liberation Sans", style.Font)
+	}
+	if len(style.Fill.Color) == 0 || style.Fill.Color[0] != "FFFF00" {
+		t.Errorf("A1 填充 = %+v, want FFFF00", style.Fill)
+	}
+	if len(style.Border) != 4 || style.Alignment == nil || style.Alignment.Horizontal != "center" || style.Alignment.Vertical != "center" {
+		t.Errorf("A1 边框/对齐 = %+v %+v", style.Border, style.Alignment)
+	}
+	style, _ = sheet.cells.get("C1").RenderStyle()
+	if style == nil || len(style.Border) != 1 || style.Border[0].Type != "bottom" || style.Border[0].Style != 5 ||
+		!style.Font.Italic || style.Alignment == nil || !style.Alignment.WrapText {
+		t.Errorf("C1 样式 = %+v", style)
+	}
+
+	// 2.54cm = 96px，1in = 96px；行高 24pt
+	for _, col := range []string{"A", "C"} {
+		if px := sheet.ColWidthPixels(col); px < 96 || px > 97 {
+			t.Errorf("%s 列宽 = %.2fpx, want 96px", col, px)
+		}
+	}
+	if h := sheet.GetRowHeight(1); h != 24 {
+		t.Errorf("第 1 行行高 = %v, want 24", h)
+	}
+
+	hidden, err := excel.GetSheet("Hidden Data")
+	if err != nil {
+		t.Fatalf("获取工作表失败: %v", err)
+	}
+	if cell := hidden.cells.get("A1"); cell == nil || cell.Value != "13:30:00" || cell.Formula != "Report!C3*2" {
+		t.Errorf("Hidden Data!A1 = %+v, want 13:30:00 与公式 Report!C3*2", cell)
+	}
+
+	if _, err := NewSheetRenderer(logger).RenderSheet(sheet); err != nil {
+		t.Errorf("渲染失败: %v", err)
+	}
+}
+
+// TestNewExcelFromODS_Invalid 测试无效的 ODS 内容
+func TestNewExcelFromODS_Invalid(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"不是压缩包", []byte("plain text")},
+		{"没有工作表", createTestODS(t, `<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0"><office:body/></office:document-content>`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewExcelFromODS(bytes.NewReader(tt.data), logger); err == nil {
+				t.Error("应该返回错误")
+			}
+		})
+	}
+}
+
+// TestODSFormula 测试 OpenFormula 到 Excel 公式的转换
+func TestODSFormula(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"of:=SUM([.A1:.B2])", "SUM(A1:B2)"},
+		{"of:=IF([.A1]>0;\"a;b\";[.$B$1])", "IF(A1>0,\"a;b\",$B$1)"},
+		{"of:=[$Sheet2.A1]+['My Sheet'.B2]", "Sheet2!A1+'My Sheet'!B2"},
+		{"of:=SUM([$'Q1 Data'.A1:.A9])", "SUM('Q1 Data'!A1:A9)"},
+		{"msoxl:=SUM(A1:A3)", "SUM(A1:A3)"},
+	}
+	for _, tt := range tests {
+		if got := odsFormula(tt.in); got != tt.want {
+			t.Errorf("odsFormula(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// TestODSNumFmt 测试数字样式转换为 Excel 数字格式代码
+func TestODSNumFmt(t *testing.T) {
+	st := newODSStyles()
+	st.data["pos"] = &odsDataStyle{code: "#,##0.00"}
+	st.data["neg"] = &odsDataStyle{code: `"-"#,##0.00`, color: "FF0000", maps: []odsStyleMap{{"value()>=0", "pos"}}}
+	st.data["pct"] = &odsDataStyle{kind: "percentage-style", code: "0.0" + odsQuote("%", true)}
+	tests := []struct {
+		name, want string
+	}{
+		{"pos", "#,##0.00"},
+		{"neg", `#,##0.00;[Red]"-"#,##0.00`},
+		{"pct", "0.0%"},
+		{"missing", ""},
+	}
+	for _, tt := range tests {
+		if got := st.numFmt(tt.name); got != tt.want {
+			t.Errorf("numFmt(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+	if got := odsQuote("US$ ", false); got != `"US$ "` {
+		t.Errorf("odsQuote() = %q", got)
+	}
+}
+
+// TestNewExcelFromODS_Streaming 测试流式加载转换后的工作簿（超出解压大小限制时读取转换结果的原始内容）
+func TestNewExcelFromODS_Streaming(t *testing.T) {
+	excel, err := NewExcelFromODS(bytes.NewReader(createTestODS(t, testODSContent)), zaptest.NewLogger(t),
+		WithStreaming(true),
+		WithOpenOptions(excelize.Options{UnzipXMLSizeLimit: 1}),
+	)
+	if err != nil {
+		t.Fatalf("NewExcelFromODS() 失败: %v", err)
+	}
+	defer excel.Close()
+	sheet, err := excel.GetSheet("Report")
+	if err != nil {
+		t.Fatalf("获取工作表失败: %v", err)
+	}
+	if cell := sheet.cells.get("B2"); cell == nil || cell.Value != "-1,234.50" {
+		t.Errorf("B2 = %+v, want -1,234.50", cell)
+	}
+	if cell := sheet.cells.get("C3"); cell == nil || cell.Formula != "SUM(A3:B4,1)" {
+		t.Errorf("C3 公式 = %+v, want SUM(A3:B4,1)", cell)
+	}
+}
+
+// testODSRepeatContent 重复次数超出 Excel 最大行列数的工作表
+const testODSRepeatContent = `<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0" xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0">
+ <office:body>
+  <office:spreadsheet>
+   <table:table table:name="Repeat">
+    <table:table-column table:number-columns-repeated="100000"/>
+    <table:table-row>
+     <table:table-cell office:value-type="string"><text:p>起点</text:p></table:table-cell>
+     <table:table-cell table:number-columns-repeated="20000" table:number-columns-spanned="20000"/>
+     <table:table-cell office:value-type="string"><text:p>超出列</text:p></table:table-cell>
+    </table:table-row>
+    <table:table-row table:number-rows-repeated="9999999999">
+     <table:table-cell/>
+    </table:table-row>
+    <table:table-row>
+     <table:table-cell office:value-type="string"><text:p>超出行</text:p></table:table-cell>
+    </table:table-row>
+   </table:table>
+  </office:spreadsheet>
+ </office:body>
+</office:document-content>`
+
+// TestNewExcelFromODS_Repeat 测试重复与跨越次数按 Excel 的最大行列数截断
+func TestNewExcelFromODS_Repeat(t *testing.T) {
+	excel, err := NewExcelFromODS(bytes.NewReader(createTestODS(t, testODSRepeatContent)), zaptest.NewLogger(t))
+	if err != nil {
+		t.Fatalf("NewExcelFromODS() 失败: %v", err)
+	}
+	defer excel.Close()
+	sheet, err := excel.GetSheet("Repeat")
+	if err != nil {
+		t.Fatalf("获取工作表失败: %v", err)
+	}
+	if cell := sheet.cells.get("A1"); cell == nil || cell.Value != "起点" {
+		t.Errorf("A1 = %+v, want 起点", cell)
+	}
+	if sheet.Rows != 1 {
+		t.Errorf("行数 = %d, want 1（超出最大行数的行被忽略）", sheet.Rows)
+	}
+	if sheet.Cols > excelize.MaxColumns {
+		t.Errorf("列数 = %d, 超出最大列数 %d", sheet.Cols, excelize.MaxColumns)
+	}
+	sheet.cells.each(func(c *Cell) error {
+		if strings.HasPrefix(c.Value, "超出") {
+			t.Errorf("超出最大行列数的单元格 %s 不应被转换", c.Address)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,71 @@
+package excelsnapshot
+
+import (
+	"bytes"
+	"unicode/utf16"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// PasswordError 打开加密工作簿失败：未提供打开密码（Missing 为 true）或密码错误
+type PasswordError struct {
+	Missing bool
+	// excelize 返回的原始错误
+	Err error
+}
+
+func (e *PasswordError) Error() string {
+	if e.Missing {
+		return "工作簿已加密，需要提供打开密码"
+	}
+	return "工作簿打开密码错误"
+}
+
+func (e *PasswordError) Unwrap() error {
+	return e.Err
+}
+
+// WithPassword 设置加密工作簿的打开密码（优先于 WithOpenOptions 中的 Password）
+func WithPassword(password string) ExcelOption {
+	return func(e *Excel) {
+		e.password = password
+	}
+}
+
+// excelizeOptions 返回打开工作簿时传递给 excelize 的选项
+func (e *Excel) excelizeOptions() excelize.Options {
+	opts := e.openOptions
+	if e.password != "" {
+		opts.Password = e.password
+	}
+	return opts
+}
+
+// openError 将加密工作簿的打开失败转换为 PasswordError；content 按需读取文件内容
+func (e *Excel) openError(err error, content func() []byte) error {
+	if !isEncryptedWorkbook(content()) {
+		return err
+	}
+	return &PasswordError{Missing: e.excelizeOptions().Password == "", Err: err}
+}
+
+var (
+	// oleSignature OLE 复合文档（CFB）文件头
+	oleSignature = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
+	// encryptedPackageName 加密工作簿中存放密文的流名称（目录项名称为 UTF-16LE）
+	encryptedPackageName = utf16LE("EncryptedPackage")
+)
+
+// isEncryptedWorkbook 判断内容是否为加密的 OOXML 工作簿（包含 EncryptedPackage 流的 OLE 复合文档）
+func isEncryptedWorkbook(data []byte) bool {
+	return bytes.HasPrefix(data, oleSignature) && bytes.Contains(data, encryptedPackageName)
+}
+
+// utf16LE 将字符串编码为 UTF-16LE 字节
+func utf16LE(s string) []byte {
+	var b []byte
+	for _, u := range utf16.Encode([]rune(s)) {
+		b = append(b, byte(u), byte(u>>8))
+	}
+	return b
+}
@@ -0,0 +1,135 @@
+package excelsnapshot
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap/zaptest"
+)
+
+// createEncryptedTestExcel 创建设置了打开密码的测试Excel文件
+func createEncryptedTestExcel(filename, password string) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	f.SetCellValue("Sheet1", "A1", "机密")
+	f.SetCellValue("Sheet1", "B2", 42)
+	return f.SaveAs(filename, excelize.Options{Password: password})
+}
+
+// TestNewExcel_Password 测试加密工作簿的密码处理
+func TestNewExcel_Password(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "encrypted.xlsx")
+	if err := createEncryptedTestExcel(testFile, "secret"); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+	data, err := os.ReadFile(testFile)
+	if err != nil {
+		t.Fatalf("读取测试文件失败: %v", err)
+	}
+	logger := zaptest.NewLogger(t)
+
+	tests := []struct {
+		name        string
+		opts        []ExcelOption
+		wantErr     bool
+		wantMissing bool
+	}{
+		{
+			name:        "未提供密码",
+			wantErr:     true,
+			wantMissing: true,
+		},
+		{
+			name:    "密码错误",
+			opts:    []ExcelOption{WithPassword("wrong")},
+			wantErr: true,
+		},
+		{
+			name: "密码正确",
+			opts: []ExcelOption{WithPassword("secret")},
+		},
+		{
+			name: "通过打开选项提供密码",
+			opts: []ExcelOption{WithOpenOptions(excelize.Options{Password: "secret"})},
+		},
+	}
+
+	sources := []struct {
+		name string
+		open func(opts []ExcelOption) (*Excel, error)
+	}{
+		{"文件", func(opts []ExcelOption) (*Excel, error) { return NewExcel(testFile, logger, opts...) }},
+		{"字节", func(opts []ExcelOption) (*Excel, error) { return NewExcelFromBytes(data, logger, opts...) }},
+	}
+	for _, src := range sources {
+		for _, tt := range tests {
+			t.Run(src.name+"/"+tt.name, func(t *testing.T) {
+				excel, err := src.open(tt.opts)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("打开工作簿 error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if tt.wantErr {
+					var pe *PasswordError
+					if !errors.As(err, &pe) {
+						t.Fatalf("错误类型 = %T, want *PasswordError", err)
+					}
+					if pe.Missing != tt.wantMissing {
+						t.Errorf("PasswordError.Missing = %v, want %v", pe.Missing, tt.wantMissing)
+					}
+					return
+				}
+				defer excel.Close()
+				sheet, err := excel.GetSheet("Sheet1")
+				if err != nil {
+					t.Fatalf("获取工作表失败: %v", err)
+				}
+				if cell := sheet.cells.get("B2"); cell == nil || cell.Value != "42" {
+					t.Errorf("B2 = %+v, want 42", cell)
+				}
+			})
+		}
+	}
+}
+
+// TestNewExcel_NotEncrypted 测试未加密的无效文件不会被识别为密码错误
+func TestNewExcel_NotEncrypted(t *testing.T) {
+	_, err := NewExcelFromBytes([]byte("plain text"), zaptest.NewLogger(t), WithPassword("secret"))
+	if err == nil {
+		t.Fatal("无效内容应该返回错误")
+	}
+	var pe *PasswordError
+	if errors.As(err, &pe) {
+		t.Errorf("无效内容不应返回 PasswordError: %v", err)
+	}
+}
+
+// TestNewExcel_PasswordStreamingSpilled 测试加密工作簿在流式加载超出解压大小限制的工作表时解密原始内容
+func TestNewExcel_PasswordStreamingSpilled(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "encrypted.xlsx")
+	if err := createEncryptedTestExcel(testFile, "secret"); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+	excel, err := NewExcel(testFile, zaptest.NewLogger(t),
+		WithPassword("secret"),
+		WithStreaming(true),
+		WithOpenOptions(excelize.Options{UnzipXMLSizeLimit: 1}),
+	)
+	if err != nil {
+		t.Fatalf("打开工作簿失败: %v", err)
+	}
+	defer excel.Close()
+	if readWorksheetXML(excel.file, "Sheet1") != nil {
+		t.Skip("工作表仍在内存中，无法验证回退路径")
+	}
+	sheet, err := excel.GetSheet("Sheet1")
+	if err != nil {
+		t.Fatalf("获取工作表失败: %v", err)
+	}
+	if cell := sheet.cells.get("B2"); cell == nil || cell.Value != "42" {
+		t.Errorf("B2 = %+v, want 42", cell)
+	}
+}
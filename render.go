@@ -1,10 +1,12 @@
 package excelsnapshot
 
 import (
+	"context"
 	"fmt"
 	"image"
 	"image/color"
 	"math"
+	"slices"
 
 	"github.com/fogleman/gg"
 	"github.com/xuri/excelize/v2"
@@ -12,48 +14,200 @@ import (
 	"golang.org/x/image/font"
 )
 
-// 图片缩放比例
-const scale = 2.0
+// 默认输出缩放比例（相对 96 DPI）
+const defaultScale = 2.0
 
+// SheetRenderer 将工作表渲染为图片
+// 创建后可被多个 goroutine 并发使用（如 HTTP 服务共享一个渲染器）：渲染期间只读取渲染器与工作表，
+// 每次渲染从字体缓存中取出独占的字体 Face，渲染结束后放回复用。
+// 创建时的 RendererOption 为默认配置，RenderSheet 等方法可按次传入 RendererOption 覆盖，不影响其他调用
 type SheetRenderer struct {
-	logger  *zap.Logger
-	fontMap map[string]font.Face
+	logger *zap.Logger
+	// 输出缩放比例：绘制坐标以 96 DPI 像素为单位，输出图片尺寸为其 scale 倍
+	scale float64
+	// 输出图片的最大宽高（像素，0 表示不限制）
+	maxWidth, maxHeight int
+	// 渲染的资源限制（MaxPixels 与 MaxDuration）
+	limits Limits
+	// 并发安全的字体 Face 缓存（按字体配置创建）
+	fontCache *fontCache
+	// 当前渲染独占的字体集合，只在 session 返回的副本中设置
+	faces *fontSet
+	// 字体注册表（为空或无匹配时使用内置字体）
+	fonts *FontRegistry
+	// 后备字体族名（按顺序用于补全主字体缺失的字形）
+	fallbackFamilies []string
+
+	// 画布背景色（透明时为 color.Transparent）
+	background color.Color
+	// 是否隐藏默认网格线
+	hideGridlines bool
+	// 默认网格线颜色（为空时为浅灰色）
+	gridlineColor color.Color
+	// 只渲染的单元格区域（如 "B2:H40"），为空时渲染整个工作表
+	cellRange string
+
+	// 是否绘制筛选按钮
+	showFilterButtons bool
+	// 是否绘制数据验证下拉箭头
+	showValidationDropdowns bool
+	// 是否显示公式而非值
+	showFormulas bool
+	// 是否绘制错误值提示三角
+	showErrorIndicators bool
 }
 
-// NewSheetRenderer 创建 SheetRenderer
-func NewSheetRenderer(logger *zap.Logger) *SheetRenderer {
-	return &SheetRenderer{
-		logger:  logger,
-		fontMap: make(map[string]font.Face),
+// NewSheetRenderer 创建 SheetRenderer；logger 为 nil 时不输出日志
+func NewSheetRenderer(logger *zap.Logger, opts ...RendererOption) *SheetRenderer {
+	sr := &SheetRenderer{
+		logger:     orNop(logger),
+		scale:      defaultScale,
+		background: color.White,
 	}
+	for _, opt := range opts {
+		opt(sr)
+	}
+	sr.fontCache = newFontCache(sr.fonts, sr.fallbackFamilies)
+	return sr
+}
+
+// session 返回供一次渲染使用的渲染器副本，opts 只作用于该副本；副本独占一个字体集合，用完后须调用 endSession 放回
+func (sr *SheetRenderer) session(opts ...RendererOption) *SheetRenderer {
+	r := *sr
+	for _, opt := range opts {
+		opt(&r)
+	}
+	if sr.fontCache != nil && r.fonts == sr.fonts && slices.Equal(r.fallbackFamilies, sr.fallbackFamilies) {
+		r.faces = sr.fontCache.get()
+	} else {
+		// 本次调用指定了其他字体：使用临时的字体集合，用完后丢弃
+		r.fontCache = nil
+		r.faces = newFontSet(r.fonts, r.fallbackFamilies)
+	}
+	return &r
+}
+
+// endSession 将副本的字体集合放回缓存
+func (sr *SheetRenderer) endSession() {
+	if sr.fontCache != nil {
+		sr.fontCache.put(sr.faces)
+	}
+	sr.faces = nil
+}
+
+// Scale 返回渲染器的输出缩放比例
+func (sr *SheetRenderer) Scale() float64 {
+	return sr.scale
+}
+
+// fitScale 在最大尺寸限制内选择缩放比例（只缩小，不超过设定的缩放比例）
+func (sr *SheetRenderer) fitScale(w, h float64) float64 {
+	scale := sr.scale
+	if sr.maxWidth > 0 && w*scale > float64(sr.maxWidth) {
+		scale = float64(sr.maxWidth) / w
+	}
+	if sr.maxHeight > 0 && h*scale > float64(sr.maxHeight) {
+		scale = float64(sr.maxHeight) / h
+	}
+	return scale
 }
 
-// RenderSheet 渲染工作表为图片
-func (sr *SheetRenderer) RenderSheet(sheet *Sheet) (image.Image, error) {
+// canvasSize 计算缩放后的画布像素尺寸（容忍浮点误差，避免超出最大尺寸 1 像素）
+func canvasSize(w, h, scale float64) (int, int) {
+	const eps = 1e-6
+	return max(int(math.Ceil(w*scale-eps)), 1), max(int(math.Ceil(h*scale-eps)), 1)
+}
+
+// RenderSheet 渲染工作表为图片，opts 只作用于本次渲染（如 WithScale、WithBackground、WithRange）
+// 设置了最大尺寸时，在栅格化之前按限制选择缩放比例，而非渲染后再缩放
+func (sr *SheetRenderer) RenderSheet(sheet *Sheet, opts ...RendererOption) (image.Image, error) {
+	return sr.RenderSheetContext(context.Background(), sheet, opts...)
+}
+
+// RenderSheetContext 同 RenderSheet，ctx 取消或超出 WithRenderLimits 设置的资源限制时中止并返回错误
+// 画布像素数在分配画布之前检查
+func (sr *SheetRenderer) RenderSheetContext(ctx context.Context, sheet *Sheet, opts ...RendererOption) (image.Image, error) {
 	if sheet == nil {
 		return nil, fmt.Errorf("工作表为空")
 	}
+	r := sr.session(opts...)
+	defer r.endSession()
+	ctx, cancel := r.limits.withTimeout(ctx, sheet.Name)
+	defer cancel()
+
+	grid := newGridLayout(sheet)
+	view, err := r.renderArea(grid)
+	if err != nil {
+		return nil, err
+	}
+	r.scale = r.fitScale(view.w, view.h)
+	return r.renderSheet(ctx, sheet, grid, view)
+}
 
-	w, h := sr.getSheetWidthAndHeight(sheet)
-	canvas := gg.NewContext(int(w*scale), int(h*scale))
+// renderArea 返回要渲染的区域：WithRange 指定的单元格区域（按工作表的渲染区域截断），未指定时为整个工作表
+func (sr *SheetRenderer) renderArea(grid *gridLayout) (viewport, error) {
+	if sr.cellRange == "" {
+		return viewport{w: grid.width(), h: grid.height()}, nil
+	}
+	sc, sRow, ec, eRow, err := parseRangeRef(sr.cellRange)
+	if err != nil {
+		return viewport{}, fmt.Errorf("无效的区域 %q: %w", sr.cellRange, err)
+	}
+	view := viewport{x: grid.colX(sc), y: grid.rowY(sRow)}
+	view.w = grid.colX(ec+1) - view.x
+	view.h = grid.rowY(eRow+1) - view.y
+	if view.w <= 0 || view.h <= 0 {
+		return viewport{}, fmt.Errorf("区域 %q 不在工作表的渲染区域内", sr.cellRange)
+	}
+	return view, nil
+}
+
+// renderSheet 按渲染器当前的缩放比例绘制工作表的 view 区域
+func (sr *SheetRenderer) renderSheet(ctx context.Context, sheet *Sheet, grid *gridLayout, view viewport) (image.Image, error) {
+	scale := sr.scale
+	width, height := canvasSize(view.w, view.h, scale)
+	if err := sr.limits.checkPixels(sheet.Name, width, height); err != nil {
+		return nil, err
+	}
+	canvas := gg.NewContext(width, height)
 	canvas.Scale(scale, scale) // 重要：缩放坐标系，这样绘制时就是按原始尺寸计算
+	canvas.Translate(-view.x, -view.y)
+
+	if err := sr.drawSheet(ctx, canvas, sheet, grid, view); err != nil {
+		return nil, err
+	}
+
+	// 直接返回高分辨率图片，不缩放
+	return canvas.Image(), nil
+}
 
-	canvas.SetColor(color.White)
+// drawSheet 绘制工作表中与可视区域相交的部分（画布坐标系已按缩放与可视区域原点变换）
+// 绘制单元格期间定期检查 ctx，取消或超时时返回错误
+func (sr *SheetRenderer) drawSheet(ctx context.Context, canvas *gg.Context, sheet *Sheet, grid *gridLayout, view viewport) error {
+	canvas.SetColor(sr.background)
 	canvas.Clear()
 
 	// 设置线条宽度，根据缩放调整
-	canvas.SetLineWidth(scale)
+	canvas.SetLineWidth(sr.scale)
 	canvas.SetColor(color.Black)
 
-	// 计算所有单元格矩形信息
-	cellRects := sr.calculateCellRects(sheet)
+	// 计算可视区域内单元格的矩形信息
+	cellRects := sr.cellRectsIn(sheet, grid, view)
 
 	// 先绘制整张默认网格（浅灰色）
-	sr.drawBaseGrid(canvas, sheet)
+	if !sr.hideGridlines {
+		sr.drawBaseGrid(canvas, grid, view)
+	}
 
 	// 再绘制单元格：背景+文本，并仅对非默认边框颜色进行覆盖
+	drawn := 0
 	for addr, rect := range cellRects {
-		cell := sheet.cells[addr]
+		if drawn++; drawn%guardInterval == 0 {
+			if err := contextError(ctx); err != nil {
+				return err
+			}
+		}
+		cell := sheet.cells.get(addr)
 		if cell == nil {
 			continue
 		}
@@ -67,78 +221,99 @@ func (sr *SheetRenderer) RenderSheet(sheet *Sheet) (image.Image, error) {
 		sr.drawCellBordersOverride(canvas, rect, cell)
 	}
 
+	// 迷你图绘制在单元格背景之上
+	sr.drawSparklines(canvas, sheet, cellRects)
+
+	// 界面元素：筛选按钮与数据验证下拉箭头（可选）
+	sr.drawDropdownHints(canvas, sheet, cellRects)
+
 	// 最后绘制嵌入的图片（在单元格内容之上）
 	if len(sheet.images) > 0 {
-		sr.logger.Info("开始渲染图片", zap.Int("数量", len(sheet.images)))
+		sr.logger.Debug("开始渲染图片", zap.Int("数量", len(sheet.images)))
+	}
+	if err := contextError(ctx); err != nil {
+		return err
 	}
 	sr.drawImages(canvas, sheet, cellRects)
 	sr.logger.Debug("图片渲染完成")
-
-	// 直接返回高分辨率图片，不缩放
-	return canvas.Image(), nil
+	return contextError(ctx)
 }
 
 // calculateCellRects 计算每个单元格在画布上的位置和大小
 func (sr *SheetRenderer) calculateCellRects(sheet *Sheet) map[string]struct{ x, y, w, h float64 } {
-	cellRects := make(map[string]struct{ x, y, w, h float64 })
+	grid := newGridLayout(sheet)
+	return sr.cellRectsIn(sheet, grid, viewport{w: grid.width(), h: grid.height()})
+}
 
-	// 记录每行和每列的偏移量
-	colOffsets := make([]float64, sheet.Cols+1)
-	rowOffsets := make([]float64, sheet.Rows+1)
+// cellRectsIn 计算与可视区域相交的单元格矩形
+// 横向额外包含一个可视区域宽度的单元格（文本可能溢出到相邻单元格）；
+// 合并区域的主单元格与图片的锚点单元格在区域外时也会被包含，以便绘制跨越区域边界的内容
+func (sr *SheetRenderer) cellRectsIn(sheet *Sheet, grid *gridLayout, view viewport) map[string]struct{ x, y, w, h float64 } {
+	cellRects := make(map[string]struct{ x, y, w, h float64 })
 
-	// 计算列偏移
-	for c := 1; c <= sheet.Cols; c++ {
-		colName, _ := excelize.ColumnNumberToName(c)
-		colWidth := sheet.GetColWidth(colName) * 7
-		colOffsets[c] = colOffsets[c-1] + colWidth
-	}
+	var add func(c, r int)
+	add = func(c, r int) {
+		cellAddr, _ := excelize.CoordinatesToCellName(c, r)
+		if _, ok := cellRects[cellAddr]; ok {
+			return
+		}
+		cell := sheet.cells.at(r, c)
 
-	// 计算行偏移
-	for r := 1; r <= sheet.Rows; r++ {
-		rowHeight := sheet.GetRowHeight(r) * 1.33
-		rowOffsets[r] = rowOffsets[r-1] + rowHeight
-	}
+		var rectW, rectH float64
+		if cell != nil && cell.IsMerged && cell.MergedRange[0] == cellAddr {
+			rectW, rectH = sr.calcMergedRectOffsets(cell, grid)
+		} else {
+			rectW = grid.colX(c+1) - grid.colX(c)
+			rectH = grid.rowY(r+1) - grid.rowY(r)
+		}
 
-	// 遍历每个单元格
-	for r := 1; r <= sheet.Rows; r++ {
-		for c := 1; c <= sheet.Cols; c++ {
-			cellAddr, _ := excelize.CoordinatesToCellName(c, r)
-			cell := sheet.cells[cellAddr]
+		cellRects[cellAddr] = struct{ x, y, w, h float64 }{
+			x: grid.colX(c),
+			y: grid.rowY(r),
+			w: rectW,
+			h: rectH,
+		}
 
-			var rectW, rectH float64
-			if cell != nil && cell.IsMerged && cell.MergedRange[0] == cellAddr {
-				rectW, rectH = sr.calcMergedRectOffsets(cell, colOffsets, rowOffsets)
-			} else {
-				rectW = colOffsets[c] - colOffsets[c-1]
-				rectH = rowOffsets[r] - rowOffsets[r-1]
+		// 合并区域内的单元格：同时包含主单元格
+		if cell != nil && cell.IsMerged && cell.MergedRange[0] != cellAddr {
+			if mc, mr, err := excelize.CellNameToCoordinates(cell.MergedRange[0]); err == nil {
+				add(mc, mr)
 			}
+		}
+	}
 
-			cellRects[cellAddr] = struct{ x, y, w, h float64 }{
-				x: colOffsets[c-1],
-				y: rowOffsets[r-1],
-				w: rectW,
-				h: rectH,
-			}
+	c0, c1 := grid.visibleCols(view.x-view.w, view.x+2*view.w)
+	r0, r1 := grid.visibleRows(view.y, view.y+view.h)
+	for r := r0; r <= r1; r++ {
+		for c := c0; c <= c1; c++ {
+			add(c, r)
+		}
+	}
+
+	// 图片的锚点单元格（图片可能从区域外延伸进来）
+	for _, img := range sheet.images {
+		if c, r, err := excelize.CellNameToCoordinates(img.Cell); err == nil && grid.contains(r, c) {
+			add(c, r)
 		}
 	}
 
 	return cellRects
 }
 
-// calcMergedRectOffsets 计算合并单元格的宽高及位置
-func (sr *SheetRenderer) calcMergedRectOffsets(cell *Cell, colOffsets, rowOffsets []float64) (float64, float64) {
+// calcMergedRectOffsets 计算合并单元格的宽高（超出渲染区域的部分被裁剪）
+func (sr *SheetRenderer) calcMergedRectOffsets(cell *Cell, grid *gridLayout) (float64, float64) {
 	endAddr := cell.MergedRange[len(cell.MergedRange)-1]
 	endCol, endRow, _ := excelize.CellNameToCoordinates(endAddr)
 
-	width := colOffsets[endCol] - colOffsets[cell.Col-1]
-	height := rowOffsets[endRow] - rowOffsets[cell.Row-1]
+	width := grid.colX(endCol+1) - grid.colX(cell.Col)
+	height := grid.rowY(endRow+1) - grid.rowY(cell.Row)
 	return width, height
 }
 
 // drawCell 绘制单元格，包括背景色和文本
 func (sr *SheetRenderer) drawCell(canvas *gg.Context, rect struct{ x, y, w, h float64 }, cell *Cell) {
 	// 绘制背景色（样式容错）
-	style, err := cell.Style()
+	style, err := cell.RenderStyle()
 	if err != nil || style == nil {
 		if err != nil {
 			sr.logger.Debug("获取单元格样式失败，采用默认样式", zap.Error(err))
@@ -152,27 +327,30 @@ func (sr *SheetRenderer) drawCell(canvas *gg.Context, rect struct{ x, y, w, h fl
 	}
 
 	// 绘制文本（避免缩放后的位图再缩放导致的模糊）
-	if cell.Value != "" {
+	text := sr.displayText(cell)
+	if text != "" {
 		// 字体参数（样式容错）
 		fontSize := 11.0
-		bold := false
+		bold, italic := false, false
+		family := ""
 		if style != nil && style.Font != nil {
 			if style.Font.Size > 0 {
 				fontSize = style.Font.Size
 			}
 			bold = style.Font.Bold
+			italic = style.Font.Italic
+			family = style.Font.Family
 		}
 
 		// 使用未缩放坐标系绘制文字：放大字体尺寸，使用设备像素坐标
-		fontFace, err := sr.GetFont(fontSize*scale, bold)
+		fontFace, err := sr.GetFontFace(family, fontSize*pixelsPerPoint*sr.scale, bold, italic)
 		if err != nil {
 			sr.logger.Error("获取字体失败", zap.Error(err))
 			return
 		}
 
 		// 计算设备像素坐标并进行像素对齐
-		dx := (rect.x + rect.w/2) * scale
-		dy := (rect.y + rect.h/2) * scale
+		dx, dy := canvas.TransformPoint(rect.x+rect.w/2, rect.y+rect.h/2)
 		dx = math.Round(dx)
 		dy = math.Round(dy)
 
@@ -183,13 +361,41 @@ func (sr *SheetRenderer) drawCell(canvas *gg.Context, rect struct{ x, y, w, h fl
 			}
 		}
 
+		// 按字形覆盖拆分文本，缺字的部分使用后备字体绘制
+		runs := splitTextRuns(text, sr.fontChain(fontFace, fontSize*pixelsPerPoint*sr.scale, bold))
+
 		canvas.Push()
 		canvas.Identity()
-		canvas.SetFontFace(fontFace)
 		canvas.SetColor(fontColor)
-		canvas.DrawStringAnchored(cell.Value, dx, dy, 0.5, 0.3)
+		drawRunsAnchored(canvas, runs, dx, dy, 0.5, 0.3)
 		canvas.Pop()
 	}
+
+	// 错误提示三角（仅公式产生的错误值，与 Excel 的错误检查规则一致）
+	if sr.showErrorIndicators && cell.HasFormula() && cell.IsError() {
+		sr.drawErrorIndicator(canvas, rect)
+	}
+}
+
+// displayText 返回单元格要显示的文本：显示公式模式下公式单元格显示 "=公式"
+func (sr *SheetRenderer) displayText(cell *Cell) string {
+	if sr.showFormulas && cell.HasFormula() {
+		return "=" + cell.Formula
+	}
+	return cell.Value
+}
+
+// drawErrorIndicator 在单元格左上角绘制绿色三角
+func (sr *SheetRenderer) drawErrorIndicator(canvas *gg.Context, rect struct{ x, y, w, h float64 }) {
+	size := math.Min(6, math.Min(rect.w, rect.h)/2)
+	canvas.Push()
+	canvas.SetColor(color.RGBA{R: 0, G: 128, B: 0, A: 255})
+	canvas.MoveTo(rect.x, rect.y)
+	canvas.LineTo(rect.x+size, rect.y)
+	canvas.LineTo(rect.x, rect.y+size)
+	canvas.ClosePath()
+	canvas.Fill()
+	canvas.Pop()
 }
 
 // getSheetWidthAndHeight 获取工作表宽高
@@ -198,14 +404,8 @@ func (sr *SheetRenderer) getSheetWidthAndHeight(sheet *Sheet) (float64, float64)
 		return 100, 100 // 返回默认尺寸
 	}
 
-	totalWidth, totalHeight := 0.0, 0.0
-	for _, colWidth := range sheet.colWidthMap {
-		totalWidth += colWidth * 7
-	}
-	for _, rowHeight := range sheet.rowHeightMap {
-		totalHeight += rowHeight * 1.33
-	}
-	return totalWidth, totalHeight
+	grid := newGridLayout(sheet)
+	return grid.width(), grid.height()
 }
 
 // defaultBorderColor 返回默认边框颜色（浅灰色）
@@ -220,38 +420,36 @@ func equalColor(a, b color.Color) bool {
 	return ar == br && ag == bg && ab == bb && aa == ba
 }
 
-// drawBaseGrid 使用行/列端点绘制整张默认网格
-func (sr *SheetRenderer) drawBaseGrid(canvas *gg.Context, sheet *Sheet) {
-	def := defaultBorderColor()
-	canvas.SetColor(def)
-
-	// 列偏移与总宽
-	colOffsets := make([]float64, sheet.Cols+1)
-	for c := 1; c <= sheet.Cols; c++ {
-		colName, _ := excelize.ColumnNumberToName(c)
-		colWidth := sheet.GetColWidth(colName) * 7
-		colOffsets[c] = colOffsets[c-1] + colWidth
+// gridColor 返回默认网格线颜色
+func (sr *SheetRenderer) gridColor() color.Color {
+	if sr.gridlineColor != nil {
+		return sr.gridlineColor
 	}
-	totalWidth := colOffsets[sheet.Cols]
+	return defaultBorderColor()
+}
 
-	// 行偏移与总高
-	rowOffsets := make([]float64, sheet.Rows+1)
-	for r := 1; r <= sheet.Rows; r++ {
-		rowHeight := sheet.GetRowHeight(r) * 1.33
-		rowOffsets[r] = rowOffsets[r-1] + rowHeight
-	}
-	totalHeight := rowOffsets[sheet.Rows]
+// drawBaseGrid 使用行/列端点绘制默认网格（仅绘制可视区域内的网格线）
+func (sr *SheetRenderer) drawBaseGrid(canvas *gg.Context, grid *gridLayout, view viewport) {
+	canvas.SetColor(sr.gridColor())
+
+	// 网格线在可视区域内的起止位置
+	left, right := math.Max(view.x, 0), math.Min(view.x+view.w, grid.width())
+	top, bottom := math.Max(view.y, 0), math.Min(view.y+view.h, grid.height())
 
 	// 竖线
-	for i := 0; i <= sheet.Cols; i++ {
-		x := colOffsets[i]
-		canvas.DrawLine(x, 0, x, totalHeight)
+	for _, x := range grid.cols {
+		if x < view.x-1 || x > view.x+view.w+1 {
+			continue
+		}
+		canvas.DrawLine(x, top, x, bottom)
 		canvas.Stroke()
 	}
 	// 横线
-	for i := 0; i <= sheet.Rows; i++ {
-		y := rowOffsets[i]
-		canvas.DrawLine(0, y, totalWidth, y)
+	for _, y := range grid.rows {
+		if y < view.y-1 || y > view.y+view.h+1 {
+			continue
+		}
+		canvas.DrawLine(left, y, right, y)
 		canvas.Stroke()
 	}
 }
@@ -261,11 +459,11 @@ func (sr *SheetRenderer) drawCellBordersOverride(canvas *gg.Context, rect struct
 	if cell == nil {
 		return
 	}
-	style, err := cell.Style()
+	style, err := cell.RenderStyle()
 	if err != nil || style == nil {
 		return
 	}
-	def := defaultBorderColor()
+	def := sr.gridColor()
 	// 遍历样式边框定义，按边绘制
 	for _, b := range style.Border {
 		if b.Color == "" {
@@ -275,8 +473,8 @@ func (sr *SheetRenderer) drawCellBordersOverride(canvas *gg.Context, rect struct
 		if err != nil {
 			continue
 		}
-		if equalColor(col, def) {
-			// 与默认色一致则无需覆盖
+		if !sr.hideGridlines && equalColor(col, def) {
+			// 与网格线颜色一致则无需覆盖
 			continue
 		}
 		canvas.SetColor(col)
@@ -299,18 +497,39 @@ func (sr *SheetRenderer) drawCellBordersOverride(canvas *gg.Context, rect struct
 	}
 }
 
-// GetFont 获取字体
+// GetFont 获取内置字体
 func (sr *SheetRenderer) GetFont(size float64, bold bool) (font.Face, error) {
-	mapKey := fmt.Sprintf("%f|%t", size, bold)
-	if f, ok := sr.fontMap[mapKey]; ok {
-		return f, nil
-	}
-	f, err := LoadDefaultFontWithSize(size, bold)
-	if err != nil {
-		return nil, err
+	return sr.GetFontFace("", size, bold, false)
+}
+
+// GetFontFace 按字体族名获取字体：优先从字体注册表匹配（含别名），无匹配时回退到内置字体
+// 渲染之外调用时返回渲染器共用的 Face（渲染使用各自独占的 Face）；font.Face 不是并发安全的，
+// 调用方不应在多个 goroutine 中同时使用返回的 Face
+func (sr *SheetRenderer) GetFontFace(family string, size float64, bold, italic bool) (face font.Face, err error) {
+	sr.withFonts(func(fs *fontSet) {
+		face, err = fs.face(family, size, bold, italic)
+	})
+	return face, err
+}
+
+// fontChain 返回字形后备链：主字体、已注册的后备字体，最后是内置字体
+func (sr *SheetRenderer) fontChain(primary font.Face, size float64, bold bool) (chain []font.Face) {
+	sr.withFonts(func(fs *fontSet) {
+		chain = fs.chain(primary, size, bold)
+	})
+	return chain
+}
+
+// withFonts 以当前渲染独占的字体集合调用 fn；不在渲染期间时使用字体缓存中共用的集合
+func (sr *SheetRenderer) withFonts(fn func(fs *fontSet)) {
+	switch {
+	case sr.faces != nil:
+		fn(sr.faces)
+	case sr.fontCache != nil:
+		sr.fontCache.withShared(fn)
+	default:
+		fn(newFontSet(sr.fonts, sr.fallbackFamilies))
 	}
-	sr.fontMap[mapKey] = f
-	return f, nil
 }
 
 // drawImages 绘制工作表中的嵌入图片
@@ -345,15 +564,19 @@ func (sr *SheetRenderer) drawImages(canvas *gg.Context, sheet *Sheet, cellRects
 			finalHeight = imgHeight
 		}
 
+		// 可视区域外的图片无需绘制
+		if !canvasIntersects(canvas, x, y, finalWidth, finalHeight) {
+			continue
+		}
+
+		// 计算设备像素坐标和目标尺寸
+		deviceX, deviceY := canvas.TransformPoint(x, y)
+
 		// 绘制图片需要保持原始尺寸比例
 		canvas.Push()
 		canvas.Identity() // 重置变换，使用设备像素坐标
-
-		// 计算设备像素坐标和目标尺寸
-		deviceX := x * scale
-		deviceY := y * scale
-		targetWidth := finalWidth * scale
-		targetHeight := finalHeight * scale
+		targetWidth := finalWidth * sr.scale
+		targetHeight := finalHeight * sr.scale
 
 		// 计算缩放比例并应用变换
 		scaleX := targetWidth / imgWidth
@@ -381,11 +604,9 @@ func (sr *SheetRenderer) calculateImagePosition(img *ExcelImage, cellRects map[s
 	var offsetX, offsetY float64
 
 	if img.X != 0 || img.Y != 0 {
-		// 有偏移量，需要转换
-		// Excel偏移量可能是EMU单位，转换为像素
-		// 粗略估算：1个EMU单位 ≈ 0.0008像素（需要根据实际情况调整）
-		offsetX = float64(img.X) * 0.0008
-		offsetY = float64(img.Y) * 0.0008
+		// excelize 的偏移量已按 EMU/9525 换算为 96 DPI 像素，与绘制坐标一致
+		offsetX = float64(img.X)
+		offsetY = float64(img.Y)
 
 		sr.logger.Debug("图片偏移量转换",
 			zap.String("image", img.Name),
@@ -400,3 +621,233 @@ func (sr *SheetRenderer) calculateImagePosition(img *ExcelImage, cellRects map[s
 
 	return x, y
 }
+
+// drawDropdownHints 绘制筛选按钮与数据验证下拉箭头
+func (sr *SheetRenderer) drawDropdownHints(canvas *gg.Context, sheet *Sheet, cellRects map[string]struct{ x, y, w, h float64 }) {
+	buttons := make(map[string]bool)
+	if sr.showFilterButtons {
+		for _, addr := range sheet.FilterButtonCells() {
+			rect, ok := cellRects[addr]
+			if !ok {
+				continue
+			}
+			buttons[addr] = true
+			sr.drawDropdownButton(canvas, rect, true)
+		}
+	}
+	if !sr.showValidationDropdowns || len(sheet.listValidations) == 0 {
+		return
+	}
+	for addr, rect := range cellRects {
+		if buttons[addr] {
+			continue
+		}
+		cell := sheet.cells.get(addr)
+		if cell == nil || (cell.IsMerged && cell.MergedRange[0] != addr) {
+			continue
+		}
+		if sheet.HasListValidation(cell.Row, cell.Col) {
+			sr.drawDropdownButton(canvas, rect, false)
+		}
+	}
+}
+
+// drawDropdownButton 在单元格右侧绘制下拉按钮：boxed 为筛选按钮样式（带底色与边框），否则仅绘制箭头
+func (sr *SheetRenderer) drawDropdownButton(canvas *gg.Context, rect struct{ x, y, w, h float64 }, boxed bool) {
+	size := math.Min(rect.h-2, 14)
+	if size <= 4 || rect.w <= size {
+		return
+	}
+	x := rect.x + rect.w - size - 1
+	y := rect.y + rect.h - size - 1
+
+	canvas.Push()
+	canvas.SetLineWidth(sr.scale / 2)
+	if boxed {
+		canvas.SetColor(color.RGBA{R: 240, G: 240, B: 240, A: 255})
+		canvas.DrawRectangle(x, y, size, size)
+		canvas.Fill()
+		canvas.SetColor(color.RGBA{R: 171, G: 171, B: 171, A: 255})
+		canvas.DrawRectangle(x, y, size, size)
+		canvas.Stroke()
+	}
+	// 向下的三角形箭头
+	cx, cy := x+size/2, y+size/2
+	half := size / 4
+	canvas.SetColor(color.RGBA{R: 68, G: 68, B: 68, A: 255})
+	canvas.MoveTo(cx-half, cy-half/2)
+	canvas.LineTo(cx+half, cy-half/2)
+	canvas.LineTo(cx, cy+half/2)
+	canvas.ClosePath()
+	canvas.Fill()
+	canvas.Pop()
+}
+
+// drawSparklines 在迷你图所在单元格内绘制迷你图
+func (sr *SheetRenderer) drawSparklines(canvas *gg.Context, sheet *Sheet, cellRects map[string]struct{ x, y, w, h float64 }) {
+	for _, sp := range sheet.sparklines {
+		rect, ok := cellRects[sp.Cell]
+		if !ok {
+			continue
+		}
+		sr.drawSparkline(canvas, rect, sp)
+	}
+}
+
+// drawSparkline 绘制单个迷你图（折线、柱形、盈亏）
+func (sr *SheetRenderer) drawSparkline(canvas *gg.Context, rect struct{ x, y, w, h float64 }, sp *Sparkline) {
+	g := sp.Group
+	n := len(sp.Values)
+	if n == 0 {
+		return
+	}
+	values := make([]float64, n)
+	for i, v := range sp.Values {
+		if g.Reverse {
+			i = n - 1 - i
+		}
+		if math.IsNaN(v) && g.EmptyAs == "zero" {
+			v = 0
+		}
+		values[i] = v
+	}
+
+	const pad = 3.0
+	x0, y0 := rect.x+pad, rect.y+pad
+	w, h := rect.w-2*pad, rect.h-2*pad
+	if w <= 0 || h <= 0 {
+		return
+	}
+	lo, hi := sp.axisRange()
+	if math.IsInf(lo, 0) || math.IsInf(hi, 0) {
+		return
+	}
+	if g.Type == "column" {
+		lo, hi = math.Min(lo, 0), math.Max(hi, 0)
+	}
+	if hi == lo {
+		lo, hi = lo-1, hi+1
+	}
+	yOf := func(v float64) float64 {
+		v = math.Max(lo, math.Min(hi, v))
+		return y0 + h - (v-lo)/(hi-lo)*h
+	}
+
+	// 计算最高/最低点索引
+	highIdx, lowIdx := -1, -1
+	for i, v := range values {
+		if math.IsNaN(v) {
+			continue
+		}
+		if highIdx < 0 || v > values[highIdx] {
+			highIdx = i
+		}
+		if lowIdx < 0 || v < values[lowIdx] {
+			lowIdx = i
+		}
+	}
+	// pointColor 返回数据点的强调色（无强调时返回空串）
+	pointColor := func(i int, v float64) string {
+		switch {
+		case g.High && i == highIdx:
+			return g.ColorHigh
+		case g.Low && i == lowIdx:
+			return g.ColorLow
+		case g.First && i == 0:
+			return g.ColorFirst
+		case g.Last && i == n-1:
+			return g.ColorLast
+		case g.Negative && v < 0:
+			return g.ColorNegative
+		}
+		return ""
+	}
+	setHex := func(hex string) {
+		if c, err := HexToRGBA(hex); err == nil {
+			canvas.SetColor(c)
+		}
+	}
+
+	canvas.Push()
+	defer canvas.Pop()
+
+	step := w / float64(n)
+	switch g.Type {
+	case "column", "stacked":
+		barW := step * 0.8
+		base := yOf(0)
+		if g.Type == "stacked" {
+			base = y0 + h/2
+		}
+		for i, v := range values {
+			if math.IsNaN(v) || v == 0 {
+				continue
+			}
+			x := x0 + float64(i)*step + (step-barW)/2
+			top := yOf(v)
+			if g.Type == "stacked" {
+				top = base - h/2
+				if v < 0 {
+					top = base + h/2
+				}
+			}
+			if c := pointColor(i, v); c != "" {
+				setHex(c)
+			} else {
+				setHex(g.ColorSeries)
+			}
+			canvas.DrawRectangle(x, math.Min(top, base), barW, math.Abs(base-top))
+			canvas.Fill()
+		}
+	default:
+		// 折线：空值按 gap 断开、按 span 跨越
+		canvas.SetLineWidth(g.LineWeight * pixelsPerPoint * sr.scale)
+		setHex(g.ColorSeries)
+		started := false
+		for i, v := range values {
+			if math.IsNaN(v) {
+				if g.EmptyAs != "span" {
+					started = false
+				}
+				continue
+			}
+			x := x0 + float64(i)*step + step/2
+			if started {
+				canvas.LineTo(x, yOf(v))
+			} else {
+				canvas.MoveTo(x, yOf(v))
+				started = true
+			}
+		}
+		canvas.Stroke()
+
+		radius := math.Max(g.LineWeight*pixelsPerPoint, 1.5)
+		for i, v := range values {
+			if math.IsNaN(v) {
+				continue
+			}
+			c := pointColor(i, v)
+			if c == "" {
+				if !g.Markers {
+					continue
+				}
+				c = g.ColorMarkers
+			}
+			setHex(c)
+			canvas.DrawCircle(x0+float64(i)*step+step/2, yOf(v), radius)
+			canvas.Fill()
+		}
+	}
+
+	// 横轴：仅当数据跨越 0 时绘制
+	if g.ShowAxis && lo < 0 && hi > 0 {
+		canvas.SetLineWidth(sr.scale / 2)
+		setHex(g.ColorAxis)
+		axisY := yOf(0)
+		if g.Type == "stacked" {
+			axisY = y0 + h/2
+		}
+		canvas.DrawLine(x0, axisY, x0+w, axisY)
+		canvas.Stroke()
+	}
+}
@@ -0,0 +1,114 @@
+package excelsnapshot
+
+import "image/color"
+
+// RendererOption SheetRenderer 的可选配置
+// 既可在 NewSheetRenderer 时设置默认值，也可传给 RenderSheet、RenderSheetContext、RenderTo 只作用于单次渲染
+type RendererOption func(*SheetRenderer)
+
+// WithFilterButtons 在自动筛选区域与表格标题行的单元格中绘制筛选下拉按钮
+func WithFilterButtons(show bool) RendererOption {
+	return func(sr *SheetRenderer) {
+		sr.showFilterButtons = show
+	}
+}
+
+// WithValidationDropdowns 在设置了下拉列表数据验证的单元格右侧绘制下拉箭头提示
+func WithValidationDropdowns(show bool) RendererOption {
+	return func(sr *SheetRenderer) {
+		sr.showValidationDropdowns = show
+	}
+}
+
+// WithShowFormulas 显示公式模式（类似 Excel 的 Ctrl+`）：公式单元格显示公式文本而非计算结果
+func WithShowFormulas(show bool) RendererOption {
+	return func(sr *SheetRenderer) {
+		sr.showFormulas = show
+	}
+}
+
+// WithErrorIndicators 在结果为错误值的公式单元格左上角绘制绿色错误提示三角
+func WithErrorIndicators(show bool) RendererOption {
+	return func(sr *SheetRenderer) {
+		sr.showErrorIndicators = show
+	}
+}
+
+// WithFontRegistry 使用字体注册表按单元格字体族名选择字体（无匹配时回退到内置字体）
+func WithFontRegistry(fonts *FontRegistry) RendererOption {
+	return func(sr *SheetRenderer) {
+		sr.fonts = fonts
+	}
+}
+
+// WithFallbackFonts 设置后备字体族名（需已在字体注册表中加载），按顺序补全主字体缺失的字形
+// 例如混排阿拉伯文、泰文与表情符号时依次尝试对应字体，最终回退到内置字体
+func WithFallbackFonts(families ...string) RendererOption {
+	return func(sr *SheetRenderer) {
+		sr.fallbackFamilies = append([]string(nil), families...)
+	}
+}
+
+// WithScale 设置输出缩放比例（相对 96 DPI），如 1 用于缩略图、3 用于打印；非正数时忽略
+func WithScale(scale float64) RendererOption {
+	return func(sr *SheetRenderer) {
+		if scale > 0 {
+			sr.scale = scale
+		}
+	}
+}
+
+// WithDPI 按输出分辨率设置缩放比例（96 DPI 对应 1 倍）；非正数时忽略
+func WithDPI(dpi float64) RendererOption {
+	return WithScale(dpi / 96)
+}
+
+// WithMaxSize 限制输出图片的最大宽高（像素，0 表示不限制）
+// 超出时在栅格化之前自动减小缩放比例，保证文字与线条清晰
+func WithMaxSize(width, height int) RendererOption {
+	return func(sr *SheetRenderer) {
+		sr.maxWidth = max(width, 0)
+		sr.maxHeight = max(height, 0)
+	}
+}
+
+// WithRenderLimits 设置渲染的资源限制（MaxPixels 与 MaxDuration），超出时渲染返回 *LimitError
+// 与 WithMaxSize 不同，超出 MaxPixels 时不会缩小输出，适合拒绝异常的输入
+func WithRenderLimits(limits Limits) RendererOption {
+	return func(sr *SheetRenderer) {
+		sr.limits = limits
+	}
+}
+
+// WithBackground 设置画布背景色（默认为白色）；为 nil 时背景透明
+// JPEG 不支持透明，EncodeImage 编码为 JPEG 时透明部分按白色输出
+func WithBackground(c color.Color) RendererOption {
+	return func(sr *SheetRenderer) {
+		if c == nil {
+			c = color.Transparent
+		}
+		sr.background = c
+	}
+}
+
+// WithGridlines 是否绘制默认网格线（默认绘制）；隐藏时仍绘制单元格设置的边框
+func WithGridlines(show bool) RendererOption {
+	return func(sr *SheetRenderer) {
+		sr.hideGridlines = !show
+	}
+}
+
+// WithGridlineColor 设置默认网格线颜色；为 nil 时恢复默认的浅灰色
+func WithGridlineColor(c color.Color) RendererOption {
+	return func(sr *SheetRenderer) {
+		sr.gridlineColor = c
+	}
+}
+
+// WithRange 只渲染已加载工作表中的指定区域（如 "B2:H40"），为空时渲染整个工作表
+// 与 Excel.GetSheetRange 不同，工作表仍完整加载，区域外的合并单元格与溢出文本按区域边界裁剪
+func WithRange(ref string) RendererOption {
+	return func(sr *SheetRenderer) {
+		sr.cellRange = ref
+	}
+}
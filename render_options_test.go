@@ -0,0 +1,177 @@
+package excelsnapshot
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap/zaptest"
+)
+
+// createTestOptionsSheet 创建 A1:D4 的测试工作表，只有 A1 有值，其余为空单元格
+func createTestOptionsSheet(t *testing.T) *Sheet {
+	t.Helper()
+	f := excelize.NewFile()
+	f.SetCellValue("Sheet1", "A1", "标题")
+	f.SetCellValue("Sheet1", "D4", "")
+	f.SetSheetDimension("Sheet1", "A1:D4")
+	buf, err := f.WriteToBuffer()
+	f.Close()
+	if err != nil {
+		t.Fatalf("写入工作簿失败: %v", err)
+	}
+	excel, err := NewExcelFromBytes(buf.Bytes(), zaptest.NewLogger(t))
+	if err != nil {
+		t.Fatalf("加载Excel失败: %v", err)
+	}
+	t.Cleanup(func() { excel.Close() })
+	sheet, err := excel.GetSheet("Sheet1")
+	if err != nil {
+		t.Fatalf("获取工作表失败: %v", err)
+	}
+	return sheet
+}
+
+// cellCenter 返回单元格中心在输出图片中的坐标
+func cellCenter(sheet *Sheet, scale float64, col, row int) (int, int) {
+	grid := newGridLayout(sheet)
+	x := (grid.colX(col) + grid.colX(col+1)) / 2
+	y := (grid.rowY(row) + grid.rowY(row+1)) / 2
+	return int(x * scale), int(y * scale)
+}
+
+// rowHasColor 判断图片中经过 y 的水平线上是否存在满足 match 的像素
+func rowHasColor(img image.Image, y int, match func(c color.RGBA) bool) bool {
+	b := img.Bounds()
+	for x := b.Min.X; x < b.Max.X; x++ {
+		if match(color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)) {
+			return true
+		}
+	}
+	return false
+}
+
+// TestSheetRenderer_RenderOptions 测试按次传入的渲染选项只作用于本次渲染
+func TestSheetRenderer_RenderOptions(t *testing.T) {
+	sheet := createTestOptionsSheet(t)
+	renderer := NewSheetRenderer(zaptest.NewLogger(t))
+	scale := renderer.Scale()
+	cx, cy := cellCenter(sheet, scale, 3, 3)
+	white := color.RGBA{255, 255, 255, 255}
+	notWhite := func(c color.RGBA) bool { return c != white }
+	bluish := func(c color.RGBA) bool { return c.B > c.R+50 }
+
+	tests := []struct {
+		name      string
+		opts      []RendererOption
+		wantPixel color.RGBA
+		// 空白行中是否存在网格线像素
+		wantGrid func(c color.RGBA) bool
+	}{
+		{"默认", nil, white, notWhite},
+		{"透明背景", []RendererOption{WithBackground(nil)}, color.RGBA{}, notWhite},
+		{"背景色", []RendererOption{WithBackground(color.RGBA{255, 0, 0, 255})}, color.RGBA{255, 0, 0, 255}, notWhite},
+		{"隐藏网格线", []RendererOption{WithGridlines(false)}, white, nil},
+		{"网格线颜色", []RendererOption{WithGridlineColor(color.RGBA{0, 0, 255, 255})}, white, bluish},
+		{"单次指定字体", []RendererOption{WithFontRegistry(NewFontRegistry(nil))}, white, notWhite},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := renderer.RenderSheet(sheet, tt.opts...)
+			if err != nil {
+				t.Fatalf("RenderSheet() 失败: %v", err)
+			}
+			if got := color.RGBAModel.Convert(img.At(cx, cy)).(color.RGBA); got != tt.wantPixel {
+				t.Errorf("空单元格中心像素 = %v, want %v", got, tt.wantPixel)
+			}
+			if tt.wantGrid == nil {
+				if rowHasColor(img, cy, notWhite) {
+					t.Errorf("隐藏网格线时空白行中不应有非白色像素")
+				}
+			} else if !rowHasColor(img, cy, tt.wantGrid) {
+				t.Errorf("空白行中没有期望颜色的网格线")
+			}
+
+			// 单次选项不影响渲染器的默认配置
+			img, err = renderer.RenderSheet(sheet)
+			if err != nil {
+				t.Fatalf("RenderSheet() 失败: %v", err)
+			}
+			if got := color.RGBAModel.Convert(img.At(cx, cy)).(color.RGBA); got != white {
+				t.Errorf("默认渲染的空单元格中心像素 = %v, want %v", got, white)
+			}
+		})
+	}
+}
+
+// TestSheetRenderer_WithRange 测试只渲染指定区域
+func TestSheetRenderer_WithRange(t *testing.T) {
+	sheet := createTestOptionsSheet(t)
+	renderer := NewSheetRenderer(zaptest.NewLogger(t))
+	scale := renderer.Scale()
+	grid := newGridLayout(sheet)
+
+	tests := []struct {
+		name    string
+		ref     string
+		wantW   float64
+		wantH   float64
+		wantErr bool
+	}{
+		{"整个工作表", "", grid.width(), grid.height(), false},
+		{"区域", "B2:C3", grid.colX(4) - grid.colX(2), grid.rowY(4) - grid.rowY(2), false},
+		{"单个单元格", "D4", grid.colX(5) - grid.colX(4), grid.rowY(5) - grid.rowY(4), false},
+		{"超出渲染区域的部分被截断", "C3:Z100", grid.colX(5) - grid.colX(3), grid.rowY(5) - grid.rowY(3), false},
+		{"不在渲染区域内", "X100:Y200", 0, 0, true},
+		{"无效的区域", "B2:", 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := renderer.RenderSheet(sheet, WithRange(tt.ref))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RenderSheet() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			wantW, wantH := int(math.Ceil(tt.wantW*scale)), int(math.Ceil(tt.wantH*scale))
+			if img.Bounds().Dx() != wantW || img.Bounds().Dy() != wantH {
+				t.Errorf("图片尺寸 = %v, want %dx%d", img.Bounds().Size(), wantW, wantH)
+			}
+
+			manifest, err := NewSheetRenderer(zaptest.NewLogger(t), WithRange(tt.ref)).RenderTiles(sheet, 64, func(Tile, image.Image) error { return nil })
+			if err != nil {
+				t.Fatalf("RenderTiles() 失败: %v", err)
+			}
+			if manifest.Width != wantW || manifest.Height != wantH {
+				t.Errorf("图块整图尺寸 = %dx%d, want %dx%d", manifest.Width, manifest.Height, wantW, wantH)
+			}
+
+			// 按次传入的 WithRange 与渲染器默认配置的结果一致
+			manifest, err = renderer.RenderTiles(sheet, 64, func(Tile, image.Image) error { return nil }, WithRange(tt.ref))
+			if err != nil {
+				t.Fatalf("RenderTiles() 失败: %v", err)
+			}
+			if manifest.Width != wantW || manifest.Height != wantH {
+				t.Errorf("按次指定区域的图块整图尺寸 = %dx%d, want %dx%d", manifest.Width, manifest.Height, wantW, wantH)
+			}
+		})
+	}
+
+	// 区域渲染的结果与整图的对应部分一致
+	full, err := renderer.RenderSheet(sheet)
+	if err != nil {
+		t.Fatalf("RenderSheet() 失败: %v", err)
+	}
+	part, err := renderer.RenderSheet(sheet, WithRange("C3:D4"))
+	if err != nil {
+		t.Fatalf("RenderSheet() 失败: %v", err)
+	}
+	cx, cy := cellCenter(sheet, scale, 3, 3)
+	ox, oy := int(grid.colX(3)*scale), int(grid.rowY(3)*scale)
+	if got, want := part.At(cx-ox, cy-oy), full.At(cx, cy); got != want {
+		t.Errorf("区域渲染的像素 = %v, want %v", got, want)
+	}
+}
@@ -1,9 +1,14 @@
 package excelsnapshot
 
 import (
+	"bytes"
 	"fmt"
+	"image"
+	"image/draw"
+	"math"
 	"math/rand"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
@@ -67,6 +72,87 @@ func TestSheetRenderer_RenderSheet(t *testing.T) {
 	}
 }
 
+// TestSheetRenderer_RenderSheet_Concurrent 测试多个 goroutine 共用同一个渲染器并发渲染，
+// 结果应与串行渲染一致（配合 go test -race 检查数据竞争）
+func TestSheetRenderer_RenderSheet_Concurrent(t *testing.T) {
+	f := excelize.NewFile()
+	names := []string{"Sheet1", "Sheet2"}
+	for i, name := range names {
+		if i > 0 {
+			f.NewSheet(name)
+		}
+		if err := createComplexWorksheet(f, name, 20+10*i, 6); err != nil {
+			t.Fatalf("创建工作表失败: %v", err)
+		}
+	}
+	buf, err := f.WriteToBuffer()
+	f.Close()
+	if err != nil {
+		t.Fatalf("写入工作簿失败: %v", err)
+	}
+
+	logger := zaptest.NewLogger(t)
+	excel, err := NewExcelFromBytes(buf.Bytes(), logger)
+	if err != nil {
+		t.Fatalf("加载Excel失败: %v", err)
+	}
+	defer excel.Close()
+
+	renderer := NewSheetRenderer(logger)
+	want := make(map[string]*image.RGBA)
+	for _, name := range names {
+		sheet, err := excel.GetSheet(name)
+		if err != nil {
+			t.Fatalf("获取工作表失败: %v", err)
+		}
+		img, err := renderer.RenderSheet(sheet)
+		if err != nil {
+			t.Fatalf("RenderSheet() 失败: %v", err)
+		}
+		want[name] = toRGBA(img)
+	}
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		name := names[i%len(names)]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sheet, err := excel.GetSheet(name)
+			if err != nil {
+				t.Errorf("获取工作表失败: %v", err)
+				return
+			}
+			if i%4 == 3 {
+				// 瓦片渲染与整表渲染共用同一个渲染器
+				if _, err := renderer.RenderTiles(sheet, 128, func(Tile, image.Image) error { return nil }); err != nil {
+					t.Errorf("RenderTiles() 失败: %v", err)
+				}
+				return
+			}
+			img, err := renderer.RenderSheet(sheet)
+			if err != nil {
+				t.Errorf("RenderSheet() 失败: %v", err)
+				return
+			}
+			if got := toRGBA(img); !bytes.Equal(got.Pix, want[name].Pix) {
+				t.Errorf("工作表 %q 的并发渲染结果与串行渲染不一致", name)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// toRGBA 将图片转换为 *image.RGBA 以便逐像素比较
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok {
+		return rgba
+	}
+	rgba := image.NewRGBA(img.Bounds())
+	draw.Draw(rgba, rgba.Bounds(), img, img.Bounds().Min, draw.Src)
+	return rgba
+}
+
 // TestSheetRenderer_calculateCellRects 测试单元格矩形计算
 func TestSheetRenderer_calculateCellRects(t *testing.T) {
 	tempDir := t.TempDir()
@@ -204,6 +290,115 @@ func TestSheetRenderer_InvalidInput(t *testing.T) {
 	}
 }
 
+// TestSheetRenderer_Scale 测试输出缩放比例与 DPI 选项
+func TestSheetRenderer_Scale(t *testing.T) {
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "scale_test.xlsx")
+	if err := createTestExcelFile(testFile); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+
+	logger := zaptest.NewLogger(t)
+	excel, err := NewExcel(testFile, logger)
+	if err != nil {
+		t.Fatalf("加载Excel文件失败: %v", err)
+	}
+	defer excel.Close()
+
+	sheet, err := excel.GetSheet("Sheet1")
+	if err != nil {
+		t.Fatalf("获取工作表失败: %v", err)
+	}
+	if got := sheet.RowHeightPixels(1); got != sheet.GetRowHeight(1)*4/3 {
+		t.Errorf("RowHeightPixels(1) = %v, want %v", got, sheet.GetRowHeight(1)*4/3)
+	}
+
+	base, _ := NewSheetRenderer(logger).getSheetWidthAndHeight(sheet)
+	tests := []struct {
+		name  string
+		opts  []RendererOption
+		scale float64
+	}{
+		{"默认", nil, defaultScale},
+		{"缩略图", []RendererOption{WithScale(1)}, 1},
+		{"打印", []RendererOption{WithDPI(288)}, 3},
+		{"忽略非法值", []RendererOption{WithScale(-1), WithDPI(0)}, defaultScale},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renderer := NewSheetRenderer(logger, tt.opts...)
+			if renderer.Scale() != tt.scale {
+				t.Fatalf("Scale() = %v, want %v", renderer.Scale(), tt.scale)
+			}
+			img, err := renderer.RenderSheet(sheet)
+			if err != nil {
+				t.Fatalf("RenderSheet() 失败: %v", err)
+			}
+			if got, want := img.Bounds().Dx(), int(math.Ceil(base*tt.scale)); got != want {
+				t.Errorf("图片宽度 = %d, want %d", got, want)
+			}
+		})
+	}
+}
+
+// TestSheetRenderer_MaxSize 测试最大尺寸限制下的缩放比例选择
+func TestSheetRenderer_MaxSize(t *testing.T) {
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "max_size_test.xlsx")
+	if err := createTestExcelFile(testFile); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+
+	logger := zaptest.NewLogger(t)
+	excel, err := NewExcel(testFile, logger)
+	if err != nil {
+		t.Fatalf("加载Excel文件失败: %v", err)
+	}
+	defer excel.Close()
+
+	sheet, err := excel.GetSheet("Sheet1")
+	if err != nil {
+		t.Fatalf("获取工作表失败: %v", err)
+	}
+
+	full, err := NewSheetRenderer(logger).RenderSheet(sheet)
+	if err != nil {
+		t.Fatalf("RenderSheet() 失败: %v", err)
+	}
+	fullW, fullH := full.Bounds().Dx(), full.Bounds().Dy()
+
+	tests := []struct {
+		name          string
+		width, height int
+		wantW         int // 期望宽度（0 表示只检查不超过限制）
+	}{
+		{"不限制", 0, 0, fullW},
+		{"限制宽度", fullW / 2, 0, fullW / 2},
+		{"限制高度", 0, fullH / 3, 0},
+		{"限制大于原图时不放大", fullW * 2, fullH * 2, fullW},
+		{"缩略图", 64, 64, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renderer := NewSheetRenderer(logger, WithMaxSize(tt.width, tt.height))
+			img, err := renderer.RenderSheet(sheet)
+			if err != nil {
+				t.Fatalf("RenderSheet() 失败: %v", err)
+			}
+			w, h := img.Bounds().Dx(), img.Bounds().Dy()
+			if tt.width > 0 && w > tt.width || tt.height > 0 && h > tt.height {
+				t.Errorf("图片尺寸 %dx%d 超出限制 %dx%d", w, h, tt.width, tt.height)
+			}
+			if tt.wantW > 0 && w != tt.wantW {
+				t.Errorf("图片宽度 = %d, want %d", w, tt.wantW)
+			}
+			if renderer.Scale() != defaultScale {
+				t.Error("最大尺寸限制不应修改渲染器的缩放比例")
+			}
+		})
+	}
+}
+
 // BenchmarkSheetRenderer_RenderSheet 基准测试渲染性能
 func BenchmarkSheetRenderer_RenderSheet(b *testing.B) {
 	tempDir := b.TempDir()
@@ -2,6 +2,7 @@ package excelsnapshot
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"image"
 	"image/gif"
@@ -35,16 +36,32 @@ type Sheet struct {
 	Cols       int
 	MaxColName string
 
+	// 渲染区域的起始行列（1-based），按区域加载时大于 1
+	startRow, startCol int
+
 	// 不同行的行高
 	rowHeightMap map[int]float64
 	// 不同列的列宽
 	colWidthMap map[string]float64
 	// 单元格
-	cells map[string]*Cell
+	cells *cellIndex
 	// 样式
 	styles map[int]*excelize.Style
 	// 工作表中的图片
 	images []*ExcelImage
+	// 工作表中的表格（ListObject）
+	tables []*SheetTable
+	// 自动筛选区域
+	autoFilter *cellRange
+	// 下拉列表数据验证区域
+	listValidations []cellRange
+	// 迷你图
+	sparklines []*Sparkline
+
+	// 加载期间独占的字体集合，用于自动列宽与行高的文本度量
+	fonts *fontSet
+	// 加载期间的取消与资源限制检查
+	guard *loadGuard
 }
 
 // NewSheet 构造函数，仅建立与 Excel 的关联与名称，实际数据通过 Load 加载
@@ -52,9 +69,11 @@ func NewSheet(e *Excel, name string) *Sheet {
 	sheet := &Sheet{
 		excel:        e,
 		Name:         name,
+		startRow:     1,
+		startCol:     1,
 		rowHeightMap: make(map[int]float64),
 		colWidthMap:  make(map[string]float64),
-		cells:        make(map[string]*Cell),
+		cells:        newCellIndex(),
 		styles:       make(map[int]*excelize.Style),
 	}
 	return sheet
@@ -62,6 +81,37 @@ func NewSheet(e *Excel, name string) *Sheet {
 
 // Load 加载工作表数据
 func (s *Sheet) Load() error {
+	return s.LoadContext(context.Background())
+}
+
+// LoadContext 加载工作表数据，ctx 取消或超出 WithLoadLimits 设置的资源限制时中止并返回错误
+func (s *Sheet) LoadContext(ctx context.Context) error {
+	release := s.beginLoad(ctx)
+	defer release()
+	if err := s.guard.err(); err != nil {
+		return err
+	}
+	if s.excel.streaming {
+		return s.loadStream(fullSheetRange)
+	}
+	return s.load()
+}
+
+// beginLoad 准备加载期间使用的字体集合与限制检查，返回结束加载的函数
+func (s *Sheet) beginLoad(ctx context.Context) func() {
+	guard, cancel := s.excel.limits.newGuard(ctx, s.Name)
+	s.guard = guard
+	releaseFonts := s.acquireFonts()
+	return func() {
+		releaseFonts()
+		cancel()
+		s.guard = nil
+	}
+}
+
+// load 完整加载工作表
+func (s *Sheet) load() error {
+
 	// 获取所有行数据
 	rows, err := s.excel.file.GetRows(s.Name)
 	if err != nil {
@@ -80,12 +130,15 @@ func (s *Sheet) Load() error {
 
 		for colIndex, value := range row {
 			cellAddr, _ := excelize.CoordinatesToCellName(colIndex+1, rowIndex+1)
-			s.cells[cellAddr] = &Cell{
+			s.cells.put(&Cell{
 				Sheet:   s,
 				Row:     rowIndex + 1,
 				Col:     colIndex + 1,
 				Address: cellAddr,
 				Value:   value,
+			})
+			if err := s.guard.step(s.cells.len()); err != nil {
+				return err
 			}
 		}
 	}
@@ -104,9 +157,12 @@ func (s *Sheet) Load() error {
 			// 补齐该列在 [1..len(colData)] 范围内缺失的单元格
 			for r := 1; r <= len(colData); r++ {
 				addr, _ := excelize.CoordinatesToCellName(colIndex+1, r)
-				if _, ok := s.cells[addr]; !ok {
+				if s.cells.at(r, colIndex+1) == nil {
 					val := colData[r-1]
-					s.cells[addr] = &Cell{Sheet: s, Row: r, Col: colIndex + 1, Address: addr, Value: val}
+					s.cells.put(&Cell{Sheet: s, Row: r, Col: colIndex + 1, Address: addr, Value: val})
+				}
+				if err := s.guard.step(s.cells.len()); err != nil {
+					return err
 				}
 			}
 		}
@@ -119,6 +175,9 @@ func (s *Sheet) Load() error {
 			// 维度形如 A1:D10
 			sc, sr, _ := excelize.CellNameToCoordinates(parts[0])
 			ec, er, _ := excelize.CellNameToCoordinates(parts[1])
+			if err := s.guard.reserve(cellRange{StartCol: sc, StartRow: sr, EndCol: ec, EndRow: er}); err != nil {
+				return err
+			}
 			if er > maxRow {
 				maxRow = er
 			}
@@ -128,14 +187,20 @@ func (s *Sheet) Load() error {
 			for r := sr; r <= er; r++ {
 				for c := sc; c <= ec; c++ {
 					addr, _ := excelize.CoordinatesToCellName(c, r)
-					if _, ok := s.cells[addr]; !ok {
-						s.cells[addr] = &Cell{Sheet: s, Row: r, Col: c, Address: addr, Value: ""}
+					if s.cells.at(r, c) == nil {
+						s.cells.put(&Cell{Sheet: s, Row: r, Col: c, Address: addr, Value: ""})
+					}
+					if err := s.guard.step(s.cells.len()); err != nil {
+						return err
 					}
 				}
 			}
 		} else if len(parts) == 1 {
 			// 单点维度，如 A1
 			ec, er, _ := excelize.CellNameToCoordinates(parts[0])
+			if err := s.guard.reserve(cellRange{StartCol: 1, StartRow: 1, EndCol: ec, EndRow: er}); err != nil {
+				return err
+			}
 			if er > maxRow {
 				maxRow = er
 			}
@@ -145,36 +210,75 @@ func (s *Sheet) Load() error {
 			for r := 1; r <= er; r++ {
 				for c := 1; c <= ec; c++ {
 					addr, _ := excelize.CoordinatesToCellName(c, r)
-					if _, ok := s.cells[addr]; !ok {
-						s.cells[addr] = &Cell{Sheet: s, Row: r, Col: c, Address: addr, Value: ""}
+					if s.cells.at(r, c) == nil {
+						s.cells.put(&Cell{Sheet: s, Row: r, Col: c, Address: addr, Value: ""})
+					}
+					if err := s.guard.step(s.cells.len()); err != nil {
+						return err
 					}
 				}
 			}
 		}
 	}
 
+	// 读取公式文本，并计算缺少缓存值的公式（可选），需在依赖单元格值的步骤之前完成
+	if err := s.loadFormulas(); err != nil {
+		return err
+	}
+	if s.excel.evalFormulas {
+		if err := s.evaluateFormulas(); err != nil {
+			return err
+		}
+	}
+
+	// 加载迷你图（依赖已加载的单元格值）；迷你图所在的空单元格也纳入渲染范围
+	if err := s.loadSparklines(); err != nil {
+		s.excel.logger.Warn("加载迷你图失败", zap.Error(err))
+	}
+	for _, sp := range s.sparklines {
+		if sp.Row > maxRow {
+			maxRow = sp.Row
+		}
+		if sp.Col > maxCol {
+			maxCol = sp.Col
+		}
+		if s.cells.at(sp.Row, sp.Col) == nil {
+			s.cells.put(&Cell{Sheet: s, Row: sp.Row, Col: sp.Col, Address: sp.Cell, Value: ""})
+		}
+	}
+
 	// 仅对已存在的单元格绑定样式并缓存（避免对空区域重复扫描）
 	styleBindCount := 0
 	styleCacheMiss := 0
-	for addr, cell := range s.cells {
-		styleIndex, err := s.excel.file.GetCellStyle(s.Name, addr)
+	err = s.cells.each(func(cell *Cell) error {
+		styleIndex, err := s.excel.file.GetCellStyle(s.Name, cell.Address)
 		if err != nil {
 			return err
 		}
-		if cell.StyleIndex != styleIndex {
-			cell.StyleIndex = styleIndex
-		}
-		if _, ok := s.styles[styleIndex]; !ok {
-			st, err := s.excel.file.GetStyle(styleIndex)
-			if err != nil {
-				return err
-			}
-			s.styles[styleIndex] = st
+		miss, err := s.bindStyle(cell, styleIndex)
+		if miss {
 			styleCacheMiss++
 		}
 		styleBindCount++
+		if err != nil {
+			return err
+		}
+		return s.guard.step(0)
+	})
+	if err != nil {
+		return err
 	}
-	s.excel.logger.Info("加载工作表", zap.String("sheet", s.Name), zap.Int("rows", maxRow), zap.Int("cols", maxCol), zap.Int("cells", len(s.cells)), zap.Int("style_bind", styleBindCount), zap.Int("style_miss", styleCacheMiss))
+	s.excel.logger.Debug("加载工作表", zap.String("sheet", s.Name), zap.Int("rows", maxRow), zap.Int("cols", maxCol), zap.Int("cells", s.cells.len()), zap.Int("style_bind", styleBindCount), zap.Int("style_miss", styleCacheMiss))
+
+	// 优化：批量处理列宽（利用Excel列内宽度统一特性）
+	for col := 1; col <= maxCol; col++ {
+		colLetter, _ := excelize.ColumnNumberToName(col)
+		width, _ := s.excel.file.GetColWidth(s.Name, colLetter)
+		s.colWidthMap[colLetter] = width
+	}
+
+	// 智能列宽调整：确保所有数据完整可见（需在行高估算之前，换行行数依赖列宽）
+	s.optimizeColumnWidths()
 
 	// 优化：批量处理行高（利用Excel行内高度统一特性）
 	for rowNum := 1; rowNum <= maxRow; rowNum++ {
@@ -182,27 +286,15 @@ func (s *Sheet) Load() error {
 
 		// 15 是 Excel 的默认行高，需要通过估算进行调整
 		if height == 15 {
-			// 只需要该行的数据来估算
-			var rowData []string
-			if rowNum-1 < len(rows) {
-				rowData = rows[rowNum-1]
-			}
-			height = s.estimateRowHeight(rowNum, rowData)
+			height = s.estimateRowHeight(rowNum)
 		}
 
 		s.rowHeightMap[rowNum] = height
+		if err := s.guard.step(0); err != nil {
+			return err
+		}
 	}
 
-	// 优化：批量处理列宽（利用Excel列内宽度统一特性）
-	for col := 1; col <= maxCol; col++ {
-		colLetter, _ := excelize.ColumnNumberToName(col)
-		width, _ := s.excel.file.GetColWidth(s.Name, colLetter)
-		s.colWidthMap[colLetter] = width
-	}
-
-	// 智能列宽调整：确保所有数据完整可见
-	s.optimizeColumnWidths()
-
 	// 合并单元格处理
 	mergedCells, err := s.excel.file.GetMergeCells(s.Name)
 	if err != nil {
@@ -211,63 +303,48 @@ func (s *Sheet) Load() error {
 	for _, mergedCell := range mergedCells {
 		startCol, startRow, _ := excelize.CellNameToCoordinates(mergedCell.GetStartAxis())
 		endCol, endRow, _ := excelize.CellNameToCoordinates(mergedCell.GetEndAxis())
-
-		// 构造整个合并区域的地址列表
-		var mergedRange []string
-		for r := startRow; r <= endRow; r++ {
-			for c := startCol; c <= endCol; c++ {
-				addr, _ := excelize.CoordinatesToCellName(c, r)
-				mergedRange = append(mergedRange, addr)
-			}
+		mr := cellRange{StartCol: startCol, StartRow: startRow, EndCol: endCol, EndRow: endRow}
+		if err := s.guard.reserve(mr); err != nil {
+			return err
 		}
-
-		// 给区域内的所有 Cell 打标记
-		for _, addr := range mergedRange {
-			cell, ok := s.cells[addr]
-			if !ok {
-				// 如果之前没加载到值，也新建一个 Cell
-				col, row, _ := excelize.CellNameToCoordinates(addr)
-				cell = &Cell{
-					Sheet:   s,
-					Row:     row,
-					Col:     col,
-					Address: addr,
-					Value:   "",
-				}
-				s.cells[addr] = cell
-			}
-			cell.IsMerged = true
-			cell.MergedRange = mergedRange
+		mainCell := s.markMerged(mr, nil)
+		if err := s.guard.step(s.cells.len()); err != nil {
+			return err
 		}
 
 		// 确保主单元格（左上角）样式已绑定并加入缓存
-		mainAddr := mergedCell.GetStartAxis()
-		if mainCell, ok := s.cells[mainAddr]; ok {
+		if mainCell != nil && mainCell.StyleIndex == 0 {
 			// 若此前未绑定样式，则立即绑定并缓存
-			if mainCell.StyleIndex == 0 {
-				idx, err := s.excel.file.GetCellStyle(s.Name, mainAddr)
-				if err != nil {
-					return err
-				}
-				if mainCell.StyleIndex != idx {
-					mainCell.StyleIndex = idx
-				}
-				if _, ok := s.styles[idx]; !ok {
-					st, err := s.excel.file.GetStyle(idx)
-					if err != nil {
-						return err
-					}
-					s.styles[idx] = st
-					styleCacheMiss++
-				}
-				styleBindCount++
+			idx, err := s.excel.file.GetCellStyle(s.Name, mainCell.Address)
+			if err != nil {
+				return err
+			}
+			miss, err := s.bindStyle(mainCell, idx)
+			if err != nil {
+				return err
+			}
+			if miss {
+				styleCacheMiss++
 			}
+			styleBindCount++
 		}
 	}
 
-	// 加载工作表中的图片
+	// 加载工作表中的表格及其样式
+	if err := s.loadTables(); err != nil {
+		s.excel.logger.Warn("加载表格失败", zap.Error(err))
+	}
+	s.bindTableStyles()
+
+	// 加载自动筛选与下拉列表数据验证（用于绘制筛选按钮/下拉箭头）
+	s.loadAutoFilter()
+	if err := s.loadListValidations(); err != nil {
+		s.excel.logger.Warn("加载数据验证失败", zap.Error(err))
+	}
+
+	// 加载工作表中的图片（单张图片失败时跳过，取消或超出限制时中止）
 	if err := s.loadImages(); err != nil {
-		s.excel.logger.Warn("加载图片失败", zap.Error(err))
+		return err
 	}
 
 	// 更新 sheet 信息
@@ -277,6 +354,65 @@ func (s *Sheet) Load() error {
 	return nil
 }
 
+// markMerged 为合并区域内的单元格打标记（缺失的单元格新建），返回主单元格（左上角）
+// visible 非空时仅新建区域内可见的单元格，主单元格总是保留以便绘制跨越渲染区域边界的合并单元格
+func (s *Sheet) markMerged(mr cellRange, visible *cellRange) *Cell {
+	// 构造整个合并区域的地址列表
+	var mergedRange []string
+	for r := mr.StartRow; r <= mr.EndRow; r++ {
+		for c := mr.StartCol; c <= mr.EndCol; c++ {
+			addr, _ := excelize.CoordinatesToCellName(c, r)
+			mergedRange = append(mergedRange, addr)
+		}
+	}
+
+	var mainCell *Cell
+	for r := mr.StartRow; r <= mr.EndRow; r++ {
+		for c := mr.StartCol; c <= mr.EndCol; c++ {
+			isMain := r == mr.StartRow && c == mr.StartCol
+			cell := s.cells.at(r, c)
+			if cell == nil {
+				if visible != nil && !isMain && !visible.contains(r, c) {
+					continue
+				}
+				// 如果之前没加载到值，也新建一个 Cell
+				addr, _ := excelize.CoordinatesToCellName(c, r)
+				cell = &Cell{Sheet: s, Row: r, Col: c, Address: addr}
+				s.cells.put(cell)
+			}
+			cell.IsMerged = true
+			cell.MergedRange = mergedRange
+			if isMain {
+				mainCell = cell
+			}
+		}
+	}
+	return mainCell
+}
+
+// acquireFonts 从 Excel 的字体缓存取出加载期间独占的字体集合，返回放回的函数
+func (s *Sheet) acquireFonts() func() {
+	s.fonts = s.excel.fonts.get()
+	return func() {
+		s.excel.fonts.put(s.fonts)
+		s.fonts = nil
+	}
+}
+
+// bindStyle 为单元格绑定样式索引并缓存样式，返回是否为缓存未命中
+func (s *Sheet) bindStyle(cell *Cell, styleIndex int) (bool, error) {
+	cell.StyleIndex = styleIndex
+	if _, ok := s.styles[styleIndex]; ok {
+		return false, nil
+	}
+	st, err := s.excel.file.GetStyle(styleIndex)
+	if err != nil {
+		return true, err
+	}
+	s.styles[styleIndex] = st
+	return true, nil
+}
+
 // GetColWidth 获取指定列的列宽
 func (s *Sheet) GetColWidth(col string) float64 {
 	return s.colWidthMap[col]
@@ -287,172 +423,129 @@ func (s *Sheet) GetRowHeight(row int) float64 {
 	return s.rowHeightMap[row]
 }
 
+// ColWidthPixels 获取指定列的像素宽度（96 DPI，按默认字体的最大数字宽度换算）
+func (s *Sheet) ColWidthPixels(col string) float64 {
+	return colWidthToPixels(s.GetColWidth(col), s.excel.maxDigitWidth)
+}
+
+// RowHeightPixels 获取指定行的像素高度（96 DPI，1 磅 = 4/3 像素）
+func (s *Sheet) RowHeightPixels(row int) float64 {
+	return s.GetRowHeight(row) * pixelsPerPoint
+}
+
 // optimizeColumnWidths 智能调整列宽以确保数据完整可见
 func (s *Sheet) optimizeColumnWidths() {
 	// Excel 默认列宽（与 excelize 返回值对齐），使用容差判断
 	const defaultColWidth = 9.140625
 	const eps = 1e-6
 
+	contentWidths := s.columnContentWidths()
 	for colLetter := range s.colWidthMap {
 		currentWidth := s.colWidthMap[colLetter]
 
 		// 仅当列宽为默认值时，按内容进行估算调整；否则尊重文件中的列宽设置
 		if math.Abs(currentWidth-defaultColWidth) <= eps {
-			maxContentWidth := s.calculateMaxContentWidth(colLetter)
-			if maxContentWidth > currentWidth {
-				// 增加20%的缓冲区
-				s.colWidthMap[colLetter] = maxContentWidth * 1.4
+			col, _ := excelize.ColumnNameToNumber(colLetter)
+			if maxContentWidth := contentWidths[col]; maxContentWidth > currentWidth {
+				s.colWidthMap[colLetter] = maxContentWidth
 			}
 		}
 	}
 }
 
-// calculateMaxContentWidth 计算列中最大内容宽度
-func (s *Sheet) calculateMaxContentWidth(colLetter string) float64 {
-	maxWidth := 0.0
-
-	// 遍历该列的所有单元格
-	for _, cell := range s.cells {
-		if cell.Address == "" {
-			continue
-		}
-
-		// 检查是否属于当前列
-		cellColLetter, _ := excelize.ColumnNumberToName(cell.Col)
-		if cellColLetter != colLetter {
-			continue
+// columnContentWidths 一次遍历计算各列的最大内容宽度（Excel 列宽单位）
+func (s *Sheet) columnContentWidths() map[int]float64 {
+	widths := make(map[int]float64)
+	s.cells.each(func(cell *Cell) error {
+		if cell.Value == "" {
+			return nil
 		}
-
-		// 估算文本宽度（简单估算：字符数 * 平均字符宽度）
-		contentWidth := s.estimateTextWidth(cell.Value)
-		if contentWidth > maxWidth {
-			maxWidth = contentWidth
+		// 使用绘制时的字体度量文本宽度（多行文本取最宽的一行）
+		tf, _ := s.cellTextFont(cell)
+		for _, line := range strings.Split(cell.Value, "\n") {
+			widths[cell.Col] = math.Max(widths[cell.Col], s.measureTextWidth(line, tf))
 		}
-	}
-
-	return maxWidth
+		return nil
+	})
+	return widths
 }
 
-// estimateTextWidth 估算文本宽度
-func (s *Sheet) estimateTextWidth(text string) float64 {
+// measureTextWidth 度量单行文本宽度并换算为 Excel 列宽单位
+func (s *Sheet) measureTextWidth(text string, tf textFont) float64 {
 	if text == "" {
 		return 0
 	}
+	px := s.fonts.textWidth(strings.TrimRight(text, "\r"), tf)
+	return pixelsToColWidth(px, s.excel.maxDigitWidth)
+}
 
-	// 简单估算：中文字符约2个单位，英文字符约1个单位
-	width := 0.0
-	for _, char := range text {
-		if char > 127 { // 非ASCII字符（中文等）
-			width += 2.0
-		} else {
-			width += 1.0
+// cellTextFont 返回单元格绘制时使用的字体及是否自动换行（未设置时使用工作簿默认字体）
+func (s *Sheet) cellTextFont(cell *Cell) (textFont, bool) {
+	tf := s.excel.defaultFont
+	wrapText := true // 默认为可换行（多数场景渲染更友好）
+	if cell == nil {
+		return tf, wrapText
+	}
+	style, err := cell.RenderStyle()
+	if err != nil || style == nil {
+		return tf, wrapText
+	}
+	if style.Font != nil {
+		if style.Font.Family != "" {
+			tf.family = style.Font.Family
+		}
+		if style.Font.Size > 0 {
+			tf.size = style.Font.Size
 		}
+		tf.bold = style.Font.Bold
+		tf.italic = style.Font.Italic
 	}
-
-	// 转换为Excel列宽单位（大约字符宽度的0.8倍）
-	return width * 0.8
+	// 若样式提供换行信息，则以样式为准（注意 Alignment 可能为 nil）
+	if style.Alignment != nil {
+		wrapText = style.Alignment.WrapText
+	}
+	return tf, wrapText
 }
 
-// estimateRowHeight 根据行内容和字体大小估算行高
-func (s *Sheet) estimateRowHeight(rowNum int, rowData []string) float64 {
+// estimateRowHeight 根据行内容和字体度量估算行高（磅）
+func (s *Sheet) estimateRowHeight(rowNum int) float64 {
 	maxHeight := 15.0 // 默认最小行高
 
 	// 遍历这一行的所有单元格
-	for colIndex, cellValue := range rowData {
+	for _, cell := range s.cells.row(rowNum) {
+		cellValue := cell.Value
 		if cellValue == "" {
 			continue
 		}
 
-		// 获取单元格对象以获取样式信息
-		cellAddr, _ := excelize.CoordinatesToCellName(colIndex+1, rowNum)
-		cell := s.cells[cellAddr]
+		// 获取单元格字体信息
+		tf, wrapText := s.cellTextFont(cell)
 
-		// 获取字体大小
-		fontSize := 11.0 // 默认字体大小
-		wrapText := true // 默认为可换行（多数场景渲染更友好）
-		if cell != nil {
-			if style, err := cell.Style(); err == nil && style != nil {
-				if style.Font != nil && style.Font.Size > 0 {
-					fontSize = style.Font.Size
-				}
-				// 若样式提供换行信息，则以样式为准（注意 Alignment 可能为 nil）
-				if style.Alignment != nil {
-					wrapText = style.Alignment.WrapText
-				}
-			}
-		}
-
-		// 计算基于字体大小的基础行高
-		// 经验公式：行高 ≈ 字体大小 * 1.2 到 1.4 之间
-		fontBasedHeight := fontSize * 1.33
-
-		// 显式换行符统计
-		explicitLines := 1
-		for _, char := range cellValue {
-			if char == '\n' || char == '\r' {
-				explicitLines++
-			}
-		}
+		// 基于字体度量的单行高度
+		lineHeight := s.fonts.lineHeight(tf)
 
 		// 列宽（Excel 列宽单位）。若未能获取，使用默认列宽
-		colName, _ := excelize.ColumnNumberToName(colIndex + 1)
+		colName, _ := excelize.ColumnNumberToName(cell.Col)
 		colWidth := s.GetColWidth(colName)
 		if colWidth <= 0 {
 			colWidth = 9.140625 // Excel 默认列宽
 		}
 
-		// 基于内容宽度估算需要的行数（仅当允许换行或存在显式换行时生效）
+		// 按列宽（扣除内边距）计算换行后的行数；不允许换行时仅统计显式换行
 		lines := 1
-		if wrapText || explicitLines > 1 {
-			if explicitLines > 1 {
-				// 按显式换行切分，每段分别估算并累加
-				segs := strings.FieldsFunc(cellValue, func(r rune) bool { return r == '\n' || r == '\r' })
-				total := 0
-				for _, seg := range segs {
-					cw := s.estimateTextWidth(seg)
-					if colWidth > 0 {
-						need := int(cw/colWidth + 0.9999)
-						if need < 1 {
-							need = 1
-						}
-						total += need
-					} else {
-						total += 1
-					}
-				}
-				if total < 1 {
-					total = 1
-				}
-				lines = total
-			} else {
-				// 无显式换行，整段估算
-				contentWidthUnits := s.estimateTextWidth(cellValue)
-				if colWidth > 0 {
-					needed := int(contentWidthUnits/colWidth + 0.9999) // ceil
-					if needed < 1 {
-						needed = 1
-					}
-					lines = needed
-				} else {
-					lines = 1
-				}
-			}
+		if wrapText {
+			available := colWidthToPixels(colWidth, s.excel.maxDigitWidth) - colWidthPadding
+			lines = s.fonts.wrapLineCount(cellValue, tf, available)
 		} else {
-			// 不允许自动换行且没有显式换行，认为单行
-			lines = 1
+			lines = strings.Count(strings.ReplaceAll(cellValue, "\r\n", "\n"), "\n") + 1
 		}
 
-		estimatedHeight := fontBasedHeight * float64(lines)
+		estimatedHeight := lineHeight * float64(lines)
 		if estimatedHeight > maxHeight {
 			maxHeight = estimatedHeight
 		}
 	}
 
-	// 确保最小行高
-	if maxHeight < 15.0 {
-		maxHeight = 15.0
-	}
-
 	// 限制最大行高，避免过度拉伸
 	if maxHeight > 150.0 {
 		maxHeight = 150.0
@@ -461,15 +554,28 @@ func (s *Sheet) estimateRowHeight(rowNum int, rowData []string) float64 {
 	return maxHeight
 }
 
+// cellAddresses 按行列顺序返回所有单元格地址
+func (s *Sheet) cellAddresses() []string {
+	addrs := make([]string, 0, s.cells.len())
+	s.cells.each(func(cell *Cell) error {
+		addrs = append(addrs, cell.Address)
+		return nil
+	})
+	return addrs
+}
+
 // loadImages 加载工作表中的嵌入图片
 func (s *Sheet) loadImages() error {
 	s.images = nil // 重置图片列表
-	s.excel.logger.Info("开始加载图片", zap.String("sheet", s.Name))
+	s.excel.logger.Debug("开始加载图片", zap.String("sheet", s.Name))
 
 	// 遍历所有单元格，查找包含图片的单元格
 	cellCount := 0
-	for addr := range s.cells {
+	for _, addr := range s.cellAddresses() {
 		cellCount++
+		if err := s.guard.step(0); err != nil {
+			return err
+		}
 		pictures, err := s.excel.file.GetPictures(s.Name, addr)
 		if err != nil {
 			s.excel.logger.Debug("获取单元格图片失败", zap.String("cell", addr), zap.Error(err))
@@ -498,6 +604,9 @@ func (s *Sheet) loadImages() error {
 
 			// 解码图片数据
 			if err := s.decodeImage(excelImage); err != nil {
+				if isAbort(err) {
+					return err
+				}
 				s.excel.logger.Warn("解码图片失败", zap.String("cell", addr), zap.Error(err))
 				continue
 			}
@@ -506,7 +615,7 @@ func (s *Sheet) loadImages() error {
 		}
 	}
 
-	s.excel.logger.Info("图片加载完成",
+	s.excel.logger.Debug("图片加载完成",
 		zap.String("sheet", s.Name),
 		zap.Int("检查单元格数", cellCount),
 		zap.Int("加载图片数量", len(s.images)))
@@ -514,10 +623,27 @@ func (s *Sheet) loadImages() error {
 }
 
 // decodeImage 解码图片数据
+// 解码前检查 ctx 与图片大小限制：压缩数据与按图片头部尺寸估算的解码后数据均不得超出 MaxImageBytes
 func (s *Sheet) decodeImage(excelImage *ExcelImage) error {
 	if len(excelImage.Data) == 0 {
 		return fmt.Errorf("图片数据为空")
 	}
+	if err := s.guard.err(); err != nil {
+		return err
+	}
+	limits := s.excel.limits
+	if err := limits.checkImageBytes(s.Name, int64(len(excelImage.Data))); err != nil {
+		return err
+	}
+	if limits.MaxImageBytes > 0 {
+		cfg, _, err := image.DecodeConfig(bytes.NewReader(excelImage.Data))
+		if err != nil {
+			return fmt.Errorf("解码图片失败: %w", err)
+		}
+		if err := limits.checkImageBytes(s.Name, int64(cfg.Width)*int64(cfg.Height)*4); err != nil {
+			return err
+		}
+	}
 
 	reader := bytes.NewReader(excelImage.Data)
 
@@ -0,0 +1,186 @@
+package excelsnapshot
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap"
+)
+
+// SheetVisibility 工作表的可见性
+type SheetVisibility string
+
+// 工作表可见性（对应 workbook.xml 中 sheet 的 state 属性）
+const (
+	SheetVisible SheetVisibility = "visible"
+	SheetHidden  SheetVisibility = "hidden"
+	// 只能通过 VBA 取消隐藏的工作表
+	SheetVeryHidden SheetVisibility = "veryHidden"
+)
+
+// SheetInfo 工作表的元数据，无需加载工作表内容
+type SheetInfo struct {
+	// 在工作簿中的序号（0-based，与 GetSheetNameByIndex 一致）
+	Index      int
+	Name       string
+	Visibility SheetVisibility
+	// 工作表标签颜色 RRGGBB，未设置时为空
+	TabColor string
+	// 工作表记录的已用区域（如 "A1:D20"），由生成文件的程序写入，未记录时为空
+	Dimension string
+}
+
+// Hidden 是否为隐藏或深度隐藏的工作表
+func (info SheetInfo) Hidden() bool {
+	return info.Visibility != SheetVisible
+}
+
+// ListSheets 按工作簿顺序返回所有工作表（含隐藏的工作表）的元数据
+// 无法读取标签颜色或已用区域的工作表仍会列出，相应字段为空
+func (e *Excel) ListSheets() ([]SheetInfo, error) {
+	if e.file == nil {
+		return nil, fmt.Errorf("Excel 文件未打开")
+	}
+	// GetSheetList 读取 workbook.xml 后 WorkBook 可用，其中的工作表顺序即 GetSheetList 的顺序
+	names := e.file.GetSheetList()
+	infos := make([]SheetInfo, 0, len(names))
+	for i, name := range names {
+		info := SheetInfo{Index: i, Name: name, Visibility: SheetVisible}
+		if wb := e.file.WorkBook; wb != nil && i < len(wb.Sheets.Sheet) {
+			switch wb.Sheets.Sheet[i].State {
+			case "hidden":
+				info.Visibility = SheetHidden
+			case "veryHidden":
+				info.Visibility = SheetVeryHidden
+			}
+		}
+		var err error
+		if info.TabColor, info.Dimension, err = e.scanSheetHeader(name); err != nil {
+			// 无法读取的工作表仍然列出，标签颜色与已用区域为空
+			e.logger.Warn("读取工作表属性失败", zap.String("sheet", name), zap.Error(err))
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// xlsxTabColor 工作表标签颜色（sheetPr/tabColor）
+type xlsxTabColor struct {
+	RGB     string  `xml:"rgb,attr"`
+	Theme   *int    `xml:"theme,attr"`
+	Indexed *int    `xml:"indexed,attr"`
+	Tint    float64 `xml:"tint,attr"`
+}
+
+// hex 将 rgb、主题色或索引色（含 tint）转换为 RRGGBB，无法识别时返回空串
+func (c *xlsxTabColor) hex() string {
+	var hex string
+	switch {
+	case len(c.RGB) == 8:
+		hex = strings.ToUpper(c.RGB[2:])
+	case len(c.RGB) == 6:
+		hex = strings.ToUpper(c.RGB)
+	case c.Theme != nil:
+		hex = themeColorHex(*c.Theme)
+	case c.Indexed != nil && *c.Indexed >= 0 && *c.Indexed < len(excelize.IndexedColorMapping):
+		hex = excelize.IndexedColorMapping[*c.Indexed]
+	}
+	if hex != "" && c.Tint != 0 {
+		hex = tintHex(hex, c.Tint)
+	}
+	return hex
+}
+
+// scanSheetHeader 读取工作表的标签颜色与已用区域
+// 两者都位于 sheetData 之前，读到 sheetData 即停止，不解析单元格数据
+func (e *Excel) scanSheetHeader(name string) (tabColor, dimension string, err error) {
+	r, err := e.openWorksheet(name)
+	if err != nil {
+		return "", "", err
+	}
+	defer r.Close()
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return tabColor, dimension, nil
+		}
+		if err != nil {
+			return "", "", err
+		}
+		el, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch el.Name.Local {
+		case "tabColor":
+			var c xlsxTabColor
+			if err := dec.DecodeElement(&c, &el); err != nil {
+				return "", "", err
+			}
+			tabColor = c.hex()
+		case "dimension":
+			dimension = xmlAttr(el, "ref")
+		case "sheetData":
+			return tabColor, dimension, nil
+		}
+	}
+}
+
+// SheetSelector 按可见性与名称选择工作表
+type SheetSelector struct {
+	// 是否包含隐藏与深度隐藏的工作表
+	IncludeHidden bool
+	// 名称通配符（path.Match 语法，如 "2024-*"），与 Regexp 任一匹配即选中；均为空时不按名称筛选
+	Globs  []string
+	Regexp *regexp.Regexp
+}
+
+// Match 判断工作表是否被选中
+func (s SheetSelector) Match(info SheetInfo) bool {
+	if info.Hidden() && !s.IncludeHidden {
+		return false
+	}
+	if len(s.Globs) == 0 && s.Regexp == nil {
+		return true
+	}
+	for _, glob := range s.Globs {
+		if ok, _ := path.Match(glob, info.Name); ok {
+			return true
+		}
+	}
+	return s.Regexp != nil && s.Regexp.MatchString(info.Name)
+}
+
+// Validate 检查通配符语法
+func (s SheetSelector) Validate() error {
+	for _, glob := range s.Globs {
+		if _, err := path.Match(glob, ""); err != nil {
+			return fmt.Errorf("无效的工作表通配符 %q: %w", glob, err)
+		}
+	}
+	return nil
+}
+
+// SelectSheets 按工作簿顺序返回被选中的工作表名称
+func (e *Excel) SelectSheets(s SheetSelector) ([]string, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	infos, err := e.ListSheets()
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, info := range infos {
+		if s.Match(info) {
+			names = append(names, info.Name)
+		}
+	}
+	return names, nil
+}
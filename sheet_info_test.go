@@ -0,0 +1,225 @@
+package excelsnapshot
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap/zaptest"
+)
+
+// createTestExcelWithSheetStates 创建包含可见、隐藏、深度隐藏工作表与标签颜色的测试工作簿
+func createTestExcelWithSheetStates(t *testing.T) *Excel {
+	t.Helper()
+	f := excelize.NewFile()
+	f.SetSheetName("Sheet1", "2024-01")
+	for _, name := range []string{"2024-02", "草稿", "Config", "2023-12"} {
+		f.NewSheet(name)
+	}
+	f.SetCellValue("2024-01", "A1", "一月")
+	f.SetCellValue("2024-01", "D20", 100)
+	f.SetCellValue("2024-02", "B3", "二月")
+	// excelize 写入单元格时不更新工作表记录的区域
+	f.SetSheetDimension("2024-01", "A1:D20")
+	f.SetSheetDimension("2024-02", "B3")
+	f.SetSheetProps("2024-01", &excelize.SheetPropsOptions{TabColorRGB: stringPtr("FFFF0000")})
+	f.SetSheetProps("2024-02", &excelize.SheetPropsOptions{TabColorTheme: intPtr(4)})
+	f.SetSheetVisible("草稿", false)
+	f.SetSheetVisible("Config", false, true)
+	buf, err := f.WriteToBuffer()
+	f.Close()
+	if err != nil {
+		t.Fatalf("写入工作簿失败: %v", err)
+	}
+	excel, err := NewExcelFromBytes(buf.Bytes(), zaptest.NewLogger(t))
+	if err != nil {
+		t.Fatalf("加载Excel失败: %v", err)
+	}
+	t.Cleanup(func() { excel.Close() })
+	return excel
+}
+
+func intPtr(v int) *int          { return &v }
+func stringPtr(v string) *string { return &v }
+
+// TestExcel_ListSheets 测试按工作簿顺序列出工作表及其元数据
+func TestExcel_ListSheets(t *testing.T) {
+	excel := createTestExcelWithSheetStates(t)
+	infos, err := excel.ListSheets()
+	if err != nil {
+		t.Fatalf("ListSheets() 失败: %v", err)
+	}
+
+	want := []SheetInfo{
+		{Index: 0, Name: "2024-01", Visibility: SheetVisible, TabColor: "FF0000", Dimension: "A1:D20"},
+		{Index: 1, Name: "2024-02", Visibility: SheetVisible, TabColor: themeColorHex(4), Dimension: "B3"},
+		{Index: 2, Name: "草稿", Visibility: SheetHidden, Dimension: "A1"},
+		{Index: 3, Name: "Config", Visibility: SheetVeryHidden, Dimension: "A1"},
+		{Index: 4, Name: "2023-12", Visibility: SheetVisible, Dimension: "A1"},
+	}
+	if len(infos) != len(want) {
+		t.Fatalf("工作表数量 = %d, want %d", len(infos), len(want))
+	}
+	for i := range want {
+		if infos[i] != want[i] {
+			t.Errorf("ListSheets()[%d] = %+v, want %+v", i, infos[i], want[i])
+		}
+		if name := excel.GetSheetNameByIndex(i); name != infos[i].Name {
+			t.Errorf("GetSheetNameByIndex(%d) = %q, want %q", i, name, infos[i].Name)
+		}
+	}
+
+	// 只读取工作表开头的元素，不应让 excelize 解析并缓存完整的工作表
+	excel.file.Sheet.Range(func(k, _ any) bool {
+		t.Errorf("ListSheets() 后 excelize 缓存了工作表 %v", k)
+		return true
+	})
+}
+
+// TestExcel_ListSheets_Unreadable 测试无法读取的工作表不影响其他工作表的列出
+func TestExcel_ListSheets_Unreadable(t *testing.T) {
+	f := excelize.NewFile()
+	f.NewSheet("损坏")
+	f.NewSheet("正常")
+	f.SetSheetProps("正常", &excelize.SheetPropsOptions{TabColorRGB: stringPtr("FF00FF00")})
+	buf, err := f.WriteToBuffer()
+	f.Close()
+	if err != nil {
+		t.Fatalf("写入工作簿失败: %v", err)
+	}
+
+	// 将第二个工作表的内容替换为无法解析的 XML
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("读取工作簿失败: %v", err)
+	}
+	var out bytes.Buffer
+	zw := zip.NewWriter(&out)
+	for _, zf := range zr.File {
+		w, _ := zw.Create(zf.Name)
+		if zf.Name == "xl/worksheets/sheet2.xml" {
+			io.WriteString(w, "<worksheet><sheetPr><tabColor")
+			continue
+		}
+		rc, _ := zf.Open()
+		io.Copy(w, rc)
+		rc.Close()
+	}
+	zw.Close()
+
+	excel, err := NewExcelFromBytes(out.Bytes(), zaptest.NewLogger(t))
+	if err != nil {
+		t.Fatalf("加载Excel失败: %v", err)
+	}
+	defer excel.Close()
+	infos, err := excel.ListSheets()
+	if err != nil {
+		t.Fatalf("ListSheets() 失败: %v", err)
+	}
+	if len(infos) != 3 {
+		t.Fatalf("工作表数量 = %d, want 3", len(infos))
+	}
+	if infos[1].Name != "损坏" || infos[1].TabColor != "" {
+		t.Errorf("ListSheets()[1] = %+v", infos[1])
+	}
+	if infos[2].Name != "正常" || infos[2].TabColor != "00FF00" {
+		t.Errorf("ListSheets()[2] = %+v, want TabColor 00FF00", infos[2])
+	}
+}
+
+// TestExcel_SelectSheets_CSV 测试 CSV 工作簿的工作表选择与批量渲染（-all）
+func TestExcel_SelectSheets_CSV(t *testing.T) {
+	excel, err := NewExcelFromCSV(strings.NewReader("名称,数量\n苹果,10\n"), zaptest.NewLogger(t), CSVOptions{SheetName: "d"})
+	if err != nil {
+		t.Fatalf("NewExcelFromCSV() 失败: %v", err)
+	}
+	defer excel.Close()
+
+	infos, err := excel.ListSheets()
+	if err != nil {
+		t.Fatalf("ListSheets() 失败: %v", err)
+	}
+	want := SheetInfo{Name: "d", Visibility: SheetVisible, Dimension: "A1:B2"}
+	if len(infos) != 1 || infos[0] != want {
+		t.Fatalf("ListSheets() = %+v, want [%+v]", infos, want)
+	}
+	names, err := excel.SelectSheets(SheetSelector{})
+	if err != nil {
+		t.Fatalf("SelectSheets() 失败: %v", err)
+	}
+	if fmt.Sprint(names) != "[d]" {
+		t.Fatalf("SelectSheets() = %v, want [d]", names)
+	}
+
+	renderer := NewSheetRenderer(zaptest.NewLogger(t))
+	rendered := 0
+	err = renderer.RenderSheets(excel, BatchOptions{Sheets: names}, func(res *SheetResult) error {
+		if res.Err != nil {
+			return res.Err
+		}
+		rendered++
+		return nil
+	})
+	if err != nil || rendered != 1 {
+		t.Errorf("RenderSheets() error = %v, 渲染了 %d 个工作表, want 1", err, rendered)
+	}
+}
+
+// TestExcel_SelectSheets 测试按可见性与名称选择工作表
+func TestExcel_SelectSheets(t *testing.T) {
+	excel := createTestExcelWithSheetStates(t)
+	tests := []struct {
+		name     string
+		selector SheetSelector
+		want     []string
+		wantErr  bool
+	}{
+		{"默认跳过隐藏的工作表", SheetSelector{}, []string{"2024-01", "2024-02", "2023-12"}, false},
+		{"包含隐藏的工作表", SheetSelector{IncludeHidden: true}, []string{"2024-01", "2024-02", "草稿", "Config", "2023-12"}, false},
+		{"通配符", SheetSelector{Globs: []string{"2024-*"}}, []string{"2024-01", "2024-02"}, false},
+		{"多个通配符", SheetSelector{Globs: []string{"*-02", "2023-*"}}, []string{"2024-02", "2023-12"}, false},
+		{"正则表达式", SheetSelector{Regexp: regexp.MustCompile(`-(01|12)$`)}, []string{"2024-01", "2023-12"}, false},
+		{"通配符不选中隐藏的工作表", SheetSelector{Globs: []string{"C*"}}, nil, false},
+		{"通配符与隐藏的工作表", SheetSelector{IncludeHidden: true, Globs: []string{"C*"}}, []string{"Config"}, false},
+		{"无效的通配符", SheetSelector{Globs: []string{"[2024"}}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := excel.SelectSheets(tt.selector)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SelectSheets() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("SelectSheets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestXLSXTabColor_hex 测试标签颜色的转换
+func TestXLSXTabColor_hex(t *testing.T) {
+	tests := []struct {
+		name  string
+		color xlsxTabColor
+		want  string
+	}{
+		{"ARGB", xlsxTabColor{RGB: "ff00b050"}, "00B050"},
+		{"主题色", xlsxTabColor{Theme: intPtr(5)}, themeColorHex(5)},
+		{"主题色加深", xlsxTabColor{Theme: intPtr(1), Tint: -0.5}, tintHex(themeColorHex(1), -0.5)},
+		{"索引色", xlsxTabColor{Indexed: intPtr(10)}, "FF0000"},
+		{"无效的索引色", xlsxTabColor{Indexed: intPtr(1000)}, ""},
+		{"未设置", xlsxTabColor{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.color.hex(); !strings.EqualFold(got, tt.want) {
+				t.Errorf("hex() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -146,7 +146,7 @@ func TestSheet_GetCell(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			cell := sheet.cells[tt.addr]
+			cell := sheet.cells.get(tt.addr)
 			exists := (cell != nil)
 			if exists != tt.want {
 				t.Errorf("GetCell(%v) 存在性 = %v, want %v", tt.addr, exists, tt.want)
@@ -284,6 +284,6 @@ func BenchmarkSheet_GetCell(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_ = sheet.cells["A1"]
+		_ = sheet.cells.get("A1")
 	}
 }
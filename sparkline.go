@@ -0,0 +1,352 @@
+package excelsnapshot
+
+import (
+	"bytes"
+	"encoding/xml"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap"
+)
+
+// SparklineGroup 迷你图组的公共设置（类型、颜色、标记等）
+type SparklineGroup struct {
+	Type       string  // line、column、stacked（盈亏图）
+	LineWeight float64 // 线宽（磅）
+	EmptyAs    string  // 空单元格显示方式：gap、zero、span
+
+	Markers  bool
+	High     bool
+	Low      bool
+	First    bool
+	Last     bool
+	Negative bool
+	ShowAxis bool
+	Reverse  bool
+
+	ColorSeries   string
+	ColorNegative string
+	ColorAxis     string
+	ColorMarkers  string
+	ColorFirst    string
+	ColorLast     string
+	ColorHigh     string
+	ColorLow      string
+
+	// 纵轴范围：custom 使用手动值，group 使用组内所有迷你图的最值，否则各自独立
+	MinAxisType string
+	MaxAxisType string
+	ManualMin   float64
+	ManualMax   float64
+	groupMin    float64
+	groupMax    float64
+}
+
+// Sparkline 单个迷你图：所在单元格及其数据
+type Sparkline struct {
+	Group  *SparklineGroup
+	Cell   string
+	Row    int
+	Col    int
+	Range  string
+	Values []float64 // 非数值或空单元格为 NaN
+}
+
+// xlsxSparklineWorksheet 仅解析工作表扩展列表中的迷你图组
+type xlsxSparklineWorksheet struct {
+	ExtLst *struct {
+		Ext []struct {
+			SparklineGroups *struct {
+				Group []xlsxSparklineGroup `xml:"sparklineGroup"`
+			} `xml:"sparklineGroups"`
+		} `xml:"ext"`
+	} `xml:"extLst"`
+}
+
+type xlsxSparklineGroup struct {
+	Type                string              `xml:"type,attr"`
+	LineWeight          string              `xml:"lineWeight,attr"`
+	DisplayEmptyCellsAs string              `xml:"displayEmptyCellsAs,attr"`
+	Markers             bool                `xml:"markers,attr"`
+	High                bool                `xml:"high,attr"`
+	Low                 bool                `xml:"low,attr"`
+	First               bool                `xml:"first,attr"`
+	Last                bool                `xml:"last,attr"`
+	Negative            bool                `xml:"negative,attr"`
+	DisplayXAxis        bool                `xml:"displayXAxis,attr"`
+	RightToLeft         bool                `xml:"rightToLeft,attr"`
+	MinAxisType         string              `xml:"minAxisType,attr"`
+	MaxAxisType         string              `xml:"maxAxisType,attr"`
+	ManualMin           float64             `xml:"manualMin,attr"`
+	ManualMax           float64             `xml:"manualMax,attr"`
+	ColorSeries         *xlsxSparklineColor `xml:"colorSeries"`
+	ColorNegative       *xlsxSparklineColor `xml:"colorNegative"`
+	ColorAxis           *xlsxSparklineColor `xml:"colorAxis"`
+	ColorMarkers        *xlsxSparklineColor `xml:"colorMarkers"`
+	ColorFirst          *xlsxSparklineColor `xml:"colorFirst"`
+	ColorLast           *xlsxSparklineColor `xml:"colorLast"`
+	ColorHigh           *xlsxSparklineColor `xml:"colorHigh"`
+	ColorLow            *xlsxSparklineColor `xml:"colorLow"`
+	Sparklines          struct {
+		Sparkline []struct {
+			F     string `xml:"f"`
+			Sqref string `xml:"sqref"`
+		} `xml:"sparkline"`
+	} `xml:"sparklines"`
+}
+
+type xlsxSparklineColor struct {
+	RGB   string  `xml:"rgb,attr"`
+	Theme *int    `xml:"theme,attr"`
+	Tint  float64 `xml:"tint,attr"`
+}
+
+// hex 将 rgb 或主题色（含 tint）转换为 RRGGBB，无法识别时返回 def
+func (c *xlsxSparklineColor) hex(def string) string {
+	if c == nil {
+		return def
+	}
+	var hex string
+	switch {
+	case len(c.RGB) == 8:
+		hex = strings.ToUpper(c.RGB[2:])
+	case len(c.RGB) == 6:
+		hex = strings.ToUpper(c.RGB)
+	case c.Theme != nil:
+		hex = themeColorHex(*c.Theme)
+	}
+	if hex == "" {
+		return def
+	}
+	if c.Tint != 0 {
+		hex = tintHex(hex, c.Tint)
+	}
+	return hex
+}
+
+// Sparklines 返回工作表中的迷你图
+func (s *Sheet) Sparklines() []*Sparkline {
+	return s.sparklines
+}
+
+// loadSparklines 从工作表扩展列表中解析迷你图组，并根据已加载的单元格值解析数据
+func (s *Sheet) loadSparklines() error {
+	s.sparklines = nil
+	part := worksheetPart(s.excel.file, s.Name)
+	if part == "" {
+		return nil
+	}
+	// 超过解压大小限制的工作表不在内存中，需从原始文件中读取
+	data, err := s.excel.readPart(part)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 || !bytes.Contains(data, []byte("sparklineGroup")) {
+		return nil
+	}
+	var ws xlsxSparklineWorksheet
+	if err := xml.NewDecoder(bytes.NewReader(data)).Decode(&ws); err != nil {
+		return err
+	}
+	if ws.ExtLst == nil {
+		return nil
+	}
+	for _, ext := range ws.ExtLst.Ext {
+		if ext.SparklineGroups == nil {
+			continue
+		}
+		for _, g := range ext.SparklineGroups.Group {
+			s.addSparklineGroup(g)
+		}
+	}
+	s.excel.logger.Debug("迷你图加载完成", zap.String("sheet", s.Name), zap.Int("sparklines", len(s.sparklines)))
+	return nil
+}
+
+// addSparklineGroup 转换一个迷你图组并解析组内每个迷你图的数据
+func (s *Sheet) addSparklineGroup(g xlsxSparklineGroup) {
+	group := &SparklineGroup{
+		Type:          g.Type,
+		LineWeight:    0.75,
+		EmptyAs:       g.DisplayEmptyCellsAs,
+		Markers:       g.Markers,
+		High:          g.High,
+		Low:           g.Low,
+		First:         g.First,
+		Last:          g.Last,
+		Negative:      g.Negative,
+		ShowAxis:      g.DisplayXAxis,
+		Reverse:       g.RightToLeft,
+		ColorSeries:   g.ColorSeries.hex("376092"),
+		ColorNegative: g.ColorNegative.hex("D00000"),
+		ColorAxis:     g.ColorAxis.hex("000000"),
+		ColorMarkers:  g.ColorMarkers.hex("D00000"),
+		ColorFirst:    g.ColorFirst.hex("D00000"),
+		ColorLast:     g.ColorLast.hex("D00000"),
+		ColorHigh:     g.ColorHigh.hex("D00000"),
+		ColorLow:      g.ColorLow.hex("D00000"),
+		MinAxisType:   g.MinAxisType,
+		MaxAxisType:   g.MaxAxisType,
+		ManualMin:     g.ManualMin,
+		ManualMax:     g.ManualMax,
+		groupMin:      math.Inf(1),
+		groupMax:      math.Inf(-1),
+	}
+	if group.Type == "" {
+		group.Type = "line"
+	}
+	if group.EmptyAs == "" {
+		group.EmptyAs = "zero"
+	}
+	if w, err := strconv.ParseFloat(g.LineWeight, 64); err == nil && w > 0 {
+		group.LineWeight = w
+	}
+
+	for _, sp := range g.Sparklines.Sparkline {
+		col, row, err := excelize.CellNameToCoordinates(strings.TrimSpace(sp.Sqref))
+		if err != nil {
+			continue
+		}
+		line := &Sparkline{
+			Group:  group,
+			Cell:   strings.TrimSpace(sp.Sqref),
+			Row:    row,
+			Col:    col,
+			Range:  sp.F,
+			Values: s.resolveRangeValues(sp.F),
+		}
+		for _, v := range line.Values {
+			if !math.IsNaN(v) {
+				group.groupMin = math.Min(group.groupMin, v)
+				group.groupMax = math.Max(group.groupMax, v)
+			}
+		}
+		s.sparklines = append(s.sparklines, line)
+	}
+}
+
+// resolveRangeValues 读取区域内的数值（按行优先展开）；当前工作表直接使用已加载的单元格
+func (s *Sheet) resolveRangeValues(ref string) []float64 {
+	sheetName := s.Name
+	if i := strings.LastIndex(ref, "!"); i >= 0 {
+		sheetName = strings.ReplaceAll(strings.Trim(ref[:i], "'"), "''", "'")
+	}
+	sc, sr, ec, er, err := parseRangeRef(ref)
+	if err != nil {
+		return nil
+	}
+	values := make([]float64, 0, (ec-sc+1)*(er-sr+1))
+	for r := sr; r <= er; r++ {
+		for c := sc; c <= ec; c++ {
+			addr, _ := excelize.CoordinatesToCellName(c, r)
+			var raw string
+			if sheetName == s.Name {
+				raw = s.cells.at(r, c).String()
+			} else {
+				raw, _ = s.excel.file.GetCellValue(sheetName, addr)
+			}
+			v, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+			if err != nil {
+				v = math.NaN()
+			}
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// axisRange 计算迷你图的纵轴范围
+func (sp *Sparkline) axisRange() (float64, float64) {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range sp.Values {
+		if !math.IsNaN(v) {
+			lo = math.Min(lo, v)
+			hi = math.Max(hi, v)
+		}
+	}
+	g := sp.Group
+	switch g.MinAxisType {
+	case "custom":
+		lo = g.ManualMin
+	case "group":
+		lo = g.groupMin
+	}
+	switch g.MaxAxisType {
+	case "custom":
+		hi = g.ManualMax
+	case "group":
+		hi = g.groupMax
+	}
+	return lo, hi
+}
+
+// readWorksheetXML 根据工作簿关系读取指定工作表的原始 XML
+// 超过 excelize 解压大小限制的工作表不在内存中，返回 nil
+func readWorksheetXML(f *excelize.File, sheet string) []byte {
+	name := worksheetPart(f, sheet)
+	if name == "" {
+		return nil
+	}
+	return readPkgPart(f, name)
+}
+
+// readPkgPart 读取文档包中已解压到内存的部件
+func readPkgPart(f *excelize.File, name string) []byte {
+	if v, ok := f.Pkg.Load(name); ok {
+		if data, ok := v.([]byte); ok {
+			return data
+		}
+	}
+	return nil
+}
+
+// worksheetPart 根据工作簿关系解析指定工作表的部件路径（如 xl/worksheets/sheet1.xml）
+func worksheetPart(f *excelize.File, sheet string) string {
+	var wb struct {
+		Sheets []struct {
+			Name string `xml:"name,attr"`
+			RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := xml.Unmarshal(readPkgPart(f, "xl/workbook.xml"), &wb); err != nil {
+		return ""
+	}
+	var rels xlsxRelationships
+	if err := xml.Unmarshal(readPkgPart(f, "xl/_rels/workbook.xml.rels"), &rels); err != nil {
+		return ""
+	}
+	for _, sh := range wb.Sheets {
+		if !strings.EqualFold(sh.Name, sheet) {
+			continue
+		}
+		if target := rels.target(sh.RID, "xl"); target != "" {
+			return target
+		}
+	}
+	return ""
+}
+
+// xlsxRelationships 部件关系（.rels）
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// target 返回关系 ID 对应的部件路径；相对路径以 dir 为基准
+func (r *xlsxRelationships) target(id, dir string) string {
+	for _, rel := range r.Relationships {
+		if rel.ID != id {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/")
+		}
+		return path.Join(dir, rel.Target)
+	}
+	return ""
+}
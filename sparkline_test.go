@@ -0,0 +1,156 @@
+package excelsnapshot
+
+import (
+	"image/color"
+	"path/filepath"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap/zaptest"
+)
+
+// createTestExcelWithSparklines 创建包含折线与柱形迷你图的测试Excel文件
+func createTestExcelWithSparklines(filename string) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	f.SetSheetRow("Sheet1", "A1", &[]interface{}{1, 5, 3, 8, 2})
+	f.SetSheetRow("Sheet1", "A2", &[]interface{}{-2, 4, -1, 6, 3})
+	f.SetColWidth("Sheet1", "F", "F", 20)
+	f.SetRowHeight("Sheet1", 1, 30)
+	f.SetRowHeight("Sheet1", 2, 30)
+
+	if err := f.AddSparkline("Sheet1", &excelize.SparklineOptions{
+		Location: []string{"F1"},
+		Range:    []string{"Sheet1!A1:E1"},
+		Markers:  true,
+		High:     true,
+	}); err != nil {
+		return err
+	}
+	if err := f.AddSparkline("Sheet1", &excelize.SparklineOptions{
+		Location: []string{"F2"},
+		Range:    []string{"Sheet1!A2:E2"},
+		Type:     "column",
+		Negative: true,
+	}); err != nil {
+		return err
+	}
+	return f.SaveAs(filename)
+}
+
+// TestSheet_loadSparklines 测试迷你图解析与数据解析
+func TestSheet_loadSparklines(t *testing.T) {
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "sparkline.xlsx")
+	if err := createTestExcelWithSparklines(testFile); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+
+	logger := zaptest.NewLogger(t)
+	excel, err := NewExcel(testFile, logger)
+	if err != nil {
+		t.Fatalf("加载Excel文件失败: %v", err)
+	}
+	defer excel.Close()
+
+	sheet, err := excel.GetSheet("Sheet1")
+	if err != nil {
+		t.Fatalf("获取工作表失败: %v", err)
+	}
+
+	lines := sheet.Sparklines()
+	if len(lines) != 2 {
+		t.Fatalf("迷你图数量 = %d, want 2", len(lines))
+	}
+
+	byCell := make(map[string]*Sparkline)
+	for _, sp := range lines {
+		byCell[sp.Cell] = sp
+	}
+	line := byCell["F1"]
+	if line == nil || line.Group.Type != "line" || !line.Group.Markers || !line.Group.High {
+		t.Fatalf("F1 迷你图设置不正确: %+v", line)
+	}
+	want := []float64{1, 5, 3, 8, 2}
+	if len(line.Values) != len(want) {
+		t.Fatalf("F1 数据 = %v, want %v", line.Values, want)
+	}
+	for i := range want {
+		if line.Values[i] != want[i] {
+			t.Errorf("F1 数据[%d] = %v, want %v", i, line.Values[i], want[i])
+		}
+	}
+	if col := byCell["F2"]; col == nil || col.Group.Type != "column" || !col.Group.Negative {
+		t.Errorf("F2 迷你图设置不正确: %+v", col)
+	}
+}
+
+// TestSheet_loadSparklines_Spilled 测试工作表超出解压大小限制时仍从原始文件中读取迷你图
+func TestSheet_loadSparklines_Spilled(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "sparkline.xlsx")
+	if err := createTestExcelWithSparklines(testFile); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+
+	// 流式加载不经过 excelize 解析工作表，工作表内容始终只在临时文件中
+	excel, err := NewExcel(testFile, zaptest.NewLogger(t),
+		WithStreaming(true),
+		WithOpenOptions(excelize.Options{UnzipXMLSizeLimit: 1}),
+	)
+	if err != nil {
+		t.Fatalf("加载Excel文件失败: %v", err)
+	}
+	defer excel.Close()
+	if readWorksheetXML(excel.file, "Sheet1") != nil {
+		t.Skip("工作表仍在内存中，无法验证回退路径")
+	}
+
+	sheet, err := excel.GetSheet("Sheet1")
+	if err != nil {
+		t.Fatalf("获取工作表失败: %v", err)
+	}
+	if n := len(sheet.Sparklines()); n != 2 {
+		t.Fatalf("迷你图数量 = %d, want 2", n)
+	}
+}
+
+// TestSheetRenderer_Sparklines 测试迷你图绘制在目标单元格内
+func TestSheetRenderer_Sparklines(t *testing.T) {
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "sparkline.xlsx")
+	if err := createTestExcelWithSparklines(testFile); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+
+	logger := zaptest.NewLogger(t)
+	excel, err := NewExcel(testFile, logger)
+	if err != nil {
+		t.Fatalf("加载Excel文件失败: %v", err)
+	}
+	defer excel.Close()
+
+	sheet, err := excel.GetSheet("Sheet1")
+	if err != nil {
+		t.Fatalf("获取工作表失败: %v", err)
+	}
+
+	renderer := NewSheetRenderer(logger)
+	img, err := renderer.RenderSheet(sheet)
+	if err != nil {
+		t.Fatalf("RenderSheet() 失败: %v", err)
+	}
+
+	rect := renderer.calculateCellRects(sheet)["F2"]
+	painted := 0
+	for y := int(rect.y*renderer.scale) + 6; y < int((rect.y+rect.h)*renderer.scale)-6; y++ {
+		for x := int(rect.x*renderer.scale) + 6; x < int((rect.x+rect.w)*renderer.scale)-6; x++ {
+			if r, g, b, _ := img.At(x, y).RGBA(); !equalColor(color.RGBA{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8), 255}, color.White) {
+				painted++
+			}
+		}
+	}
+	if painted == 0 {
+		t.Error("F2 中未绘制迷你图")
+	}
+}
@@ -0,0 +1,559 @@
+package excelsnapshot
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap"
+)
+
+// 流式加载：不构建 excelize 的完整工作表结构，也不为维度范围内的空白位置创建单元格
+// 列宽、行高、样式索引、公式、合并区域、数据验证与表格通过一次扫描工作表 XML 获得，
+// 单元格值通过 excelize 的 Rows 迭代器读取（共享字符串与数字格式由 excelize 处理）
+
+// fullSheetRange 整张工作表的范围
+var fullSheetRange = cellRange{StartCol: 1, StartRow: 1, EndCol: excelize.MaxColumns, EndRow: excelize.TotalRows}
+
+// worksheetScan 扫描工作表 XML 得到的结构信息（不含单元格值）
+type worksheetScan struct {
+	defaultColWidth  float64
+	defaultRowHeight float64
+	cols             []colSpec
+	rows             map[int]rowSpec
+	merges           []cellRange
+	listValidations  []cellRange
+	tableRIDs        []string
+	// 加载范围左上方带样式的单元格（可能是跨越范围边界的合并区域主单元格）
+	outsideStyles map[[2]int]int
+}
+
+// colSpec <col> 元素：列范围的列宽与列样式
+type colSpec struct {
+	min, max int
+	width    float64
+	style    int
+}
+
+// rowSpec <row> 元素：行高（0 表示未设置）与行样式（仅 customFormat 时有效）
+type rowSpec struct {
+	height float64
+	style  int
+}
+
+// colWidth 返回列宽，规则与 excelize GetColWidth 一致
+func (ws *worksheetScan) colWidth(col int) float64 {
+	var width float64
+	for _, c := range ws.cols {
+		if c.min <= col && col <= c.max && c.width > 0 {
+			width = c.width
+		}
+	}
+	if width != 0 {
+		return width
+	}
+	return ws.defaultColWidth
+}
+
+// rowHeight 返回行高，规则与 excelize GetRowHeight 一致
+func (ws *worksheetScan) rowHeight(row int) float64 {
+	if spec, ok := ws.rows[row]; ok && spec.height > 0 {
+		return spec.height
+	}
+	return ws.defaultRowHeight
+}
+
+// styleAt 返回未显式设置样式的单元格所继承的样式：优先行样式，其次列样式
+func (ws *worksheetScan) styleAt(row, col int) int {
+	if spec, ok := ws.rows[row]; ok && spec.style != 0 {
+		return spec.style
+	}
+	style := 0
+	for _, c := range ws.cols {
+		if c.min <= col && col <= c.max {
+			style = c.style
+		}
+	}
+	return style
+}
+
+// loadStream 以流式方式加载工作表中与 rng 相交的部分（调用方须先调用 beginLoad）
+// 仅保留有值、有样式或有公式的单元格；图片不会被加载
+func (s *Sheet) loadStream(rng cellRange) error {
+	src, err := s.excel.openWorksheet(s.Name)
+	if err != nil {
+		return err
+	}
+	scan, err := s.scanWorksheet(src, rng)
+	src.Close()
+	if err != nil {
+		if isAbort(err) {
+			return err
+		}
+		return fmt.Errorf("解析工作表 %s 失败: %w", s.Name, err)
+	}
+
+	// 合并区域：仅处理与加载范围相交的区域
+	for _, mr := range scan.merges {
+		if mr.EndRow < rng.StartRow || mr.StartRow > rng.EndRow || mr.EndCol < rng.StartCol || mr.StartCol > rng.EndCol {
+			continue
+		}
+		if err := s.guard.reserve(mr); err != nil {
+			return err
+		}
+		if mainCell := s.markMerged(mr, &rng); mainCell.StyleIndex == 0 {
+			if style, ok := scan.outsideStyles[[2]int{mainCell.Row, mainCell.Col}]; ok {
+				mainCell.StyleIndex = style
+			} else {
+				mainCell.StyleIndex = scan.styleAt(mainCell.Row, mainCell.Col)
+			}
+		}
+	}
+
+	// 单元格值：读取到加载范围的最后一行为止
+	rows, err := s.excel.file.Rows(s.Name)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for r := 1; r <= rng.EndRow && rows.Next(); r++ {
+		// 范围之前的行只需读取合并区域主单元格的值
+		if r < rng.StartRow && len(s.cells.row(r)) == 0 {
+			continue
+		}
+		values, err := rows.Columns()
+		if err != nil {
+			return err
+		}
+		for i, v := range values {
+			c := i + 1
+			if c > rng.EndCol {
+				break
+			}
+			if v == "" {
+				continue
+			}
+			cell := s.cells.at(r, c)
+			if cell == nil {
+				if !rng.contains(r, c) {
+					continue
+				}
+				addr, _ := excelize.CoordinatesToCellName(c, r)
+				cell = &Cell{Sheet: s, Row: r, Col: c, Address: addr, StyleIndex: scan.styleAt(r, c)}
+				s.cells.put(cell)
+			}
+			cell.Value = v
+			if err := s.guard.step(s.cells.len()); err != nil {
+				return err
+			}
+		}
+	}
+	if err := rows.Error(); err != nil {
+		return err
+	}
+
+	if s.excel.evalFormulas {
+		if err := s.evaluateFormulas(); err != nil {
+			return err
+		}
+	}
+
+	// 迷你图：仅保留加载范围内的迷你图
+	if err := s.loadSparklines(); err != nil {
+		s.excel.logger.Warn("加载迷你图失败", zap.Error(err))
+	}
+	sparklines := s.sparklines[:0]
+	for _, sp := range s.sparklines {
+		if !rng.contains(sp.Row, sp.Col) {
+			continue
+		}
+		sparklines = append(sparklines, sp)
+		if s.cells.at(sp.Row, sp.Col) == nil {
+			s.cells.put(&Cell{Sheet: s, Row: sp.Row, Col: sp.Col, Address: sp.Cell, StyleIndex: scan.styleAt(sp.Row, sp.Col)})
+		}
+	}
+	s.sparklines = sparklines
+
+	// 绑定样式；同时统计内容边界
+	styleCacheMiss := 0
+	maxRow, maxCol := rng.StartRow, rng.StartCol
+	err = s.cells.each(func(cell *Cell) error {
+		miss, err := s.bindStyle(cell, cell.StyleIndex)
+		if miss {
+			styleCacheMiss++
+		}
+		maxRow, maxCol = max(maxRow, cell.Row), max(maxCol, cell.Col)
+		if err != nil {
+			return err
+		}
+		return s.guard.step(0)
+	})
+	if err != nil {
+		return err
+	}
+	// 指定了区域时按区域大小渲染，否则按内容边界渲染
+	if rng != fullSheetRange {
+		maxRow, maxCol = rng.EndRow, rng.EndCol
+	}
+
+	// 表格：流式加载不保留空单元格，为表格区域内缺失的单元格补建占位单元格，以便绘制表格的填充与条纹
+	if err := s.loadStreamTables(scan.tableRIDs); err != nil {
+		s.excel.logger.Warn("加载表格失败", zap.Error(err))
+	}
+	for _, t := range s.tables {
+		tr := cellRange{StartRow: max(t.StartRow, rng.StartRow), StartCol: max(t.StartCol, rng.StartCol), EndRow: min(t.EndRow, maxRow), EndCol: min(t.EndCol, maxCol)}
+		if tr.StartRow > tr.EndRow || tr.StartCol > tr.EndCol {
+			continue
+		}
+		if err := s.guard.reserve(tr); err != nil {
+			return err
+		}
+		for r := tr.StartRow; r <= tr.EndRow; r++ {
+			for c := tr.StartCol; c <= tr.EndCol; c++ {
+				if s.cells.at(r, c) != nil {
+					continue
+				}
+				addr, _ := excelize.CoordinatesToCellName(c, r)
+				cell := &Cell{Sheet: s, Row: r, Col: c, Address: addr}
+				if _, err := s.bindStyle(cell, scan.styleAt(r, c)); err != nil {
+					return err
+				}
+				s.cells.put(cell)
+			}
+			if err := s.guard.step(s.cells.len()); err != nil {
+				return err
+			}
+		}
+	}
+	s.bindTableStyles()
+	s.excel.logger.Debug("流式加载工作表", zap.String("sheet", s.Name), zap.Int("rows", maxRow-rng.StartRow+1), zap.Int("cols", maxCol-rng.StartCol+1), zap.Int("cells", s.cells.len()), zap.Int("style_miss", styleCacheMiss))
+
+	for col := rng.StartCol; col <= maxCol; col++ {
+		colLetter, _ := excelize.ColumnNumberToName(col)
+		s.colWidthMap[colLetter] = scan.colWidth(col)
+	}
+	s.optimizeColumnWidths()
+	for rowNum := rng.StartRow; rowNum <= maxRow; rowNum++ {
+		height := scan.rowHeight(rowNum)
+		// 15 是 Excel 的默认行高，需要通过估算进行调整
+		if height == 15 {
+			height = s.estimateRowHeight(rowNum)
+		}
+		s.rowHeightMap[rowNum] = height
+		if err := s.guard.step(0); err != nil {
+			return err
+		}
+	}
+
+	s.loadAutoFilter()
+	s.listValidations = scan.listValidations
+	s.excel.logger.Debug("流式加载不包含图片", zap.String("sheet", s.Name))
+
+	s.startRow, s.startCol = rng.StartRow, rng.StartCol
+	s.Rows = maxRow
+	s.Cols = maxCol
+	s.MaxColName, _ = excelize.ColumnNumberToName(maxCol)
+	return nil
+}
+
+// scanWorksheet 逐个读取工作表 XML 的元素，收集结构信息
+// 范围内带样式或公式的单元格在此创建（值稍后填充）；共享公式的从属单元格没有公式文本
+func (s *Sheet) scanWorksheet(r io.Reader, rng cellRange) (*worksheetScan, error) {
+	scan := &worksheetScan{
+		defaultColWidth:  9.140625,
+		defaultRowHeight: 15,
+		rows:             make(map[int]rowSpec),
+		outsideStyles:    make(map[[2]int]int),
+	}
+
+	var (
+		row, col, style int
+		inFormula       bool
+		formula         strings.Builder
+	)
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return scan, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch el := tok.(type) {
+		case xml.StartElement:
+			switch el.Name.Local {
+			case "sheetFormatPr":
+				if w, err := strconv.ParseFloat(xmlAttr(el, "defaultColWidth"), 64); err == nil && w > 0 {
+					scan.defaultColWidth = w
+				}
+				if xmlBoolAttr(el, "customHeight") {
+					if h, err := strconv.ParseFloat(xmlAttr(el, "defaultRowHeight"), 64); err == nil {
+						scan.defaultRowHeight = h
+					}
+				}
+			case "col":
+				spec := colSpec{}
+				spec.min, _ = strconv.Atoi(xmlAttr(el, "min"))
+				spec.max, _ = strconv.Atoi(xmlAttr(el, "max"))
+				spec.width, _ = strconv.ParseFloat(xmlAttr(el, "width"), 64)
+				spec.style, _ = strconv.Atoi(xmlAttr(el, "style"))
+				scan.cols = append(scan.cols, spec)
+			case "row":
+				row++
+				if n, err := strconv.Atoi(xmlAttr(el, "r")); err == nil {
+					row = n
+				}
+				col = 0
+				spec := rowSpec{}
+				spec.height, _ = strconv.ParseFloat(xmlAttr(el, "ht"), 64)
+				if xmlBoolAttr(el, "customFormat") {
+					spec.style, _ = strconv.Atoi(xmlAttr(el, "s"))
+				}
+				if spec != (rowSpec{}) {
+					scan.rows[row] = spec
+				}
+			case "c":
+				col++
+				if ref := xmlAttr(el, "r"); ref != "" {
+					if c, _, err := excelize.CellNameToCoordinates(ref); err == nil {
+						col = c
+					}
+				}
+				style, _ = strconv.Atoi(xmlAttr(el, "s"))
+				formula.Reset()
+			case "f":
+				inFormula = true
+			case "mergeCell":
+				if sc, sr, ec, er, err := parseRangeRef(xmlAttr(el, "ref")); err == nil {
+					scan.merges = append(scan.merges, cellRange{StartCol: sc, StartRow: sr, EndCol: ec, EndRow: er})
+				}
+			case "dataValidation":
+				// 注意：OOXML 中 showDropDown=true 表示“隐藏”下拉箭头
+				if xmlAttr(el, "type") != "list" || xmlBoolAttr(el, "showDropDown") {
+					break
+				}
+				for _, ref := range strings.Fields(xmlAttr(el, "sqref")) {
+					if sc, sr, ec, er, err := parseRangeRef(ref); err == nil {
+						scan.listValidations = append(scan.listValidations, cellRange{StartCol: sc, StartRow: sr, EndCol: ec, EndRow: er})
+					}
+				}
+			case "tablePart":
+				if id := xmlAttr(el, "id"); id != "" {
+					scan.tableRIDs = append(scan.tableRIDs, id)
+				}
+			}
+		case xml.CharData:
+			if inFormula {
+				formula.Write(el)
+			}
+		case xml.EndElement:
+			switch el.Name.Local {
+			case "f":
+				inFormula = false
+			case "c":
+				switch {
+				case rng.contains(row, col):
+					if style != 0 || formula.Len() > 0 {
+						addr, _ := excelize.CoordinatesToCellName(col, row)
+						s.cells.put(&Cell{Sheet: s, Row: row, Col: col, Address: addr, StyleIndex: style, Formula: formula.String()})
+					}
+				case style != 0 && row <= rng.EndRow && col <= rng.EndCol:
+					scan.outsideStyles[[2]int{row, col}] = style
+				}
+				if err := s.guard.step(s.cells.len()); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+}
+
+// loadStreamTables 通过工作表关系读取表格定义（不经过 excelize 的完整工作表结构）
+func (s *Sheet) loadStreamTables(rids []string) error {
+	s.tables = nil
+	if len(rids) == 0 {
+		return nil
+	}
+	part := worksheetPart(s.excel.file, s.Name)
+	data, err := s.excel.readPart(path.Join(path.Dir(part), "_rels", path.Base(part)+".rels"))
+	if err != nil {
+		return err
+	}
+	var rels xlsxRelationships
+	if err := xml.Unmarshal(data, &rels); err != nil {
+		return err
+	}
+	for _, rid := range rids {
+		var t xlsxTablePart
+		data, err := s.excel.readPart(rels.target(rid, path.Dir(part)))
+		if err == nil {
+			err = xml.Unmarshal(data, &t)
+		}
+		if err != nil {
+			s.excel.logger.Debug("读取表格定义失败", zap.String("sheet", s.Name), zap.String("rid", rid), zap.Error(err))
+			continue
+		}
+		if tbl := s.newSheetTable(t.table(), t.tablePartInfo); tbl != nil {
+			s.tables = append(s.tables, tbl)
+		}
+	}
+	s.excel.logger.Debug("表格加载完成", zap.String("sheet", s.Name), zap.Int("tables", len(s.tables)))
+	return nil
+}
+
+// xlsxTablePart 表格定义部件（xl/tables/tableN.xml）
+type xlsxTablePart struct {
+	tablePartInfo
+	Ref       string `xml:"ref,attr"`
+	StyleInfo *struct {
+		Name              string `xml:"name,attr"`
+		ShowFirstColumn   bool   `xml:"showFirstColumn,attr"`
+		ShowLastColumn    bool   `xml:"showLastColumn,attr"`
+		ShowRowStripes    bool   `xml:"showRowStripes,attr"`
+		ShowColumnStripes bool   `xml:"showColumnStripes,attr"`
+	} `xml:"tableStyleInfo"`
+}
+
+// table 转换为与 excelize GetTables 结果一致的结构
+func (t *xlsxTablePart) table() excelize.Table {
+	table := excelize.Table{Range: t.Ref, Name: t.Name}
+	if si := t.StyleInfo; si != nil {
+		table.StyleName = si.Name
+		table.ShowFirstColumn = si.ShowFirstColumn
+		table.ShowLastColumn = si.ShowLastColumn
+		table.ShowRowStripes = &si.ShowRowStripes
+		table.ShowColumnStripes = si.ShowColumnStripes
+	}
+	return table
+}
+
+// openWorksheet 打开工作表的原始 XML
+// 已解压到内存的部件直接读取；超过 excelize 解压大小限制的工作表从原始文件中重新读取
+func (e *Excel) openWorksheet(sheet string) (io.ReadCloser, error) {
+	part := worksheetPart(e.file, sheet)
+	if part == "" {
+		return nil, fmt.Errorf("工作表 %s 不存在", sheet)
+	}
+	rc, err := e.openPart(part)
+	if err != nil {
+		return nil, fmt.Errorf("无法读取工作表 %s 的数据: %w", sheet, err)
+	}
+	return rc, nil
+}
+
+// openPart 打开文档包中的部件：优先读取内存中的部件，否则从原始文件（或解密后的内容）中读取
+func (e *Excel) openPart(part string) (io.ReadCloser, error) {
+	if data := readPkgPart(e.file, part); data != nil {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+	archive, closer, err := e.openArchive()
+	if err != nil {
+		return nil, err
+	}
+	for _, zf := range archive.File {
+		if strings.TrimPrefix(zf.Name, "/") != part {
+			continue
+		}
+		rc, err := zf.Open()
+		if err != nil {
+			if closer != nil {
+				closer.Close()
+			}
+			return nil, err
+		}
+		return &zipPartReader{ReadCloser: rc, archive: closer}, nil
+	}
+	if closer != nil {
+		closer.Close()
+	}
+	return nil, fmt.Errorf("部件 %s 不存在", part)
+}
+
+// readPart 读取文档包中部件的完整内容，回退规则同 openPart
+func (e *Excel) readPart(part string) ([]byte, error) {
+	if data := readPkgPart(e.file, part); data != nil {
+		return data, nil
+	}
+	rc, err := e.openPart(part)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+// openArchive 打开原始工作簿压缩包；加密工作簿在内存中解密
+func (e *Excel) openArchive() (*zip.Reader, io.Closer, error) {
+	if e.data == nil && e.path != "" {
+		zr, err := zip.OpenReader(e.path)
+		if err == nil {
+			return &zr.Reader, zr, nil
+		}
+		if !errors.Is(err, zip.ErrFormat) {
+			return nil, nil, err
+		}
+	}
+	data := e.data
+	if data == nil {
+		if e.path == "" {
+			return nil, nil, fmt.Errorf("没有可用的工作簿内容")
+		}
+		raw, err := os.ReadFile(e.path)
+		if err != nil {
+			return nil, nil, err
+		}
+		data = raw
+	}
+	if isEncryptedWorkbook(data) {
+		opts := e.excelizeOptions()
+		decrypted, err := excelize.Decrypt(data, &opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		data = decrypted
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	return zr, nil, err
+}
+
+// zipPartReader 关闭部件时一并关闭压缩包文件（如有）
+type zipPartReader struct {
+	io.ReadCloser
+	archive io.Closer
+}
+
+func (z *zipPartReader) Close() error {
+	err := z.ReadCloser.Close()
+	if z.archive == nil {
+		return err
+	}
+	if cerr := z.archive.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// xmlAttr 返回元素属性值（忽略命名空间）
+func xmlAttr(el xml.StartElement, name string) string {
+	for _, a := range el.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// xmlBoolAttr 解析 xsd:boolean 属性
+func xmlBoolAttr(el xml.StartElement, name string) bool {
+	v := xmlAttr(el, name)
+	return v == "1" || v == "true"
+}
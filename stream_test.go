@@ -0,0 +1,213 @@
+package excelsnapshot
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap/zaptest"
+)
+
+// createTestExcelForStream 创建包含合并单元格、样式、公式、行高列宽、表格与下拉列表的测试Excel文件
+func createTestExcelForStream(filename string) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	for r := 1; r <= 20; r++ {
+		for c := 1; c <= 6; c++ {
+			addr, _ := excelize.CoordinatesToCellName(c, r)
+			f.SetCellValue("Sheet1", addr, r*c)
+		}
+	}
+	f.SetCellValue("Sheet1", "B3", "合并单元格")
+	f.MergeCell("Sheet1", "B3", "D6")
+	fill, _ := f.NewStyle(&excelize.Style{
+		Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"FFD966"}},
+	})
+	f.SetCellStyle("Sheet1", "B3", "D6", fill)
+	// 只有样式没有值的单元格
+	f.SetCellStyle("Sheet1", "H2", "H2", fill)
+	f.SetCellFormula("Sheet1", "F21", "SUM(F1:F20)")
+	f.SetColWidth("Sheet1", "C", "C", 20)
+	f.SetRowHeight("Sheet1", 10, 40)
+
+	dv := excelize.NewDataValidation(true)
+	dv.Sqref = "A22"
+	dv.SetDropList([]string{"是", "否"})
+	f.AddDataValidation("Sheet1", dv)
+
+	if err := f.AddTable("Sheet1", &excelize.Table{Range: "A25:B27", Name: "Items", StyleName: "TableStyleMedium2"}); err != nil {
+		return err
+	}
+	// 表格中只有最后一个单元格有值，其余数据单元格为空
+	f.SetCellValue("Sheet1", "B27", 1)
+	return f.SaveAs(filename)
+}
+
+// TestSheet_loadStream 测试流式加载与常规加载结果一致
+func TestSheet_loadStream(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "stream.xlsx")
+	if err := createTestExcelForStream(testFile); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+	logger := zaptest.NewLogger(t)
+
+	load := func(opts ...ExcelOption) *Sheet {
+		excel, err := NewExcel(testFile, logger, opts...)
+		if err != nil {
+			t.Fatalf("加载Excel文件失败: %v", err)
+		}
+		t.Cleanup(func() { excel.Close() })
+		sheet, err := excel.GetSheet("Sheet1")
+		if err != nil {
+			t.Fatalf("获取工作表失败: %v", err)
+		}
+		return sheet
+	}
+	normal := load()
+	stream := load(WithStreaming(true))
+
+	if stream.Rows != normal.Rows || stream.Cols != normal.Cols {
+		t.Errorf("流式加载范围 = %dx%d, want %dx%d", stream.Rows, stream.Cols, normal.Rows, normal.Cols)
+	}
+
+	// 常规加载中有值或有样式的单元格在流式加载中应一致
+	normal.cells.each(func(want *Cell) error {
+		if want.Value == "" && want.StyleIndex == 0 && want.Formula == "" && !want.IsMerged {
+			return nil
+		}
+		got := stream.cells.at(want.Row, want.Col)
+		if got == nil {
+			t.Errorf("流式加载缺少单元格 %s", want.Address)
+			return nil
+		}
+		if got.Value != want.Value || got.StyleIndex != want.StyleIndex || got.Formula != want.Formula || got.IsMerged != want.IsMerged {
+			t.Errorf("单元格 %s = {%q %d %q %v}, want {%q %d %q %v}", want.Address,
+				got.Value, got.StyleIndex, got.Formula, got.IsMerged,
+				want.Value, want.StyleIndex, want.Formula, want.IsMerged)
+		}
+		return nil
+	})
+
+	for col, want := range normal.colWidthMap {
+		if got := stream.GetColWidth(col); got != want {
+			t.Errorf("列 %s 宽度 = %v, want %v", col, got, want)
+		}
+	}
+	for row, want := range normal.rowHeightMap {
+		if got := stream.GetRowHeight(row); got != want {
+			t.Errorf("行 %d 高度 = %v, want %v", row, got, want)
+		}
+	}
+
+	if len(stream.Tables()) != 1 || stream.Tables()[0].Range != "A25:B27" || stream.Tables()[0].style == nil {
+		t.Errorf("流式加载表格 = %+v, want 一个 A25:B27 的表格", stream.Tables())
+	}
+	// 表格区域内的空单元格也应存在，以便绘制表格的填充与条纹
+	for r := 25; r <= 27; r++ {
+		for c := 1; c <= 2; c++ {
+			cell := stream.cells.at(r, c)
+			if cell == nil {
+				t.Errorf("流式加载缺少表格单元格 (%d,%d)", r, c)
+				continue
+			}
+			style, err := cell.RenderStyle()
+			if err != nil || style == nil {
+				t.Errorf("表格单元格 %s 样式 = %+v, %v, want 表格样式", cell.Address, style, err)
+				continue
+			}
+			// 标题行与第一条带状行有填充
+			if r <= 26 && len(style.Fill.Color) == 0 {
+				t.Errorf("表格单元格 %s 没有填充", cell.Address)
+			}
+		}
+	}
+	if !stream.HasListValidation(22, 1) {
+		t.Error("流式加载应包含 A22 的下拉列表")
+	}
+}
+
+// TestSheet_loadStreamSparse 测试维度很大的稀疏工作表只保留有内容的单元格
+func TestSheet_loadStreamSparse(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "sparse.xlsx")
+	f := excelize.NewFile()
+	f.SetCellValue("Sheet1", "A1", "起点")
+	f.SetCellValue("Sheet1", "CV5000", "终点")
+	if err := f.SaveAs(testFile); err != nil {
+		t.Fatalf("保存测试文件失败: %v", err)
+	}
+	f.Close()
+
+	excel, err := NewExcel(testFile, zaptest.NewLogger(t), WithStreaming(true))
+	if err != nil {
+		t.Fatalf("加载Excel文件失败: %v", err)
+	}
+	defer excel.Close()
+	sheet, err := excel.GetSheet("Sheet1")
+	if err != nil {
+		t.Fatalf("获取工作表失败: %v", err)
+	}
+
+	if sheet.cells.len() != 2 {
+		t.Errorf("单元格数量 = %d, want 2", sheet.cells.len())
+	}
+	if sheet.Rows != 5000 || sheet.Cols != 100 {
+		t.Errorf("工作表范围 = %dx%d, want 5000x100", sheet.Rows, sheet.Cols)
+	}
+	if cell := sheet.cells.get("CV5000"); cell == nil || cell.Value != "终点" {
+		t.Errorf("CV5000 = %+v, want 终点", cell)
+	}
+}
+
+// TestExcel_GetSheetRange 测试按区域加载与渲染
+func TestExcel_GetSheetRange(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "range.xlsx")
+	if err := createTestExcelForStream(testFile); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+	logger := zaptest.NewLogger(t)
+	excel, err := NewExcel(testFile, logger)
+	if err != nil {
+		t.Fatalf("加载Excel文件失败: %v", err)
+	}
+	defer excel.Close()
+
+	// C4:E12 与合并区域 B3:D6 部分相交
+	sheet, err := excel.GetSheetRange("Sheet1", "C4:E12")
+	if err != nil {
+		t.Fatalf("GetSheetRange() 失败: %v", err)
+	}
+	if sheet.startRow != 4 || sheet.startCol != 3 || sheet.Rows != 12 || sheet.Cols != 5 {
+		t.Errorf("区域 = %d,%d..%d,%d, want 4,3..12,5", sheet.startRow, sheet.startCol, sheet.Rows, sheet.Cols)
+	}
+	sheet.cells.each(func(cell *Cell) error {
+		if cell.Address != "B3" && (cell.Row < 4 || cell.Row > 12 || cell.Col < 3 || cell.Col > 5) {
+			t.Errorf("区域外的单元格 %s 不应被加载", cell.Address)
+		}
+		return nil
+	})
+	if main := sheet.cells.get("B3"); main == nil || main.Value != "合并单元格" {
+		t.Errorf("跨越区域边界的合并单元格主单元格 = %+v, want 合并单元格", main)
+	}
+
+	renderer := NewSheetRenderer(logger)
+	img, err := renderer.RenderSheet(sheet)
+	if err != nil {
+		t.Fatalf("RenderSheet() 失败: %v", err)
+	}
+	wantW, wantH := 0.0, 0.0
+	for _, col := range []string{"C", "D", "E"} {
+		wantW += sheet.ColWidthPixels(col)
+	}
+	for row := 4; row <= 12; row++ {
+		wantH += sheet.RowHeightPixels(row)
+	}
+	gotW, gotH := canvasSize(wantW, wantH, renderer.Scale())
+	if img.Bounds().Dx() != gotW || img.Bounds().Dy() != gotH {
+		t.Errorf("图片尺寸 = %dx%d, want %dx%d", img.Bounds().Dx(), img.Bounds().Dy(), gotW, gotH)
+	}
+
+	if _, err := excel.GetSheetRange("Sheet1", "无效"); err == nil {
+		t.Error("无效的区域应该返回错误")
+	}
+}
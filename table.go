@@ -0,0 +1,339 @@
+package excelsnapshot
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap"
+)
+
+// SheetTable 工作表中的表格（ListObject）信息
+type SheetTable struct {
+	Name      string
+	Range     string
+	StyleName string
+
+	StartCol int
+	StartRow int
+	EndCol   int
+	EndRow   int
+
+	// 标题行与汇总行数量（0 表示不显示）
+	HeaderRows int
+	TotalsRows int
+
+	ShowFirstColumn   bool
+	ShowLastColumn    bool
+	ShowRowStripes    bool
+	ShowColumnStripes bool
+
+	// 标题行是否带有自动筛选按钮
+	HasAutoFilter bool
+
+	// 解析后的内置表格样式（未识别的样式为 nil，按普通单元格渲染）
+	style *tableStyleDef
+}
+
+// tablePartInfo 表格定义中 excelize GetTables 未提供的信息（标题行/汇总行数量、自动筛选）
+type tablePartInfo struct {
+	Name           string `xml:"name,attr"`
+	HeaderRowCount *int   `xml:"headerRowCount,attr"`
+	TotalsRowCount int    `xml:"totalsRowCount,attr"`
+	AutoFilter     *struct {
+		Ref string `xml:"ref,attr"`
+	} `xml:"autoFilter"`
+}
+
+// Contains 判断坐标是否位于表格范围内
+func (t *SheetTable) Contains(row, col int) bool {
+	return row >= t.StartRow && row <= t.EndRow && col >= t.StartCol && col <= t.EndCol
+}
+
+// Tables 返回工作表中的表格
+func (s *Sheet) Tables() []*SheetTable {
+	return s.tables
+}
+
+// loadTables 加载工作表中的表格定义及其样式
+func (s *Sheet) loadTables() error {
+	s.tables = nil
+	tables, err := s.excel.file.GetTables(s.Name)
+	if err != nil {
+		return err
+	}
+	if len(tables) == 0 {
+		return nil
+	}
+	parts := s.excel.readTableParts(tables)
+
+	for _, t := range tables {
+		if tbl := s.newSheetTable(t, parts[t.Name]); tbl != nil {
+			s.tables = append(s.tables, tbl)
+		}
+	}
+	s.excel.logger.Debug("表格加载完成", zap.String("sheet", s.Name), zap.Int("tables", len(s.tables)))
+	return nil
+}
+
+// newSheetTable 根据表格定义构造 SheetTable，范围无效时返回 nil
+func (s *Sheet) newSheetTable(t excelize.Table, info tablePartInfo) *SheetTable {
+	sc, sr, ec, er, err := parseRangeRef(t.Range)
+	if err != nil {
+		return nil
+	}
+	tbl := &SheetTable{
+		Name:              t.Name,
+		Range:             t.Range,
+		StyleName:         t.StyleName,
+		StartCol:          sc,
+		StartRow:          sr,
+		EndCol:            ec,
+		EndRow:            er,
+		HeaderRows:        1,
+		ShowFirstColumn:   t.ShowFirstColumn,
+		ShowLastColumn:    t.ShowLastColumn,
+		ShowRowStripes:    t.ShowRowStripes != nil && *t.ShowRowStripes,
+		ShowColumnStripes: t.ShowColumnStripes,
+	}
+	if info.HeaderRowCount != nil {
+		tbl.HeaderRows = *info.HeaderRowCount
+	}
+	tbl.TotalsRows = info.TotalsRowCount
+	tbl.HasAutoFilter = info.AutoFilter != nil && tbl.HeaderRows > 0
+	if t.StyleName != "" {
+		if def, ok := lookupTableStyle(t.StyleName); ok {
+			tbl.style = def
+		} else {
+			s.excel.logger.Debug("未识别的表格样式，按普通单元格渲染", zap.String("table", t.Name), zap.String("style", t.StyleName))
+		}
+	}
+	return tbl
+}
+
+// readTableParts 直接读取 xl/tables/*.xml，获取各表格的补充信息
+// 内存中缺少的表格部件从原始文件（或解密后的内容）中读取
+func (e *Excel) readTableParts(tables []excelize.Table) map[string]tablePartInfo {
+	infos := make(map[string]tablePartInfo)
+	e.file.Pkg.Range(func(k, v interface{}) bool {
+		name, ok := k.(string)
+		if !ok || !isTablePart(name) {
+			return true
+		}
+		if data, ok := v.([]byte); ok {
+			addTablePartInfo(infos, bytes.NewReader(data))
+		}
+		return true
+	})
+
+	missing := false
+	for _, t := range tables {
+		if _, ok := infos[t.Name]; !ok {
+			missing = true
+			break
+		}
+	}
+	if !missing {
+		return infos
+	}
+	archive, closer, err := e.openArchive()
+	if err != nil {
+		e.logger.Debug("读取表格部件失败", zap.Error(err))
+		return infos
+	}
+	if closer != nil {
+		defer closer.Close()
+	}
+	for _, zf := range archive.File {
+		name := strings.TrimPrefix(zf.Name, "/")
+		if !isTablePart(name) || readPkgPart(e.file, name) != nil {
+			continue
+		}
+		rc, err := zf.Open()
+		if err != nil {
+			e.logger.Debug("读取表格部件失败", zap.String("part", name), zap.Error(err))
+			continue
+		}
+		addTablePartInfo(infos, rc)
+		rc.Close()
+	}
+	return infos
+}
+
+// isTablePart 判断部件路径是否为表格定义（xl/tables/*.xml）
+func isTablePart(name string) bool {
+	return strings.HasPrefix(name, "xl/tables/") && strings.HasSuffix(name, ".xml")
+}
+
+// addTablePartInfo 解析表格定义部件并按表格名称记录
+func addTablePartInfo(infos map[string]tablePartInfo, r io.Reader) {
+	var info tablePartInfo
+	if err := xml.NewDecoder(r).Decode(&info); err == nil && info.Name != "" {
+		infos[info.Name] = info
+	}
+}
+
+// bindTableStyles 为表格范围内已加载的单元格计算叠加表格样式后的渲染样式，渲染时直接复用
+func (s *Sheet) bindTableStyles() {
+	for _, t := range s.tables {
+		if t.style == nil {
+			continue
+		}
+		for r := t.StartRow; r <= t.EndRow; r++ {
+			for _, cell := range s.cells.rows[r] {
+				if cell.Col < t.StartCol || cell.Col > t.EndCol || cell.renderStyle != nil {
+					continue
+				}
+				tbl := t.cellStyle(r, cell.Col)
+				if tbl == nil {
+					continue
+				}
+				if style, err := cell.Style(); err == nil && style != nil {
+					tbl = mergeTableStyle(tbl, style)
+				}
+				cell.renderStyle = tbl
+			}
+		}
+	}
+}
+
+// cellStyle 按 Excel 的叠加顺序计算表格内某个单元格的样式
+func (t *SheetTable) cellStyle(row, col int) *excelize.Style {
+	var acc tableCellFormat
+	def := t.style
+
+	bodyStart := t.StartRow + t.HeaderRows
+	bodyEnd := t.EndRow - t.TotalsRows
+	isHeader := row < bodyStart
+	isTotal := row > bodyEnd
+	isBody := !isHeader && !isTotal
+
+	acc.apply(def.WholeTable, row, col, t.StartRow, t.StartCol, t.EndRow, t.EndCol)
+	if t.ShowColumnStripes && isBody && (col-t.StartCol)%2 == 0 {
+		acc.apply(def.FirstColumnStripe, row, col, bodyStart, col, bodyEnd, col)
+	}
+	if t.ShowRowStripes && isBody && (row-bodyStart)%2 == 0 {
+		acc.apply(def.FirstRowStripe, row, col, row, t.StartCol, row, t.EndCol)
+	}
+	if t.ShowLastColumn && col == t.EndCol {
+		acc.apply(def.LastColumn, row, col, t.StartRow, col, t.EndRow, col)
+	}
+	if t.ShowFirstColumn && col == t.StartCol {
+		acc.apply(def.FirstColumn, row, col, t.StartRow, col, t.EndRow, col)
+	}
+	if isHeader {
+		acc.apply(def.HeaderRow, row, col, t.StartRow, t.StartCol, bodyStart-1, t.EndCol)
+	}
+	if isTotal {
+		acc.apply(def.TotalRow, row, col, bodyEnd+1, t.StartCol, t.EndRow, t.EndCol)
+	}
+	return acc.style()
+}
+
+// tableCellFormat 单元格上叠加后的表格格式
+type tableCellFormat struct {
+	fill      string
+	fontColor string
+	bold      bool
+	top       string
+	bottom    string
+	left      string
+	right     string
+}
+
+// apply 将样式元素叠加到单元格上，(r0,c0)-(r1,c1) 为该元素作用的区域
+func (f *tableCellFormat) apply(el *tableStyleElement, row, col, r0, c0, r1, c1 int) {
+	if el == nil {
+		return
+	}
+	if el.Fill != "" {
+		f.fill = el.Fill
+	}
+	if el.FontColor != "" {
+		f.fontColor = el.FontColor
+	}
+	f.bold = f.bold || el.Bold
+
+	pick := func(edge bool, outer, inner string) string {
+		if edge {
+			return outer
+		}
+		return inner
+	}
+	if v := pick(row == r0, el.Border.Top, el.Border.InsideH); v != "" {
+		f.top = v
+	}
+	if v := pick(row == r1, el.Border.Bottom, el.Border.InsideH); v != "" {
+		f.bottom = v
+	}
+	if v := pick(col == c0, el.Border.Left, el.Border.InsideV); v != "" {
+		f.left = v
+	}
+	if v := pick(col == c1, el.Border.Right, el.Border.InsideV); v != "" {
+		f.right = v
+	}
+}
+
+// style 转换为 excelize.Style，便于与单元格样式统一处理
+func (f *tableCellFormat) style() *excelize.Style {
+	st := &excelize.Style{
+		Font: &excelize.Font{Bold: f.bold, Color: f.fontColor},
+	}
+	if f.fill != "" {
+		st.Fill = excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{f.fill}}
+	}
+	for _, b := range []struct{ typ, color string }{
+		{"left", f.left}, {"right", f.right}, {"top", f.top}, {"bottom", f.bottom},
+	} {
+		if b.color != "" {
+			st.Border = append(st.Border, excelize.Border{Type: b.typ, Color: b.color, Style: 1})
+		}
+	}
+	return st
+}
+
+// mergeTableStyle 将单元格显式样式叠加在表格样式之上
+// 显式填充、非默认字体颜色与显式边框优先，其余沿用表格样式
+func mergeTableStyle(tbl, explicit *excelize.Style) *excelize.Style {
+	merged := *explicit
+
+	if len(explicit.Fill.Color) == 0 || (explicit.Fill.Type == "pattern" && explicit.Fill.Pattern == 0) {
+		merged.Fill = tbl.Fill
+	}
+
+	font := excelize.Font{}
+	if explicit.Font != nil {
+		font = *explicit.Font
+	}
+	if tbl.Font != nil {
+		if isDefaultFontColor(font.Color) {
+			font.Color = tbl.Font.Color
+		}
+		font.Bold = font.Bold || tbl.Font.Bold
+	}
+	merged.Font = &font
+
+	merged.Border = append([]excelize.Border(nil), tbl.Border...)
+	for _, b := range explicit.Border {
+		replaced := false
+		for i := range merged.Border {
+			if merged.Border[i].Type == b.Type {
+				merged.Border[i] = b
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged.Border = append(merged.Border, b)
+		}
+	}
+	return &merged
+}
+
+// isDefaultFontColor 判断字体颜色是否为默认值（未设置或黑色）
+func isDefaultFontColor(c string) bool {
+	c = strings.TrimPrefix(strings.ToUpper(c), "#")
+	return c == "" || c == "000000" || c == "FF000000"
+}
@@ -0,0 +1,205 @@
+package excelsnapshot
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Office 默认主题色（dk1 与 accent1~accent6），内置表格样式均基于这些颜色派生
+var themeTableColors = []string{"000000", "4472C4", "ED7D31", "A5A5A5", "FFC000", "5B9BD5", "70AD47"}
+
+// tableStyleBorder 表格样式元素的边框颜色（空串表示无边框）
+// Top/Bottom/Left/Right 作用于元素区域的外边，InsideH/InsideV 作用于区域内部
+type tableStyleBorder struct {
+	Top, Bottom, Left, Right string
+	InsideH, InsideV         string
+}
+
+// tableStyleElement 表格样式中的一个元素（整表、标题行、汇总行、条纹等）
+type tableStyleElement struct {
+	Fill      string
+	FontColor string
+	Bold      bool
+	Border    tableStyleBorder
+}
+
+// tableStyleDef 表格样式定义，元素按 Excel 的叠加顺序从低到高排列
+type tableStyleDef struct {
+	WholeTable        *tableStyleElement
+	FirstColumnStripe *tableStyleElement
+	FirstRowStripe    *tableStyleElement
+	LastColumn        *tableStyleElement
+	FirstColumn       *tableStyleElement
+	HeaderRow         *tableStyleElement
+	TotalRow          *tableStyleElement
+}
+
+// builtinTableStyles 内置表格样式目录（TableStyleLight1~21、Medium1~28、Dark1~11）
+var builtinTableStyles = buildBuiltinTableStyles()
+
+// lookupTableStyle 按名称查找内置表格样式（不区分大小写）
+func lookupTableStyle(name string) (*tableStyleDef, bool) {
+	def, ok := builtinTableStyles[strings.ToLower(name)]
+	return def, ok
+}
+
+// buildBuiltinTableStyles 生成内置表格样式目录
+// 每个系列 7 个样式：第 1 个使用 dk1，其余依次使用 accent1~accent6
+func buildBuiltinTableStyles() map[string]*tableStyleDef {
+	styles := make(map[string]*tableStyleDef)
+	for i, c := range themeTableColors {
+		styles[fmt.Sprintf("tablestylelight%d", i+1)] = lightBandedStyle(c)
+		styles[fmt.Sprintf("tablestylelight%d", i+8)] = lightHeaderStyle(c)
+		styles[fmt.Sprintf("tablestylelight%d", i+15)] = lightGridStyle(c)
+		styles[fmt.Sprintf("tablestylemedium%d", i+1)] = mediumBandedStyle(c)
+		styles[fmt.Sprintf("tablestylemedium%d", i+8)] = mediumFilledStyle(c)
+		styles[fmt.Sprintf("tablestylemedium%d", i+15)] = mediumGridStyle(c)
+		styles[fmt.Sprintf("tablestylemedium%d", i+22)] = mediumLightStyle(c)
+		styles[fmt.Sprintf("tablestyledark%d", i+1)] = darkStyle(c)
+	}
+	// Dark8~11 使用成对的颜色：主体色与标题色
+	pairs := [][2]string{
+		{"000000", "000000"},
+		{themeTableColors[1], themeTableColors[2]},
+		{themeTableColors[3], themeTableColors[4]},
+		{themeTableColors[5], themeTableColors[6]},
+	}
+	for i, p := range pairs {
+		styles[fmt.Sprintf("tablestyledark%d", i+8)] = darkPairStyle(p[0], p[1])
+	}
+	return styles
+}
+
+// lightBandedStyle Light1~7：上下边框、浅色条纹、无标题填充
+func lightBandedStyle(c string) *tableStyleDef {
+	band := &tableStyleElement{Fill: tintHex(c, 0.8)}
+	return &tableStyleDef{
+		WholeTable:        &tableStyleElement{FontColor: tintHex(c, -0.25), Border: tableStyleBorder{Top: c, Bottom: c}},
+		FirstColumnStripe: band,
+		FirstRowStripe:    band,
+		LastColumn:        &tableStyleElement{Bold: true},
+		FirstColumn:       &tableStyleElement{Bold: true},
+		HeaderRow:         &tableStyleElement{Bold: true, Border: tableStyleBorder{Bottom: c}},
+		TotalRow:          &tableStyleElement{Bold: true, Border: tableStyleBorder{Top: c}},
+	}
+}
+
+// lightHeaderStyle Light8~14：实色标题行、外框、条纹以边框区分
+func lightHeaderStyle(c string) *tableStyleDef {
+	return &tableStyleDef{
+		WholeTable:        &tableStyleElement{Border: tableStyleBorder{Top: c, Bottom: c, Left: c, Right: c}},
+		FirstColumnStripe: &tableStyleElement{Border: tableStyleBorder{Left: c, Right: c}},
+		FirstRowStripe:    &tableStyleElement{Border: tableStyleBorder{Top: c, Bottom: c}},
+		LastColumn:        &tableStyleElement{Bold: true},
+		FirstColumn:       &tableStyleElement{Bold: true},
+		HeaderRow:         &tableStyleElement{Fill: c, FontColor: "FFFFFF", Bold: true},
+		TotalRow:          &tableStyleElement{Bold: true, Border: tableStyleBorder{Top: c}},
+	}
+}
+
+// lightGridStyle Light15~21：全网格边框、浅色条纹
+func lightGridStyle(c string) *tableStyleDef {
+	band := &tableStyleElement{Fill: tintHex(c, 0.8)}
+	return &tableStyleDef{
+		WholeTable:        &tableStyleElement{Border: tableStyleBorder{Top: c, Bottom: c, Left: c, Right: c, InsideH: c, InsideV: c}},
+		FirstColumnStripe: band,
+		FirstRowStripe:    band,
+		LastColumn:        &tableStyleElement{Bold: true},
+		FirstColumn:       &tableStyleElement{Bold: true},
+		HeaderRow:         &tableStyleElement{Bold: true, Border: tableStyleBorder{Bottom: c}},
+		TotalRow:          &tableStyleElement{Bold: true, Border: tableStyleBorder{Top: c}},
+	}
+}
+
+// mediumBandedStyle Medium1~7：实色标题行、浅色横线与条纹（TableStyleMedium2 即默认蓝色表格）
+func mediumBandedStyle(c string) *tableStyleDef {
+	line := tintHex(c, 0.4)
+	band := &tableStyleElement{Fill: tintHex(c, 0.8)}
+	return &tableStyleDef{
+		WholeTable:        &tableStyleElement{Border: tableStyleBorder{Top: line, Bottom: line, Left: line, Right: line, InsideH: line}},
+		FirstColumnStripe: band,
+		FirstRowStripe:    band,
+		LastColumn:        &tableStyleElement{Bold: true},
+		FirstColumn:       &tableStyleElement{Bold: true},
+		HeaderRow:         &tableStyleElement{Fill: c, FontColor: "FFFFFF", Bold: true},
+		TotalRow:          &tableStyleElement{Bold: true, Border: tableStyleBorder{Top: c}},
+	}
+}
+
+// mediumFilledStyle Medium8~14：整表浅色填充、白色分隔线、实色标题与首末列
+func mediumFilledStyle(c string) *tableStyleDef {
+	band := &tableStyleElement{Fill: tintHex(c, 0.6)}
+	solid := &tableStyleElement{Fill: c, FontColor: "FFFFFF", Bold: true}
+	return &tableStyleDef{
+		WholeTable:        &tableStyleElement{Fill: tintHex(c, 0.8), Border: tableStyleBorder{InsideH: "FFFFFF", InsideV: "FFFFFF"}},
+		FirstColumnStripe: band,
+		FirstRowStripe:    band,
+		LastColumn:        solid,
+		FirstColumn:       solid,
+		HeaderRow:         &tableStyleElement{Fill: c, FontColor: "FFFFFF", Bold: true, Border: tableStyleBorder{Bottom: "FFFFFF"}},
+		TotalRow:          &tableStyleElement{Fill: c, FontColor: "FFFFFF", Bold: true, Border: tableStyleBorder{Top: "FFFFFF"}},
+	}
+}
+
+// mediumGridStyle Medium15~21：黑色网格、实色标题与首末列、灰色条纹
+func mediumGridStyle(c string) *tableStyleDef {
+	band := &tableStyleElement{Fill: "D9D9D9"}
+	solid := &tableStyleElement{Fill: c, FontColor: "FFFFFF", Bold: true}
+	dk := themeTableColors[0]
+	return &tableStyleDef{
+		WholeTable:        &tableStyleElement{Border: tableStyleBorder{Top: dk, Bottom: dk, Left: dk, Right: dk, InsideH: dk, InsideV: dk}},
+		FirstColumnStripe: band,
+		FirstRowStripe:    band,
+		LastColumn:        solid,
+		FirstColumn:       solid,
+		HeaderRow:         &tableStyleElement{Fill: c, FontColor: "FFFFFF", Bold: true},
+		TotalRow:          &tableStyleElement{Bold: true, Border: tableStyleBorder{Top: dk}},
+	}
+}
+
+// mediumLightStyle Medium22~28：整表浅色填充与网格、较深条纹、标题仅加粗
+func mediumLightStyle(c string) *tableStyleDef {
+	line := tintHex(c, 0.4)
+	band := &tableStyleElement{Fill: tintHex(c, 0.6)}
+	return &tableStyleDef{
+		WholeTable:        &tableStyleElement{Fill: tintHex(c, 0.8), Border: tableStyleBorder{Top: line, Bottom: line, Left: line, Right: line, InsideH: line, InsideV: line}},
+		FirstColumnStripe: band,
+		FirstRowStripe:    band,
+		LastColumn:        &tableStyleElement{Bold: true},
+		FirstColumn:       &tableStyleElement{Bold: true},
+		HeaderRow:         &tableStyleElement{Bold: true},
+		TotalRow:          &tableStyleElement{Bold: true, Border: tableStyleBorder{Top: c}},
+	}
+}
+
+// darkStyle Dark1~7：深色填充、白色文字、黑色标题行
+func darkStyle(c string) *tableStyleDef {
+	if c == themeTableColors[0] {
+		// dk1 系列以灰色为底色，否则加深后与黑色无法区分
+		c = "737373"
+	}
+	dark := tintHex(c, -0.5)
+	return &tableStyleDef{
+		WholeTable:        &tableStyleElement{Fill: dark, FontColor: "FFFFFF"},
+		FirstColumnStripe: &tableStyleElement{Fill: tintHex(c, -0.25)},
+		FirstRowStripe:    &tableStyleElement{Fill: tintHex(c, -0.25)},
+		LastColumn:        &tableStyleElement{Fill: tintHex(c, -0.75), Bold: true, Border: tableStyleBorder{Left: "FFFFFF"}},
+		FirstColumn:       &tableStyleElement{Fill: tintHex(c, -0.75), Bold: true, Border: tableStyleBorder{Right: "FFFFFF"}},
+		HeaderRow:         &tableStyleElement{Fill: "000000", FontColor: "FFFFFF", Bold: true, Border: tableStyleBorder{Bottom: "FFFFFF"}},
+		TotalRow:          &tableStyleElement{Fill: tintHex(c, -0.75), FontColor: "FFFFFF", Bold: true, Border: tableStyleBorder{Top: "FFFFFF"}},
+	}
+}
+
+// darkPairStyle Dark8~11：主体色浅色填充、标题色实色标题行
+func darkPairStyle(body, header string) *tableStyleDef {
+	band := &tableStyleElement{Fill: tintHex(body, 0.4)}
+	return &tableStyleDef{
+		WholeTable:        &tableStyleElement{Fill: tintHex(body, 0.6)},
+		FirstColumnStripe: band,
+		FirstRowStripe:    band,
+		LastColumn:        &tableStyleElement{Bold: true},
+		FirstColumn:       &tableStyleElement{Bold: true},
+		HeaderRow:         &tableStyleElement{Fill: header, FontColor: "FFFFFF", Bold: true},
+		TotalRow:          &tableStyleElement{Bold: true, Border: tableStyleBorder{Top: themeTableColors[0]}},
+	}
+}
@@ -0,0 +1,243 @@
+package excelsnapshot
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap/zaptest"
+)
+
+// createTestExcelWithTable 创建包含表格（TableStyleMedium2）的测试Excel文件
+func createTestExcelWithTable(filename string) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	f.SetSheetRow("Sheet1", "A1", &[]interface{}{"名称", "数量"})
+	f.SetSheetRow("Sheet1", "A2", &[]interface{}{"苹果", 10})
+	f.SetSheetRow("Sheet1", "A3", &[]interface{}{"香蕉", 20})
+	f.SetSheetRow("Sheet1", "A4", &[]interface{}{"橙子", 30})
+
+	// 对 B4 设置显式填充，验证显式样式优先
+	styleID, err := f.NewStyle(&excelize.Style{
+		Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"FF0000"}},
+	})
+	if err != nil {
+		return err
+	}
+	f.SetCellStyle("Sheet1", "B4", "B4", styleID)
+
+	if err := f.AddTable("Sheet1", &excelize.Table{
+		Range:     "A1:B4",
+		Name:      "Fruits",
+		StyleName: "TableStyleMedium2",
+	}); err != nil {
+		return err
+	}
+	return f.SaveAs(filename)
+}
+
+// TestSheet_loadTables 测试表格定义加载
+func TestSheet_loadTables(t *testing.T) {
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "table.xlsx")
+	if err := createTestExcelWithTable(testFile); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+
+	logger := zaptest.NewLogger(t)
+	excel, err := NewExcel(testFile, logger)
+	if err != nil {
+		t.Fatalf("加载Excel文件失败: %v", err)
+	}
+	defer excel.Close()
+
+	sheet, err := excel.GetSheet("Sheet1")
+	if err != nil {
+		t.Fatalf("获取工作表失败: %v", err)
+	}
+
+	tables := sheet.Tables()
+	if len(tables) != 1 {
+		t.Fatalf("表格数量 = %d, want 1", len(tables))
+	}
+	tbl := tables[0]
+	if tbl.Name != "Fruits" || tbl.StyleName != "TableStyleMedium2" {
+		t.Errorf("表格信息不正确: %+v", tbl)
+	}
+	if tbl.StartCol != 1 || tbl.StartRow != 1 || tbl.EndCol != 2 || tbl.EndRow != 4 {
+		t.Errorf("表格范围不正确: %+v", tbl)
+	}
+	if tbl.HeaderRows != 1 || tbl.TotalsRows != 0 {
+		t.Errorf("标题行/汇总行 = %d/%d, want 1/0", tbl.HeaderRows, tbl.TotalsRows)
+	}
+	if tbl.style == nil {
+		t.Error("内置表格样式应被识别")
+	}
+}
+
+// TestExcel_readTableParts_Fallback 测试内存中缺少的表格部件从原始文件中读取
+func TestExcel_readTableParts_Fallback(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "table.xlsx")
+	if err := createTestExcelWithTable(testFile); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		opts []ExcelOption
+	}{
+		{"完整加载", nil},
+		{"流式加载", []ExcelOption{WithStreaming(true), WithOpenOptions(excelize.Options{UnzipXMLSizeLimit: 1})}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			excel, err := NewExcel(testFile, zaptest.NewLogger(t), tt.opts...)
+			if err != nil {
+				t.Fatalf("加载Excel文件失败: %v", err)
+			}
+			defer excel.Close()
+
+			tables, err := excel.file.GetTables("Sheet1")
+			if err != nil || len(tables) != 1 {
+				t.Fatalf("GetTables = %v, %v", tables, err)
+			}
+			// 模拟表格部件不在内存中
+			excel.file.Pkg.Delete("xl/tables/table1.xml")
+
+			parts := excel.readTableParts(tables)
+			if _, ok := parts["Fruits"]; !ok {
+				t.Errorf("表格部件应从原始文件中读取: %+v", parts)
+			}
+			if len(tt.opts) == 0 {
+				return
+			}
+			sheet, err := excel.GetSheet("Sheet1")
+			if err != nil {
+				t.Fatalf("获取工作表失败: %v", err)
+			}
+			if n := len(sheet.Tables()); n != 1 {
+				t.Errorf("表格数量 = %d, want 1", n)
+			}
+		})
+	}
+}
+
+// TestCell_RenderStyleWithTable 测试表格样式与单元格显式样式的叠加
+func TestCell_RenderStyleWithTable(t *testing.T) {
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "table.xlsx")
+	if err := createTestExcelWithTable(testFile); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+
+	logger := zaptest.NewLogger(t)
+	excel, err := NewExcel(testFile, logger)
+	if err != nil {
+		t.Fatalf("加载Excel文件失败: %v", err)
+	}
+	defer excel.Close()
+
+	sheet, err := excel.GetSheet("Sheet1")
+	if err != nil {
+		t.Fatalf("获取工作表失败: %v", err)
+	}
+
+	fillOf := func(addr string) string {
+		st, err := sheet.cells.get(addr).RenderStyle()
+		if err != nil {
+			t.Fatalf("RenderStyle(%s) 失败: %v", addr, err)
+		}
+		if len(st.Fill.Color) == 0 {
+			return ""
+		}
+		return st.Fill.Color[0]
+	}
+
+	tests := []struct {
+		addr string
+		want string
+	}{
+		{"A1", "4472C4"},               // 标题行
+		{"A2", tintHex("4472C4", 0.8)}, // 第一条纹
+		{"A3", ""},                     // 第二条纹无填充
+		{"B4", "FF0000"},               // 显式填充优先
+		{"C1", ""},                     // 表格外
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			if sheet.cells.get(tt.addr) == nil {
+				if tt.want != "" {
+					t.Fatalf("单元格 %s 不存在", tt.addr)
+				}
+				return
+			}
+			if got := fillOf(tt.addr); got != tt.want {
+				t.Errorf("填充色 = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	header, _ := sheet.cells.get("A1").RenderStyle()
+	if header.Font == nil || !header.Font.Bold || header.Font.Color != "FFFFFF" {
+		t.Errorf("标题行字体应为白色加粗: %+v", header.Font)
+	}
+}
+
+// TestCell_RenderStyleCached 测试表格内单元格的渲染样式在加载时计算，重复调用返回同一结果
+func TestCell_RenderStyleCached(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "table.xlsx")
+	if err := createTestExcelWithTable(testFile); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		opts []ExcelOption
+	}{
+		{"完整加载", nil},
+		{"流式加载", []ExcelOption{WithStreaming(true)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			excel, err := NewExcel(testFile, zaptest.NewLogger(t), tt.opts...)
+			if err != nil {
+				t.Fatalf("加载Excel文件失败: %v", err)
+			}
+			defer excel.Close()
+			sheet, err := excel.GetSheet("Sheet1")
+			if err != nil {
+				t.Fatalf("获取工作表失败: %v", err)
+			}
+
+			for _, addr := range []string{"A1", "A2", "B4"} {
+				cell := sheet.cells.get(addr)
+				if cell == nil || cell.renderStyle == nil {
+					t.Fatalf("%s 应在加载时计算渲染样式: %+v", addr, cell)
+				}
+				first, _ := cell.RenderStyle()
+				second, _ := cell.RenderStyle()
+				if first != second {
+					t.Errorf("%s 重复调用 RenderStyle 应返回同一样式", addr)
+				}
+			}
+			if st, _ := sheet.cells.get("A2").RenderStyle(); len(st.Fill.Color) == 0 || st.Fill.Color[0] != tintHex("4472C4", 0.8) {
+				t.Errorf("A2 填充色 = %v, want %s", st.Fill.Color, tintHex("4472C4", 0.8))
+			}
+		})
+	}
+}
+
+// TestLookupTableStyle 测试内置表格样式目录
+func TestLookupTableStyle(t *testing.T) {
+	for _, name := range []string{"TableStyleLight1", "TableStyleLight21", "TableStyleMedium28", "TableStyleDark11", "tablestylemedium2"} {
+		if _, ok := lookupTableStyle(name); !ok {
+			t.Errorf("lookupTableStyle(%q) 未找到", name)
+		}
+	}
+	for _, name := range []string{"", "TableStyleLight22", "PivotStyleLight16"} {
+		if _, ok := lookupTableStyle(name); ok {
+			t.Errorf("lookupTableStyle(%q) 不应存在", name)
+		}
+	}
+}
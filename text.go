@@ -0,0 +1,332 @@
+package excelsnapshot
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/fogleman/gg"
+	"golang.org/x/image/font"
+)
+
+// Excel 以 96 DPI 换算磅与像素，列宽单位为默认字体的最大数字宽度
+const (
+	pixelsPerPoint = 96.0 / 72.0
+	// 列宽换算中的单元格内边距（左右各 2 像素与 1 像素网格线）
+	colWidthPadding = 5.0
+)
+
+// fontSet 字体族解析、字形后备链与字体 Face 缓存（渲染与文本度量共用）
+// fontSet 及其中的 Face 不是并发安全的，同一时刻只能由一个 goroutine 使用，并发场景通过 fontCache 取用
+type fontSet struct {
+	registry *FontRegistry
+	fallback []string
+	faces    map[string]font.Face
+}
+
+// newFontSet 创建字体集合，registry 为空时仅使用内置字体
+func newFontSet(registry *FontRegistry, fallback []string) *fontSet {
+	return &fontSet{
+		registry: registry,
+		fallback: fallback,
+		faces:    make(map[string]font.Face),
+	}
+}
+
+// fontCache 可并发使用的字体 Face 缓存
+// font.Face 不是并发安全的（度量与绘制会修改其内部缓冲），因此 Face 不在 goroutine 间共享：
+// 每次渲染或加载取出一个独占的 fontSet，用完后放回，之后的使用者复用其中已创建的 Face
+type fontCache struct {
+	registry *FontRegistry
+	fallback []string
+
+	mu   sync.Mutex
+	idle []*fontSet
+	// 渲染之外的公开方法（如 SheetRenderer.GetFontFace）共用的字体集合，由 mu 保护
+	shared *fontSet
+}
+
+// newFontCache 创建字体缓存，registry 为空时仅使用内置字体
+func newFontCache(registry *FontRegistry, fallback []string) *fontCache {
+	return &fontCache{registry: registry, fallback: fallback}
+}
+
+// get 取出一个空闲的字体集合，没有空闲时新建
+func (c *fontCache) get() *fontSet {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if n := len(c.idle); n > 0 {
+		fs := c.idle[n-1]
+		c.idle = c.idle[:n-1]
+		return fs
+	}
+	return newFontSet(c.registry, c.fallback)
+}
+
+// put 放回用完的字体集合
+func (c *fontCache) put(fs *fontSet) {
+	if fs == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.idle = append(c.idle, fs)
+}
+
+// withShared 在持有锁的情况下以共用的字体集合调用 fn
+func (c *fontCache) withShared(fn func(fs *fontSet)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.shared == nil {
+		c.shared = newFontSet(c.registry, c.fallback)
+	}
+	fn(c.shared)
+}
+
+// face 按字体族名获取指定字号（像素）的字体，注册表无匹配时使用内置字体
+func (fs *fontSet) face(family string, size float64, bold, italic bool) (font.Face, error) {
+	f, ok := fs.registry.Lookup(family, bold, italic)
+	if !ok {
+		// 内置字体不区分字体族与斜体
+		family, italic = "", false
+	}
+	key := fmt.Sprintf("%s|%f|%t|%t", normalizeFamily(family), size, bold, italic)
+	if face, ok := fs.faces[key]; ok {
+		return face, nil
+	}
+
+	var face font.Face
+	var err error
+	if f != nil {
+		face, err = newFontFace(f, size)
+	} else {
+		face, err = LoadDefaultFontWithSize(size, bold)
+	}
+	if err != nil {
+		return nil, err
+	}
+	fs.faces[key] = face
+	return face, nil
+}
+
+// chain 返回字形后备链：主字体、已注册的后备字体，最后是内置字体
+func (fs *fontSet) chain(primary font.Face, size float64, bold bool) []font.Face {
+	chain := []font.Face{primary}
+	add := func(face font.Face) {
+		for _, f := range chain {
+			if f == face {
+				return
+			}
+		}
+		chain = append(chain, face)
+	}
+	for _, family := range fs.fallback {
+		if _, ok := fs.registry.Lookup(family, bold, false); !ok {
+			continue
+		}
+		if face, err := fs.face(family, size, bold, false); err == nil {
+			add(face)
+		}
+	}
+	if face, err := fs.face("", size, bold, false); err == nil {
+		add(face)
+	}
+	return chain
+}
+
+// textFont 度量文本所用的字体属性（字号单位为磅）
+type textFont struct {
+	family string
+	size   float64
+	bold   bool
+	italic bool
+}
+
+// runs 按绘制时的字体与后备链拆分文本，字号按 96 DPI 换算为像素
+func (fs *fontSet) runs(text string, tf textFont) []textRun {
+	size := tf.size * pixelsPerPoint
+	face, err := fs.face(tf.family, size, tf.bold, tf.italic)
+	if err != nil {
+		return nil
+	}
+	return splitTextRuns(text, fs.chain(face, size, tf.bold))
+}
+
+// textWidth 度量单行文本宽度（96 DPI 像素）
+func (fs *fontSet) textWidth(text string, tf textFont) float64 {
+	return measureRuns(fs.runs(text, tf))
+}
+
+// lineHeight 返回字体行高（磅）
+func (fs *fontSet) lineHeight(tf textFont) float64 {
+	face, err := fs.face(tf.family, tf.size*pixelsPerPoint, tf.bold, tf.italic)
+	if err != nil {
+		return tf.size * pixelsPerPoint
+	}
+	m := face.Metrics()
+	height := math.Max(float64(m.Height), float64(m.Ascent+m.Descent)) / 64
+	return height / pixelsPerPoint
+}
+
+// maxDigitWidth 返回字体中数字 0-9 的最大宽度（96 DPI 像素，取整）
+func (fs *fontSet) maxDigitWidth(tf textFont) float64 {
+	widest := 0.0
+	for d := '0'; d <= '9'; d++ {
+		widest = math.Max(widest, fs.textWidth(string(d), tf))
+	}
+	if widest <= 0 {
+		return 7 // Calibri 11 的最大数字宽度
+	}
+	return math.Round(widest)
+}
+
+// wrapLineCount 计算文本在给定宽度（像素）内自动换行后的行数
+// 在空白处及 CJK 字符之间断行，超宽的单词按字符断开
+func (fs *fontSet) wrapLineCount(text string, tf textFont, maxWidth float64) int {
+	total := 0
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		lines, width := 1, 0.0
+		for _, token := range wrapTokens(line) {
+			w := fs.textWidth(token, tf)
+			if width > 0 && width+w > maxWidth {
+				lines++
+				width = 0
+				token = strings.TrimLeft(token, " ")
+				w = fs.textWidth(token, tf)
+			}
+			if w > maxWidth {
+				// 单个单词超宽：按字符断开
+				for _, r := range token {
+					rw := fs.textWidth(string(r), tf)
+					if width > 0 && width+rw > maxWidth {
+						lines++
+						width = 0
+					}
+					width += rw
+				}
+				continue
+			}
+			width += w
+		}
+		total += lines
+	}
+	return total
+}
+
+// wrapTokens 将文本拆分为可断行的片段：空白归入后一个单词，CJK 字符各自成段
+func wrapTokens(text string) []string {
+	var tokens []string
+	start := 0
+	// 前一个字符（按 rune 而非字节判断，避免把多字节字符的后续字节误判为空白）
+	var prev rune
+	for i, r := range text {
+		switch {
+		case isWideRune(r):
+			if i > start {
+				tokens = append(tokens, text[start:i])
+			}
+			tokens = append(tokens, string(r))
+			start = i + len(string(r))
+		case unicode.IsSpace(r) && i > start && !unicode.IsSpace(prev):
+			tokens = append(tokens, text[start:i])
+			start = i
+		}
+		prev = r
+	}
+	if start < len(text) {
+		tokens = append(tokens, text[start:])
+	}
+	return tokens
+}
+
+// isWideRune 判断是否为可在任意位置断行的 CJK 字符
+func isWideRune(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) ||
+		(r >= 0x3000 && r <= 0x303F) || (r >= 0xFF00 && r <= 0xFFEF)
+}
+
+// pixelsToColWidth 将内容宽度（像素）换算为 Excel 列宽（字符数，含内边距，按 1/256 向上取整）
+func pixelsToColWidth(px, mdw float64) float64 {
+	return math.Ceil((px+colWidthPadding)/mdw*256) / 256
+}
+
+// colWidthToPixels 将 Excel 列宽换算为像素（ECMA-376 18.3.1.13）
+func colWidthToPixels(width, mdw float64) float64 {
+	return math.Trunc((256*width + math.Trunc(128/mdw)) / 256 * mdw)
+}
+
+// colWidthFromPixels 将列的像素宽度换算为 Excel 列宽（colWidthToPixels 的逆运算，按 1/256 向上取整）
+func colWidthFromPixels(px, mdw float64) float64 {
+	return math.Ceil(px/mdw*256-math.Trunc(128/mdw)) / 256
+}
+
+// textRun 使用同一字体绘制的一段连续文本
+type textRun struct {
+	text string
+	face font.Face
+}
+
+// splitTextRuns 按字形覆盖将文本拆分为若干段：每个字符使用字体链中第一个包含该字形的字体
+// 空白与控制字符沿用当前段的字体，所有字体都不包含的字符使用首个字体
+func splitTextRuns(text string, faces []font.Face) []textRun {
+	if len(faces) == 0 || text == "" {
+		return nil
+	}
+	var runs []textRun
+	var cur font.Face
+	start := 0
+	for i, r := range text {
+		face := cur
+		if face == nil || !(unicode.IsSpace(r) || unicode.IsControl(r)) {
+			face = pickFace(r, faces)
+		}
+		if cur != nil && face != cur {
+			runs = append(runs, textRun{text: text[start:i], face: cur})
+			start = i
+		}
+		cur = face
+	}
+	return append(runs, textRun{text: text[start:], face: cur})
+}
+
+// pickFace 返回字体链中第一个包含该字形的字体
+func pickFace(r rune, faces []font.Face) font.Face {
+	for _, f := range faces {
+		if _, ok := f.GlyphAdvance(r); ok {
+			return f
+		}
+	}
+	return faces[0]
+}
+
+// measureRuns 计算多段文本的总宽度（设备像素）
+func measureRuns(runs []textRun) float64 {
+	total := 0.0
+	for _, run := range runs {
+		total += float64(font.MeasureString(run.face, run.text)) / 64
+	}
+	return total
+}
+
+// drawRunsAnchored 以锚点方式绘制多段文本，语义与 gg.DrawStringAnchored 一致
+// 垂直方向以首个字体的行高为准，保证不同字体的基线对齐
+func drawRunsAnchored(canvas *gg.Context, runs []textRun, x, y, ax, ay float64) {
+	if len(runs) == 0 {
+		return
+	}
+	if len(runs) == 1 {
+		canvas.SetFontFace(runs[0].face)
+		canvas.DrawStringAnchored(runs[0].text, x, y, ax, ay)
+		return
+	}
+	height := float64(runs[0].face.Metrics().Height) / 64
+	x -= ax * measureRuns(runs)
+	y += ay * height
+	for _, run := range runs {
+		canvas.SetFontFace(run.face)
+		canvas.DrawString(run.text, x, y)
+		x += float64(font.MeasureString(run.face, run.text)) / 64
+	}
+}
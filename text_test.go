@@ -0,0 +1,201 @@
+package excelsnapshot
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap/zaptest"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// coverageFace 仅包含部分字形的测试字体
+type coverageFace struct {
+	font.Face
+	covers func(r rune) bool
+}
+
+func (f *coverageFace) GlyphAdvance(r rune) (fixed.Int26_6, bool) {
+	if !f.covers(r) {
+		return 0, false
+	}
+	return f.Face.GlyphAdvance(r)
+}
+
+// TestSplitTextRuns 测试按字形覆盖拆分文本
+func TestSplitTextRuns(t *testing.T) {
+	sr := NewSheetRenderer(zaptest.NewLogger(t))
+	base, err := sr.GetFont(12, false)
+	if err != nil {
+		t.Fatalf("GetFont() 失败: %v", err)
+	}
+	latin := &coverageFace{Face: base, covers: func(r rune) bool { return r < 0x80 }}
+	greek := &coverageFace{Face: base, covers: func(r rune) bool { return r >= 0x370 && r <= 0x3FF }}
+	faces := []font.Face{latin, greek}
+
+	tests := []struct {
+		name  string
+		text  string
+		texts []string
+		faces []font.Face
+	}{
+		{"单一字体", "abc 123", []string{"abc 123"}, []font.Face{latin}},
+		{"混排", "ab αβ cd", []string{"ab ", "αβ ", "cd"}, []font.Face{latin, greek, latin}},
+		{"前导后备字形", "αb", []string{"α", "b"}, []font.Face{greek, latin}},
+		{"均不支持时使用主字体", "a☃b", []string{"a☃b"}, []font.Face{latin}},
+		{"空文本", "", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runs := splitTextRuns(tt.text, faces)
+			if len(runs) != len(tt.texts) {
+				t.Fatalf("分段数量 = %d, want %d (%+v)", len(runs), len(tt.texts), runs)
+			}
+			for i, run := range runs {
+				if run.text != tt.texts[i] || run.face != tt.faces[i] {
+					t.Errorf("第 %d 段 = %q, want %q（或字体不匹配）", i, run.text, tt.texts[i])
+				}
+			}
+		})
+	}
+}
+
+// TestSheetRenderer_fontChain 测试后备字体链的顺序与去重
+func TestSheetRenderer_fontChain(t *testing.T) {
+	reg := newTestFontRegistry(t)
+	sr := NewSheetRenderer(zaptest.NewLogger(t), WithFontRegistry(reg), WithFallbackFonts("Go Mono", "Missing", "Go Mono"))
+
+	primary, err := sr.GetFontFace("Go", 24, false, false)
+	if err != nil {
+		t.Fatalf("GetFontFace() 失败: %v", err)
+	}
+	chain := sr.fontChain(primary, 24, false)
+	if len(chain) != 3 {
+		t.Fatalf("字体链长度 = %d, want 3（主字体、Go Mono、内置字体）", len(chain))
+	}
+	if chain[0] != primary {
+		t.Error("字体链应以主字体开头")
+	}
+	mono, _ := sr.GetFontFace("Go Mono", 24, false, false)
+	if chain[1] != mono {
+		t.Error("第二个字体应为后备字体 Go Mono")
+	}
+	embedded, _ := sr.GetFont(24, false)
+	if chain[2] != embedded {
+		t.Error("字体链应以内置字体结尾")
+	}
+}
+
+// TestColWidthConversion 测试 Excel 列宽与像素的换算
+func TestColWidthConversion(t *testing.T) {
+	// Calibri 11 的最大数字宽度为 7 像素：默认列宽（8.43 字符 + 内边距）为 64 像素
+	if got := colWidthToPixels(9.140625, 7); got != 64 {
+		t.Errorf("colWidthToPixels(9.140625, 7) = %v, want 64", got)
+	}
+	if got := pixelsToColWidth(59, 7); math.Abs(got-9.140625) > 1.0/256 {
+		t.Errorf("pixelsToColWidth(59, 7) = %v, want 9.140625", got)
+	}
+	for _, px := range []float64{10, 59, 123, 400} {
+		w := pixelsToColWidth(px, 7)
+		if back := colWidthToPixels(w, 7) - colWidthPadding; back < px {
+			t.Errorf("列宽 %v 换算回像素 %v 小于内容宽度 %v", w, back, px)
+		}
+	}
+}
+
+// TestFontSet_wrapLineCount 测试按字体度量的自动换行行数
+func TestFontSet_wrapLineCount(t *testing.T) {
+	fs := newFontSet(nil, nil)
+	tf := textFont{size: 11}
+	word := fs.textWidth("word", tf)
+	space := fs.textWidth(" ", tf)
+
+	tests := []struct {
+		name  string
+		text  string
+		width float64
+		want  int
+	}{
+		{"单行", "word word", 2*word + space + 1, 1},
+		{"按空白断行", "word word word", 2*word + space + 1, 2},
+		{"显式换行", "word\nword", 100 * word, 2},
+		{"超宽单词按字符断开", "wordwordword", word + 1, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fs.wrapLineCount(tt.text, tf, tt.width); got != tt.want {
+				t.Errorf("wrapLineCount(%q) = %d, want %d", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestWrapTokens 测试断行片段拆分
+func TestWrapTokens(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"ab cd  中文x", []string{"ab", " cd", "  ", "中", "文", "x"}},
+		// à 与 Ņ 的末字节为 0xA0、0x85，不应被当作空白
+		{"à b", []string{"à", " b"}},
+		{"Ņ b", []string{"Ņ", " b"}},
+		{"café au lait", []string{"café", " au", " lait"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got := wrapTokens(tt.text)
+			if len(got) != len(tt.want) {
+				t.Fatalf("wrapTokens() = %q, want %q", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("wrapTokens()[%d] = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// TestSheet_optimizeColumnWidths 测试按字体度量自动调整列宽
+func TestSheet_optimizeColumnWidths(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "autofit.xlsx")
+	f := excelize.NewFile()
+	f.SetCellValue("Sheet1", "A1", "short")
+	f.SetCellValue("Sheet1", "B1", "a considerably longer piece of text")
+	f.SetCellValue("Sheet1", "C1", "a considerably longer piece of text")
+	boldID, _ := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true, Size: 16}})
+	f.SetCellStyle("Sheet1", "C1", "C1", boldID)
+	if err := f.SaveAs(testFile); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+	f.Close()
+
+	excel, err := NewExcel(testFile, zaptest.NewLogger(t))
+	if err != nil {
+		t.Fatalf("加载Excel文件失败: %v", err)
+	}
+	defer excel.Close()
+	sheet, err := excel.GetSheet("Sheet1")
+	if err != nil {
+		t.Fatalf("获取工作表失败: %v", err)
+	}
+
+	if got := sheet.GetColWidth("A"); got != 9.140625 {
+		t.Errorf("短文本列宽 = %v, 应保持默认列宽", got)
+	}
+	b, c := sheet.GetColWidth("B"), sheet.GetColWidth("C")
+	if b <= 9.140625 || c <= b {
+		t.Errorf("列宽 B=%v C=%v，长文本应加宽且大字号加粗更宽", b, c)
+	}
+
+	// 换算回像素后应恰好容纳文本
+	tf := excel.defaultFont
+	px := excel.fonts.get().textWidth("a considerably longer piece of text", tf)
+	avail := colWidthToPixels(b, excel.MaxDigitWidth()) - colWidthPadding
+	if avail < px || avail > px+excel.MaxDigitWidth()+1 {
+		t.Errorf("列宽可用像素 = %v, 文本宽度 = %v", avail, px)
+	}
+}
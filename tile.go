@@ -0,0 +1,273 @@
+package excelsnapshot
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"math"
+	"sort"
+
+	"github.com/fogleman/gg"
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap"
+)
+
+// viewport 绘制区域（未缩放的 96 DPI 像素坐标）
+type viewport struct {
+	x, y, w, h float64
+}
+
+// gridLayout 渲染区域的行列边界（未缩放的 96 DPI 像素）
+// cols[i] 为第 startCol+i 列的左边界，最后一项为总宽；rows 同理
+type gridLayout struct {
+	startCol, startRow int
+	cols, rows         []float64
+}
+
+// newGridLayout 根据工作表的渲染区域与行高列宽计算网格
+func newGridLayout(sheet *Sheet) *gridLayout {
+	startCol, startRow := max(sheet.startCol, 1), max(sheet.startRow, 1)
+	g := &gridLayout{
+		startCol: startCol,
+		startRow: startRow,
+		cols:     make([]float64, max(sheet.Cols-startCol+1, 0)+1),
+		rows:     make([]float64, max(sheet.Rows-startRow+1, 0)+1),
+	}
+	for i := 1; i < len(g.cols); i++ {
+		colName, _ := excelize.ColumnNumberToName(startCol + i - 1)
+		g.cols[i] = g.cols[i-1] + sheet.ColWidthPixels(colName)
+	}
+	for i := 1; i < len(g.rows); i++ {
+		g.rows[i] = g.rows[i-1] + sheet.RowHeightPixels(startRow+i-1)
+	}
+	return g
+}
+
+// width 返回网格总宽
+func (g *gridLayout) width() float64 { return g.cols[len(g.cols)-1] }
+
+// height 返回网格总高
+func (g *gridLayout) height() float64 { return g.rows[len(g.rows)-1] }
+
+// colX 返回列的左边界（区域外的列按区域边界截断）
+func (g *gridLayout) colX(col int) float64 {
+	return g.cols[min(max(col-g.startCol, 0), len(g.cols)-1)]
+}
+
+// rowY 返回行的上边界（区域外的行按区域边界截断）
+func (g *gridLayout) rowY(row int) float64 {
+	return g.rows[min(max(row-g.startRow, 0), len(g.rows)-1)]
+}
+
+// contains 判断坐标是否位于渲染区域内
+func (g *gridLayout) contains(row, col int) bool {
+	return row >= g.startRow && row < g.startRow+len(g.rows)-1 && col >= g.startCol && col < g.startCol+len(g.cols)-1
+}
+
+// visibleCols 返回与 [lo, hi) 相交的列号范围
+func (g *gridLayout) visibleCols(lo, hi float64) (int, int) {
+	first, last := visibleRange(g.cols, lo, hi)
+	return first + g.startCol - 1, last + g.startCol - 1
+}
+
+// visibleRows 返回与 [lo, hi) 相交的行号范围
+func (g *gridLayout) visibleRows(lo, hi float64) (int, int) {
+	first, last := visibleRange(g.rows, lo, hi)
+	return first + g.startRow - 1, last + g.startRow - 1
+}
+
+// visibleRange 根据累计偏移量计算与 [lo, hi) 相交的行/列号范围（1-based，含两端）
+// 无相交时返回 first > last
+func visibleRange(offsets []float64, lo, hi float64) (int, int) {
+	n := len(offsets) - 1
+	first := sort.Search(n, func(i int) bool { return offsets[i+1] > lo }) + 1
+	last := sort.Search(n, func(i int) bool { return offsets[i] >= hi })
+	return first, last
+}
+
+// canvasIntersects 判断矩形经画布变换后是否与画布相交
+func canvasIntersects(canvas *gg.Context, x, y, w, h float64) bool {
+	x0, y0 := canvas.TransformPoint(x, y)
+	x1, y1 := canvas.TransformPoint(x+w, y+h)
+	return x1 > 0 && y1 > 0 && x0 < float64(canvas.Width()) && y0 < float64(canvas.Height())
+}
+
+// Tile 单个图块的位置（设备像素）
+type Tile struct {
+	Level  int `json:"level"`
+	Col    int `json:"col"`
+	Row    int `json:"row"`
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// TileLevel 一个缩放级别的图块网格
+type TileLevel struct {
+	Level   int     `json:"level"`
+	Scale   float64 `json:"scale"`
+	Width   int     `json:"width"`
+	Height  int     `json:"height"`
+	Columns int     `json:"columns"`
+	Rows    int     `json:"rows"`
+}
+
+// TileManifest 图块索引：整图尺寸、图块大小与各级别的网格信息
+type TileManifest struct {
+	Sheet    string      `json:"sheet"`
+	TileSize int         `json:"tile_size"`
+	Format   string      `json:"format"`
+	Width    int         `json:"width"`
+	Height   int         `json:"height"`
+	Levels   []TileLevel `json:"levels"`
+}
+
+// TileFunc 接收渲染完成的图块；返回错误时中止渲染
+// 图片仅在回调期间有效，调用方需在回调中完成编码或保存
+type TileFunc func(tile Tile, img image.Image) error
+
+// RenderTiles 按渲染器的缩放比例将工作表渲染为固定大小的图块（最右/最下一列图块可能较小）
+// 每个图块只绘制与其相交的单元格，内存占用与图块大小相关而与工作表大小无关；设置了 WithRange 时只渲染该区域
+// opts 只作用于本次渲染，语义与 RenderSheet 相同
+func (sr *SheetRenderer) RenderTiles(sheet *Sheet, tileSize int, fn TileFunc, opts ...RendererOption) (*TileManifest, error) {
+	return sr.RenderTilesContext(context.Background(), sheet, tileSize, fn, opts...)
+}
+
+// RenderTilesContext 同 RenderTiles，ctx 取消或超出 WithRenderLimits 设置的资源限制时中止并返回错误
+func (sr *SheetRenderer) RenderTilesContext(ctx context.Context, sheet *Sheet, tileSize int, fn TileFunc, opts ...RendererOption) (*TileManifest, error) {
+	return sr.renderTiles(ctx, sheet, tileSize, false, fn, opts)
+}
+
+// RenderTilePyramid 按 Deep Zoom 约定渲染所有缩放级别的图块
+// 最高级别为渲染器缩放比例下的整图，每降低一级宽高减半，直到 1x1 像素
+// 每个级别在栅格化之前按级别的缩放比例绘制，而非缩放高级别的图块
+func (sr *SheetRenderer) RenderTilePyramid(sheet *Sheet, tileSize int, fn TileFunc, opts ...RendererOption) (*TileManifest, error) {
+	return sr.RenderTilePyramidContext(context.Background(), sheet, tileSize, fn, opts...)
+}
+
+// RenderTilePyramidContext 同 RenderTilePyramid，ctx 取消或超出 WithRenderLimits 设置的资源限制时中止并返回错误
+func (sr *SheetRenderer) RenderTilePyramidContext(ctx context.Context, sheet *Sheet, tileSize int, fn TileFunc, opts ...RendererOption) (*TileManifest, error) {
+	return sr.renderTiles(ctx, sheet, tileSize, true, fn, opts)
+}
+
+// renderTiles 渲染图块；pyramid 为 true 时渲染 Deep Zoom 的全部级别，opts 为本次渲染的选项
+func (sr *SheetRenderer) renderTiles(ctx context.Context, sheet *Sheet, tileSize int, pyramid bool, fn TileFunc, opts []RendererOption) (*TileManifest, error) {
+	if sheet == nil {
+		return nil, fmt.Errorf("工作表为空")
+	}
+	if tileSize <= 0 {
+		return nil, fmt.Errorf("图块大小必须为正数: %d", tileSize)
+	}
+
+	r := sr.session(opts...)
+	defer r.endSession()
+	ctx, cancel := r.limits.withTimeout(ctx, sheet.Name)
+	defer cancel()
+
+	grid := newGridLayout(sheet)
+	area, err := r.renderArea(grid)
+	if err != nil {
+		return nil, err
+	}
+	scale := r.fitScale(area.w, area.h)
+	width, height := canvasSize(area.w, area.h, scale)
+	// 图块的内存占用与图块大小相关，但渲染耗时与整图大小相关，因此按整图尺寸检查像素数
+	if err := r.limits.checkPixels(sheet.Name, width, height); err != nil {
+		return nil, err
+	}
+	manifest := &TileManifest{
+		Sheet:    sheet.Name,
+		TileSize: tileSize,
+		Format:   "png",
+		Width:    width,
+		Height:   height,
+	}
+
+	maxLevel := 0
+	if pyramid {
+		maxLevel = int(math.Ceil(math.Log2(float64(max(width, height)))))
+	}
+
+	for level := maxLevel; level >= 0; level-- {
+		// Deep Zoom 级别尺寸：逐级减半并向上取整
+		factor := math.Pow(2, float64(maxLevel-level))
+		lv := TileLevel{
+			Level:  level,
+			Scale:  scale / factor,
+			Width:  int(math.Ceil(float64(width) / factor)),
+			Height: int(math.Ceil(float64(height) / factor)),
+		}
+		if !pyramid {
+			lv.Level = 0
+		}
+		lv.Columns = (lv.Width + tileSize - 1) / tileSize
+		lv.Rows = (lv.Height + tileSize - 1) / tileSize
+		manifest.Levels = append(manifest.Levels, lv)
+
+		levelRenderer := *r
+		levelRenderer.scale = lv.Scale
+		for row := 0; row < lv.Rows; row++ {
+			for col := 0; col < lv.Columns; col++ {
+				tile := Tile{
+					Level:  lv.Level,
+					Col:    col,
+					Row:    row,
+					X:      col * tileSize,
+					Y:      row * tileSize,
+					Width:  min(tileSize, lv.Width-col*tileSize),
+					Height: min(tileSize, lv.Height-row*tileSize),
+				}
+				img, err := levelRenderer.renderTile(ctx, sheet, grid, area, tile)
+				if err != nil {
+					return manifest, err
+				}
+				if err := fn(tile, img); err != nil {
+					return manifest, err
+				}
+			}
+		}
+	}
+	sr.logger.Debug("图块渲染完成", zap.String("sheet", sheet.Name), zap.Int("levels", len(manifest.Levels)), zap.Int("tile_size", tileSize))
+	return manifest, nil
+}
+
+// renderTile 绘制单个图块，area 为整图对应的渲染区域
+func (sr *SheetRenderer) renderTile(ctx context.Context, sheet *Sheet, grid *gridLayout, area viewport, tile Tile) (image.Image, error) {
+	canvas := gg.NewContext(tile.Width, tile.Height)
+	view := viewport{
+		x: area.x + float64(tile.X)/sr.scale,
+		y: area.y + float64(tile.Y)/sr.scale,
+		w: float64(tile.Width) / sr.scale,
+		h: float64(tile.Height) / sr.scale,
+	}
+	canvas.Scale(sr.scale, sr.scale)
+	canvas.Translate(-view.x, -view.y)
+	if err := sr.drawSheet(ctx, canvas, sheet, grid, view); err != nil {
+		return nil, err
+	}
+	return canvas.Image(), nil
+}
+
+// DZI 生成 Deep Zoom 描述文件（.dzi）内容，图块按 {name}_files/{level}/{col}_{row}.{format} 存放
+func (m *TileManifest) DZI() ([]byte, error) {
+	doc := struct {
+		XMLName  xml.Name `xml:"http://schemas.microsoft.com/deepzoom/2008 Image"`
+		Format   string   `xml:"Format,attr"`
+		Overlap  int      `xml:"Overlap,attr"`
+		TileSize int      `xml:"TileSize,attr"`
+		Size     struct {
+			Width  int `xml:"Width,attr"`
+			Height int `xml:"Height,attr"`
+		} `xml:"Size"`
+	}{Format: m.Format, TileSize: m.TileSize}
+	doc.Size.Width = m.Width
+	doc.Size.Height = m.Height
+
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
@@ -0,0 +1,164 @@
+package excelsnapshot
+
+import (
+	"bytes"
+	"image"
+	"image/draw"
+	"path/filepath"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap/zaptest"
+)
+
+// createTestExcelForTiles 创建包含合并单元格、填充与长文本的测试Excel文件
+func createTestExcelForTiles(filename string) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	for r := 1; r <= 40; r++ {
+		for c := 1; c <= 8; c++ {
+			addr, _ := excelize.CoordinatesToCellName(c, r)
+			f.SetCellValue("Sheet1", addr, r*c)
+		}
+	}
+	f.SetCellValue("Sheet1", "B3", "跨越多个图块的合并单元格")
+	f.MergeCell("Sheet1", "B3", "E8")
+	fill, _ := f.NewStyle(&excelize.Style{
+		Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"FFD966"}},
+	})
+	f.SetCellStyle("Sheet1", "B3", "E8", fill)
+	return f.SaveAs(filename)
+}
+
+// TestSheetRenderer_RenderTiles 测试图块拼接结果与整图渲染一致
+func TestSheetRenderer_RenderTiles(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "tiles.xlsx")
+	if err := createTestExcelForTiles(testFile); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+
+	logger := zaptest.NewLogger(t)
+	excel, err := NewExcel(testFile, logger)
+	if err != nil {
+		t.Fatalf("加载Excel文件失败: %v", err)
+	}
+	defer excel.Close()
+	sheet, err := excel.GetSheet("Sheet1")
+	if err != nil {
+		t.Fatalf("获取工作表失败: %v", err)
+	}
+
+	renderer := NewSheetRenderer(logger)
+	full, err := renderer.RenderSheet(sheet)
+	if err != nil {
+		t.Fatalf("RenderSheet() 失败: %v", err)
+	}
+
+	const tileSize = 128
+	stitched := image.NewRGBA(full.Bounds())
+	count := 0
+	manifest, err := renderer.RenderTiles(sheet, tileSize, func(tile Tile, img image.Image) error {
+		if img.Bounds().Dx() != tile.Width || img.Bounds().Dy() != tile.Height || tile.Width > tileSize || tile.Height > tileSize {
+			t.Errorf("图块 %d_%d 尺寸 %v 与索引 %dx%d 不符", tile.Col, tile.Row, img.Bounds(), tile.Width, tile.Height)
+		}
+		draw.Draw(stitched, image.Rect(tile.X, tile.Y, tile.X+tile.Width, tile.Y+tile.Height), img, image.Point{}, draw.Src)
+		count++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("RenderTiles() 失败: %v", err)
+	}
+
+	if manifest.Width != full.Bounds().Dx() || manifest.Height != full.Bounds().Dy() {
+		t.Errorf("图块索引尺寸 %dx%d 与整图 %v 不符", manifest.Width, manifest.Height, full.Bounds())
+	}
+	if len(manifest.Levels) != 1 || manifest.Levels[0].Columns*manifest.Levels[0].Rows != count {
+		t.Fatalf("图块索引不正确: %+v，实际图块数 %d", manifest.Levels, count)
+	}
+
+	// 允许图块边界处极少量抗锯齿差异
+	diff, total := 0, 0
+	bounds := full.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			total++
+			r1, g1, b1, _ := full.At(x, y).RGBA()
+			r2, g2, b2, _ := stitched.At(x, y).RGBA()
+			if absDiff(r1, r2) > 0x2000 || absDiff(g1, g2) > 0x2000 || absDiff(b1, b2) > 0x2000 {
+				diff++
+			}
+		}
+	}
+	if float64(diff)/float64(total) > 0.005 {
+		t.Errorf("拼接图块与整图差异像素 %d/%d", diff, total)
+	}
+}
+
+// TestSheetRenderer_RenderTilePyramid 测试 Deep Zoom 级别与描述文件
+func TestSheetRenderer_RenderTilePyramid(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "pyramid.xlsx")
+	if err := createTestExcelForTiles(testFile); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+
+	logger := zaptest.NewLogger(t)
+	excel, err := NewExcel(testFile, logger)
+	if err != nil {
+		t.Fatalf("加载Excel文件失败: %v", err)
+	}
+	defer excel.Close()
+	sheet, err := excel.GetSheet("Sheet1")
+	if err != nil {
+		t.Fatalf("获取工作表失败: %v", err)
+	}
+
+	levels := make(map[int]int)
+	manifest, err := NewSheetRenderer(logger, WithScale(1)).RenderTilePyramid(sheet, 256, func(tile Tile, img image.Image) error {
+		levels[tile.Level]++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("RenderTilePyramid() 失败: %v", err)
+	}
+
+	top := manifest.Levels[0]
+	last := manifest.Levels[len(manifest.Levels)-1]
+	if top.Width != manifest.Width || top.Height != manifest.Height {
+		t.Errorf("最高级别尺寸 %dx%d 应等于整图 %dx%d", top.Width, top.Height, manifest.Width, manifest.Height)
+	}
+	if last.Level != 0 || last.Width != 1 || last.Height != 1 {
+		t.Errorf("最低级别应为 1x1 的第 0 级: %+v", last)
+	}
+	for _, lv := range manifest.Levels {
+		if levels[lv.Level] != lv.Columns*lv.Rows {
+			t.Errorf("级别 %d 图块数 = %d, want %d", lv.Level, levels[lv.Level], lv.Columns*lv.Rows)
+		}
+	}
+
+	dzi, err := manifest.DZI()
+	if err != nil {
+		t.Fatalf("DZI() 失败: %v", err)
+	}
+	if !bytes.Contains(dzi, []byte(`TileSize="256"`)) || !bytes.Contains(dzi, []byte(`Format="png"`)) {
+		t.Errorf("DZI 内容不正确: %s", dzi)
+	}
+}
+
+// TestSheetRenderer_RenderTilesInvalid 测试非法参数
+func TestSheetRenderer_RenderTilesInvalid(t *testing.T) {
+	renderer := NewSheetRenderer(zaptest.NewLogger(t))
+	if _, err := renderer.RenderTiles(nil, 256, func(Tile, image.Image) error { return nil }); err == nil {
+		t.Error("工作表为空时应返回错误")
+	}
+	if _, err := renderer.RenderTiles(&Sheet{}, 0, func(Tile, image.Image) error { return nil }); err == nil {
+		t.Error("图块大小非正数时应返回错误")
+	}
+}
+
+func absDiff(a, b uint32) uint32 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
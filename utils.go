@@ -4,9 +4,11 @@ import (
 	_ "embed"
 	"fmt"
 	"image/color"
+	"math"
 	"strconv"
 	"strings"
 
+	"github.com/xuri/excelize/v2"
 	"golang.org/x/image/font"
 	"golang.org/x/image/font/opentype"
 )
@@ -30,16 +32,16 @@ func LoadDefaultFontWithSize(size float64, bold bool) (font.Face, error) {
 		return nil, err
 	}
 
-	fontFace, err := opentype.NewFace(f, &opentype.FaceOptions{
-		Size:    size, // 字体大小也要跟着4倍缩放
-		DPI:     72,   // 4倍DPI，匹配4倍缩放
+	return newFontFace(f, size)
+}
+
+// newFontFace 按给定字号创建字体 Face
+func newFontFace(f *opentype.Font, size float64) (font.Face, error) {
+	return opentype.NewFace(f, &opentype.FaceOptions{
+		Size:    size, // 字号已由调用方按缩放比例放大
+		DPI:     72,
 		Hinting: font.HintingFull,
 	})
-	if err != nil {
-		return nil, err
-	}
-
-	return fontFace, nil
 }
 
 // HexToRGBA 将十六进制颜色转换为 color.RGBA
@@ -69,3 +71,65 @@ func HexToRGBA(hex string) (color.RGBA, error) {
 		A: 255, // 默认不透明
 	}, nil
 }
+
+// tintHex 按 Excel 的 tint 语义调整颜色：正值向白色提亮，负值向黑色加深
+func tintHex(hex string, tint float64) string {
+	c, err := HexToRGBA(hex)
+	if err != nil {
+		return hex
+	}
+	apply := func(v uint8) uint8 {
+		f := float64(v)
+		if tint < 0 {
+			f = f * (1 + tint)
+		} else {
+			f = f + (255-f)*tint
+		}
+		return uint8(math.Round(f))
+	}
+	return fmt.Sprintf("%02X%02X%02X", apply(c.R), apply(c.G), apply(c.B))
+}
+
+// parseRangeRef 解析区域引用（如 A1:C5、$A$1:$C$5、'Sheet1'!$A$1:$C$5 或单个单元格 B2）
+// 返回起止列号与行号（1-based）
+func parseRangeRef(ref string) (startCol, startRow, endCol, endRow int, err error) {
+	if i := strings.LastIndex(ref, "!"); i >= 0 {
+		ref = ref[i+1:]
+	}
+	ref = strings.ReplaceAll(strings.TrimSpace(ref), "$", "")
+	parts := strings.Split(ref, ":")
+	if len(parts) > 2 {
+		return 0, 0, 0, 0, fmt.Errorf("无效的区域引用: %s", ref)
+	}
+	if startCol, startRow, err = excelize.CellNameToCoordinates(parts[0]); err != nil {
+		return 0, 0, 0, 0, err
+	}
+	endCol, endRow = startCol, startRow
+	if len(parts) == 2 {
+		if endCol, endRow, err = excelize.CellNameToCoordinates(parts[1]); err != nil {
+			return 0, 0, 0, 0, err
+		}
+	}
+	if startCol > endCol {
+		startCol, endCol = endCol, startCol
+	}
+	if startRow > endRow {
+		startRow, endRow = endRow, startRow
+	}
+	return startCol, startRow, endCol, endRow, nil
+}
+
+// officeThemeColors Office 默认主题色，按 SpreadsheetML 主题索引排列（0/1、2/3 与 clrScheme 顺序互换）
+var officeThemeColors = []string{
+	"FFFFFF", "000000", "E7E6E6", "44546A",
+	"4472C4", "ED7D31", "A5A5A5", "FFC000", "5B9BD5", "70AD47",
+	"0563C1", "954F72",
+}
+
+// themeColorHex 返回主题色索引对应的 RRGGBB（越界时返回空串）
+func themeColorHex(index int) string {
+	if index < 0 || index >= len(officeThemeColors) {
+		return ""
+	}
+	return officeThemeColors[index]
+}
@@ -0,0 +1,760 @@
+package excelsnapshot
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"unicode/utf16"
+
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap"
+)
+
+// BIFF8 记录类型（[MS-XLS] 2.3）
+const (
+	biffFormula          = 0x0006
+	biffEOF              = 0x000A
+	biffDate1904         = 0x0022
+	biffFilePass         = 0x002F
+	biffFont             = 0x0031
+	biffContinue         = 0x003C
+	biffDefColWidth      = 0x0055
+	biffColInfo          = 0x007D
+	biffBoundSheet       = 0x0085
+	biffPalette          = 0x0092
+	biffStandardWidth    = 0x0099
+	biffMulRK            = 0x00BD
+	biffMulBlank         = 0x00BE
+	biffXF               = 0x00E0
+	biffMergeCells       = 0x00E5
+	biffSST              = 0x00FC
+	biffLabelSST         = 0x00FD
+	biffBlank            = 0x0201
+	biffNumber           = 0x0203
+	biffLabel            = 0x0204
+	biffBoolErr          = 0x0205
+	biffString           = 0x0207
+	biffRow              = 0x0208
+	biffDefaultRowHeight = 0x0225
+	biffRK               = 0x027E
+	biffFormat           = 0x041E
+	biffBOF              = 0x0809
+	biffSheetExt         = 0x0862
+)
+
+const (
+	// BOF 中的 BIFF8 版本号
+	biff8Version = 0x0600
+	// BOF 中的子流类型：工作表
+	biffWorksheet = 0x0010
+	// BOUNDSHEET 中的工作表类型：普通工作表
+	biffSheetWork = 0
+	// 自动颜色（字体使用系统前景色）
+	biffAutoColor = 0x7FFF
+	// 可由 PALETTE 记录修改的第一个颜色索引
+	biffPaletteMin = 8
+)
+
+// errBIFFCorrupt BIFF 记录结构损坏
+var errBIFFCorrupt = errors.New("xls 记录结构损坏")
+
+// biffErrors 错误单元格的错误码 → 显示文本
+var biffErrors = map[byte]string{
+	0x00: "#NULL!",
+	0x07: "#DIV/0!",
+	0x0F: "#VALUE!",
+	0x17: "#REF!",
+	0x1D: "#NAME?",
+	0x24: "#NUM!",
+	0x2A: "#N/A",
+}
+
+// biffHorizontalAlign XF 水平对齐 → excelize 水平对齐，0 为常规
+var biffHorizontalAlign = []string{"", "left", "center", "right", "fill", "justify", "centerContinuous", "distributed"}
+
+// biffVerticalAlign XF 垂直对齐 → excelize 垂直对齐，2（靠下）为默认值
+var biffVerticalAlign = []string{"top", "center", "", "justify", "distributed"}
+
+// NewExcelFromXLS 读取 Excel 97-2003 工作簿（.xls，BIFF8）并转换为工作簿
+// 支持单元格值、公式的缓存结果、合并单元格、列宽、行高、隐藏行列与工作表、标签颜色，
+// 以及字体、填充、边框、对齐、数字格式与自定义调色板；
+// 公式文本、图片、图表与条件格式不会被转换，BIFF5 及更早的版本与加密的文件不受支持
+func NewExcelFromXLS(r io.Reader, logger *zap.Logger, opts ...ExcelOption) (*Excel, error) {
+	return NewExcelFromXLSContext(context.Background(), r, logger, opts...)
+}
+
+// NewExcelFromXLSContext 同 NewExcelFromXLS，转换时按 WithLoadLimits 检查每个工作表的单元格数与耗时，ctx 取消时中止
+func NewExcelFromXLSContext(ctx context.Context, r io.Reader, logger *zap.Logger, opts ...ExcelOption) (*Excel, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("读取 xls 失败: %w", err)
+	}
+	excel := newExcel("", logger, opts)
+	excel.format = FormatXLS
+	f, err := excel.buildXLSWorkbook(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	return excel.init(f)
+}
+
+// biffRecord 一条记录及其后的 CONTINUE 记录的内容
+type biffRecord struct {
+	typ    uint16
+	offset int // 记录在流中的偏移
+	chunks [][]byte
+}
+
+// data 返回记录本身（不含 CONTINUE）的内容
+func (r *biffRecord) data() []byte {
+	return r.chunks[0]
+}
+
+// reader 返回跨 CONTINUE 读取的读取器
+func (r *biffRecord) reader() *biffReader {
+	return &biffReader{chunks: r.chunks}
+}
+
+// xlsBoundSheet 工作簿中的一个工作表（BOUNDSHEET 记录）
+type xlsBoundSheet struct {
+	name   string
+	offset int
+	hidden bool
+	typ    byte
+}
+
+// xlsFont FONT 记录
+type xlsFont struct {
+	name      string
+	size      float64
+	bold      bool
+	italic    bool
+	strike    bool
+	underline string
+	vertAlign string
+	color     uint16
+}
+
+// xlsReader 工作簿全局信息与已创建的样式
+type xlsReader struct {
+	file     *excelize.File
+	sst      []string
+	fonts    []xlsFont
+	formats  map[uint16]string
+	xfs      [][]byte
+	palette  []string
+	sheets   []xlsBoundSheet
+	date1904 bool
+	// 工作簿默认字体的最大数字宽度，用于列宽换算
+	mdw float64
+	// XF 索引 → excelize 样式 ID
+	styleIDs map[int]int
+}
+
+// buildXLSWorkbook 解析复合文档中的 Workbook 流并构建等价的 excelize 工作簿
+func (e *Excel) buildXLSWorkbook(ctx context.Context, data []byte) (*excelize.File, error) {
+	cfb, err := openCFB(data)
+	if err != nil {
+		return nil, fmt.Errorf("无效的 xls 文件: %w", err)
+	}
+	stream, err := cfb.stream("Workbook")
+	if err != nil {
+		if _, bookErr := cfb.stream("Book"); bookErr == nil {
+			return nil, fmt.Errorf("不支持 Excel 95 及更早版本（BIFF5）的 xls 文件")
+		}
+		return nil, fmt.Errorf("无效的 xls 文件: %w", err)
+	}
+	records, err := readBIFFRecords(stream)
+	if err != nil {
+		return nil, fmt.Errorf("无效的 xls 文件: %w", err)
+	}
+
+	x := &xlsReader{
+		formats:  make(map[uint16]string),
+		palette:  append([]string(nil), excelize.IndexedColorMapping[:64]...),
+		styleIDs: make(map[int]int),
+	}
+	if err := x.readGlobals(records); err != nil {
+		return nil, err
+	}
+	if len(x.sheets) == 0 {
+		return nil, fmt.Errorf("xls 文件中没有工作表")
+	}
+
+	// 以第一个字体作为工作簿默认字体，列宽按其最大数字宽度换算
+	f := excelize.NewFile()
+	x.file = f
+	defaultFont := textFont{family: "Calibri", size: 11}
+	if len(x.fonts) > 0 && x.fonts[0].name != "" {
+		defaultFont.family = x.fonts[0].name
+		f.SetDefaultFont(x.fonts[0].name)
+	}
+	x.mdw = newFontSet(e.fontRegistry, e.fallbackFonts).maxDigitWidth(defaultFont)
+	if x.date1904 {
+		date1904 := true
+		if err := f.SetWorkbookProps(&excelize.WorkbookPropsOptions{Date1904: &date1904}); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+
+	offsets := make(map[int]int, len(records))
+	for i, r := range records {
+		offsets[r.offset] = i
+	}
+	var sheets []*convertedSheet
+	for _, bs := range x.sheets {
+		if bs.typ != biffSheetWork {
+			continue
+		}
+		start, ok := offsets[bs.offset]
+		if !ok {
+			f.Close()
+			return nil, fmt.Errorf("无效的 xls 文件: 找不到工作表 %s", bs.name)
+		}
+		guard, cancel := e.limits.newGuard(ctx, bs.name)
+		sheet, err := x.readSheet(bs, records[start:], guard)
+		cancel()
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("转换工作表 %s 失败: %w", bs.name, err)
+		}
+		// 图表等子流的 BOF 类型也不是工作表
+		if sheet != nil {
+			sheets = append(sheets, sheet)
+		}
+	}
+	if len(sheets) == 0 {
+		f.Close()
+		return nil, fmt.Errorf("xls 文件中没有工作表")
+	}
+	f, err = e.writeConvertedWorkbook(f, sheets)
+	if err != nil {
+		return nil, err
+	}
+	e.logger.Debug("xls 加载完成", zap.Int("sheets", len(sheets)))
+	return f, nil
+}
+
+// readBIFFRecords 将流拆分为记录，CONTINUE 记录并入前一条记录
+func readBIFFRecords(stream []byte) ([]*biffRecord, error) {
+	var records []*biffRecord
+	for off := 0; off+4 <= len(stream); {
+		typ := binary.LittleEndian.Uint16(stream[off:])
+		size := int(binary.LittleEndian.Uint16(stream[off+2:]))
+		if off+4+size > len(stream) {
+			return nil, errBIFFCorrupt
+		}
+		data := stream[off+4 : off+4+size]
+		if typ == biffContinue && len(records) > 0 {
+			last := records[len(records)-1]
+			last.chunks = append(last.chunks, data)
+		} else {
+			records = append(records, &biffRecord{typ: typ, offset: off, chunks: [][]byte{data}})
+		}
+		off += 4 + size
+	}
+	if len(records) == 0 || records[0].typ != biffBOF {
+		return nil, errBIFFCorrupt
+	}
+	return records, nil
+}
+
+// readGlobals 读取工作簿全局子流：共享字符串、字体、数字格式、XF、调色板与工作表列表
+func (x *xlsReader) readGlobals(records []*biffRecord) error {
+	for _, rec := range records {
+		r := rec.reader()
+		switch rec.typ {
+		case biffBOF:
+			if vers := r.u16(); vers != biff8Version {
+				return fmt.Errorf("不支持的 xls 版本 0x%04X，仅支持 Excel 97-2003（BIFF8）", vers)
+			}
+		case biffFilePass:
+			return fmt.Errorf("不支持加密的 xls 文件")
+		case biffEOF:
+			return nil
+		case biffDate1904:
+			x.date1904 = r.u16() == 1
+		case biffSST:
+			r.skip(4)
+			n := int(r.u32())
+			x.sst = make([]string, 0, min(n, 1<<16))
+			for i := 0; i < n && r.err == nil; i++ {
+				x.sst = append(x.sst, r.str(2))
+			}
+		case biffFont:
+			x.fonts = append(x.fonts, readXLSFont(r))
+		case biffFormat:
+			id := r.u16()
+			x.formats[id] = r.str(2)
+		case biffXF:
+			x.xfs = append(x.xfs, rec.data())
+		case biffPalette:
+			n := int(r.u16())
+			for i := 0; i < n && biffPaletteMin+i < len(x.palette); i++ {
+				rgb := r.bytes(4)
+				if rgb == nil {
+					break
+				}
+				x.palette[biffPaletteMin+i] = fmt.Sprintf("%02X%02X%02X", rgb[0], rgb[1], rgb[2])
+			}
+		case biffBoundSheet:
+			bs := xlsBoundSheet{offset: int(r.u32())}
+			bs.hidden = r.u8()&0x03 != 0
+			bs.typ = r.u8()
+			bs.name = r.str(1)
+			x.sheets = append(x.sheets, bs)
+		}
+		if r.err != nil {
+			return fmt.Errorf("无效的 xls 文件: 记录 0x%04X: %w", rec.typ, r.err)
+		}
+	}
+	return errBIFFCorrupt
+}
+
+// readXLSFont 读取 FONT 记录
+func readXLSFont(r *biffReader) xlsFont {
+	font := xlsFont{size: float64(r.u16()) / 20}
+	grbit := r.u16()
+	font.italic = grbit&0x02 != 0
+	font.strike = grbit&0x08 != 0
+	font.color = r.u16()
+	font.bold = r.u16() >= 700
+	switch r.u16() {
+	case 1:
+		font.vertAlign = "superscript"
+	case 2:
+		font.vertAlign = "subscript"
+	}
+	switch r.u8() {
+	case 0x01, 0x21:
+		font.underline = "single"
+	case 0x02, 0x22:
+		font.underline = "double"
+	}
+	r.skip(3)
+	font.name = r.str(1)
+	return font
+}
+
+// readSheet 读取工作表子流中的单元格、行列尺寸与合并区域；子流不是工作表时返回 nil
+func (x *xlsReader) readSheet(bs xlsBoundSheet, records []*biffRecord, guard *loadGuard) (*convertedSheet, error) {
+	if err := guard.err(); err != nil {
+		return nil, err
+	}
+	sheet := newConvertedSheet(bs.name, guard)
+	sheet.hidden = bs.hidden
+	var defaultColChars float64
+	// 嵌入的图表等子流有各自的 BOF/EOF
+	depth := 0
+	// 等待 STRING 记录的公式单元格（字符串结果）
+	pendingRow, pendingCol, pendingXF := 0, 0, -1
+	for _, rec := range records {
+		r := rec.reader()
+		switch rec.typ {
+		case biffBOF:
+			depth++
+			if depth == 1 {
+				r.skip(2)
+				if r.u16() != biffWorksheet {
+					return nil, nil
+				}
+			}
+		case biffEOF:
+			depth--
+		}
+		if depth == 0 {
+			break
+		}
+		if depth > 1 {
+			continue
+		}
+
+		switch rec.typ {
+		case biffLabelSST:
+			row, col, xf := r.cell()
+			idx := int(r.u32())
+			if idx >= len(x.sst) {
+				return nil, errBIFFCorrupt
+			}
+			if err := x.setCell(sheet, row, col, xf, x.sst[idx]); err != nil {
+				return nil, err
+			}
+		case biffLabel:
+			row, col, xf := r.cell()
+			if err := x.setCell(sheet, row, col, xf, r.str(2)); err != nil {
+				return nil, err
+			}
+		case biffNumber:
+			row, col, xf := r.cell()
+			if err := x.setCell(sheet, row, col, xf, r.f64()); err != nil {
+				return nil, err
+			}
+		case biffRK:
+			row, col, xf := r.cell()
+			if err := x.setCell(sheet, row, col, xf, biffRKValue(r.u32())); err != nil {
+				return nil, err
+			}
+		case biffMulRK:
+			row, col := int(r.u16()), int(r.u16())
+			for n := (len(rec.data()) - 6) / 6; n > 0 && r.err == nil; n-- {
+				xf := int(r.u16())
+				if err := x.setCell(sheet, row, col, xf, biffRKValue(r.u32())); err != nil {
+					return nil, err
+				}
+				col++
+			}
+		case biffBoolErr:
+			row, col, xf := r.cell()
+			v, isErr := r.u8(), r.u8() != 0
+			var value interface{} = v != 0
+			if isErr {
+				value = biffErrors[v]
+			}
+			if err := x.setCell(sheet, row, col, xf, value); err != nil {
+				return nil, err
+			}
+		case biffFormula:
+			row, col, xf := r.cell()
+			result := r.bytes(8)
+			if r.err != nil {
+				break
+			}
+			// 只读取公式的缓存结果，公式本身（解析后的 token）不转换
+			var value interface{}
+			if binary.LittleEndian.Uint16(result[6:]) != 0xFFFF {
+				value = math.Float64frombits(binary.LittleEndian.Uint64(result))
+			} else {
+				switch result[0] {
+				case 0: // 字符串，结果在随后的 STRING 记录中
+					pendingRow, pendingCol, pendingXF = row, col, xf
+					continue
+				case 1:
+					value = result[2] != 0
+				case 2:
+					value = biffErrors[result[2]]
+				case 3:
+					value = ""
+				}
+			}
+			if err := x.setCell(sheet, row, col, xf, value); err != nil {
+				return nil, err
+			}
+		case biffString:
+			if pendingXF >= 0 {
+				if err := x.setCell(sheet, pendingRow, pendingCol, pendingXF, r.str(2)); err != nil {
+					return nil, err
+				}
+				pendingXF = -1
+			}
+		case biffBlank:
+			row, col, xf := r.cell()
+			if err := x.setCell(sheet, row, col, xf, nil); err != nil {
+				return nil, err
+			}
+		case biffMulBlank:
+			row, col := int(r.u16()), int(r.u16())
+			for n := (len(rec.data()) - 6) / 2; n > 0 && r.err == nil; n-- {
+				if err := x.setCell(sheet, row, col, int(r.u16()), nil); err != nil {
+					return nil, err
+				}
+				col++
+			}
+		case biffRow:
+			row := int(r.u16())
+			r.skip(4)
+			height := float64(r.u16()&0x7FFF) / 20
+			r.skip(4)
+			flags := r.u32()
+			sheet.rows[row+1] = excelize.RowOpts{Height: height, Hidden: flags&0x20 != 0}
+		case biffColInfo:
+			first, last := int(r.u16()), int(r.u16())
+			width := float64(r.u16()) / 256
+			r.skip(2)
+			hidden := r.u16()&0x01 != 0
+			sheet.cols = append(sheet.cols, convertedCols{
+				first:  first + 1,
+				last:   min(last, excelize.MaxColumns-1) + 1,
+				width:  width,
+				hidden: hidden,
+			})
+		case biffDefColWidth:
+			defaultColChars = float64(r.u16())
+		case biffStandardWidth:
+			sheet.defaultColWidth = float64(r.u16()) / 256
+		case biffDefaultRowHeight:
+			r.skip(2)
+			sheet.defaultRowHeight = float64(r.u16()) / 20
+		case biffMergeCells:
+			for n := int(r.u16()); n > 0 && r.err == nil; n-- {
+				row1, row2, col1, col2 := int(r.u16()), int(r.u16()), int(r.u16()), int(r.u16())
+				sheet.addMerge(row1+1, col1+1, row2+1, col2+1)
+			}
+		case biffSheetExt:
+			r.skip(16)
+			if icv := int(r.u32() & 0x7F); r.err == nil && icv < len(x.palette) {
+				sheet.tabColor = x.palette[icv]
+			}
+			r.err = nil // 旧版本的 SHEETEXT 没有颜色字段
+		}
+		if r.err != nil {
+			return nil, fmt.Errorf("记录 0x%04X: %w", rec.typ, r.err)
+		}
+	}
+	if sheet.defaultColWidth == 0 && defaultColChars > 0 {
+		// DEFCOLWIDTH 不含边距，按 Excel 的方式加上 5 像素
+		sheet.defaultColWidth = colWidthFromPixels(defaultColChars*x.mdw+5, x.mdw)
+	}
+	return sheet, nil
+}
+
+// setCell 设置单元格的值与 XF 对应的样式（行列从 0 开始）
+func (x *xlsReader) setCell(sheet *convertedSheet, row, col, xf int, value interface{}) error {
+	id, err := x.styleID(xf)
+	if err != nil {
+		return err
+	}
+	return sheet.setCell(row+1, col+1, excelize.Cell{StyleID: id, Value: value})
+}
+
+// styleID 返回 XF 对应的 excelize 样式 ID
+func (x *xlsReader) styleID(xf int) (int, error) {
+	if id, ok := x.styleIDs[xf]; ok {
+		return id, nil
+	}
+	if xf < 0 || xf >= len(x.xfs) {
+		return 0, nil
+	}
+	id, err := x.file.NewStyle(x.excelStyle(x.xfs[xf]))
+	if err != nil {
+		return 0, err
+	}
+	x.styleIDs[xf] = id
+	return id, nil
+}
+
+// excelStyle 将 XF 记录转换为 excelize 样式
+func (x *xlsReader) excelStyle(xf []byte) *excelize.Style {
+	style := &excelize.Style{}
+	if len(xf) < 20 {
+		return style
+	}
+	le := binary.LittleEndian
+
+	// 字体索引 4 不存在，之后的索引需减 1
+	if ifnt := int(le.Uint16(xf)); ifnt != 4 {
+		if ifnt > 4 {
+			ifnt--
+		}
+		if ifnt < len(x.fonts) {
+			font := x.fonts[ifnt]
+			style.Font = &excelize.Font{
+				Family:    font.name,
+				Size:      font.size,
+				Bold:      font.bold,
+				Italic:    font.italic,
+				Strike:    font.strike,
+				Underline: font.underline,
+				VertAlign: font.vertAlign,
+				Color:     x.color(font.color),
+			}
+		}
+	}
+
+	ifmt := le.Uint16(xf[2:])
+	if code, ok := x.formats[ifmt]; ok && ifmt != 0 {
+		style.CustomNumFmt = &code
+	} else {
+		style.NumFmt = int(ifmt)
+	}
+
+	align := &excelize.Alignment{
+		WrapText:     xf[6]&0x08 != 0,
+		TextRotation: int(xf[7]),
+		Indent:       int(xf[8] & 0x0F),
+		ShrinkToFit:  xf[8]&0x10 != 0,
+	}
+	if h := int(xf[6] & 0x07); h < len(biffHorizontalAlign) {
+		align.Horizontal = biffHorizontalAlign[h]
+	}
+	if v := int(xf[6]>>4) & 0x07; v < len(biffVerticalAlign) {
+		align.Vertical = biffVerticalAlign[v]
+	}
+	if *align != (excelize.Alignment{}) {
+		style.Alignment = align
+	}
+
+	b1, b2 := le.Uint32(xf[10:]), le.Uint32(xf[14:])
+	for _, b := range []struct {
+		typ        string
+		style, icv uint32
+	}{
+		{"left", b1 & 0x0F, b1 >> 16 & 0x7F},
+		{"right", b1 >> 4 & 0x0F, b1 >> 23 & 0x7F},
+		{"top", b1 >> 8 & 0x0F, b2 & 0x7F},
+		{"bottom", b1 >> 12 & 0x0F, b2 >> 7 & 0x7F},
+	} {
+		if b.style != 0 {
+			style.Border = append(style.Border, excelize.Border{Type: b.typ, Style: int(b.style), Color: x.color(uint16(b.icv))})
+		}
+	}
+	if diag := b1 >> 30; diag != 0 {
+		if s := int(b2 >> 21 & 0x0F); s != 0 {
+			color := x.color(uint16(b2 >> 14 & 0x7F))
+			if diag&0x01 != 0 {
+				style.Border = append(style.Border, excelize.Border{Type: "diagonalDown", Style: s, Color: color})
+			}
+			if diag&0x02 != 0 {
+				style.Border = append(style.Border, excelize.Border{Type: "diagonalUp", Style: s, Color: color})
+			}
+		}
+	}
+
+	// 填充：图案编号与 Excel 相同，前景色为纯色填充的颜色
+	if pattern := int(b2 >> 26); pattern != 0 {
+		if fore := x.color(le.Uint16(xf[18:]) & 0x7F); fore != "" {
+			style.Fill = excelize.Fill{Type: "pattern", Pattern: pattern, Color: []string{fore}}
+		}
+	}
+	return style
+}
+
+// color 将调色板索引转换为 RRGGBB，自动颜色返回空
+func (x *xlsReader) color(icv uint16) string {
+	if icv == biffAutoColor || int(icv) >= len(x.palette) {
+		return ""
+	}
+	return x.palette[icv]
+}
+
+// biffRKValue 解码 RK 数值：30 位整数或 IEEE 754 双精度的高 30 位，可能需要除以 100
+func biffRKValue(rk uint32) float64 {
+	var v float64
+	if rk&0x02 != 0 {
+		v = float64(int32(rk) >> 2)
+	} else {
+		v = math.Float64frombits(uint64(rk&0xFFFFFFFC) << 32)
+	}
+	if rk&0x01 != 0 {
+		v /= 100
+	}
+	return v
+}
+
+// biffReader 按顺序读取记录内容，数据可跨越 CONTINUE 记录；出错后后续读取均返回零值
+type biffReader struct {
+	chunks [][]byte
+	chunk  int
+	pos    int
+	err    error
+}
+
+// bytes 读取 n 个字节
+func (r *biffReader) bytes(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	var out []byte
+	for n > 0 {
+		if r.chunk >= len(r.chunks) {
+			r.err = errBIFFCorrupt
+			return nil
+		}
+		c := r.chunks[r.chunk]
+		if r.pos >= len(c) {
+			r.chunk++
+			r.pos = 0
+			continue
+		}
+		k := min(n, len(c)-r.pos)
+		if out == nil && k == n {
+			out = c[r.pos : r.pos+k]
+		} else {
+			out = append(out, c[r.pos:r.pos+k]...)
+		}
+		r.pos += k
+		n -= k
+	}
+	return out
+}
+
+func (r *biffReader) skip(n int) {
+	r.bytes(n)
+}
+
+func (r *biffReader) u8() byte {
+	if b := r.bytes(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *biffReader) u16() uint16 {
+	if b := r.bytes(2); b != nil {
+		return binary.LittleEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (r *biffReader) u32() uint32 {
+	if b := r.bytes(4); b != nil {
+		return binary.LittleEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (r *biffReader) f64() float64 {
+	if b := r.bytes(8); b != nil {
+		return math.Float64frombits(binary.LittleEndian.Uint64(b))
+	}
+	return 0
+}
+
+// cell 读取单元格记录开头的行、列与 XF 索引
+func (r *biffReader) cell() (row, col, xf int) {
+	return int(r.u16()), int(r.u16()), int(r.u16())
+}
+
+// str 读取 Unicode 字符串，cchSize 为长度字段的字节数（1 或 2）；
+// 富文本格式与拼音信息被跳过
+func (r *biffReader) str(cchSize int) string {
+	var cch int
+	if cchSize == 1 {
+		cch = int(r.u8())
+	} else {
+		cch = int(r.u16())
+	}
+	flags := r.u8()
+	var runs, ext int
+	if flags&0x08 != 0 {
+		runs = int(r.u16())
+	}
+	if flags&0x04 != 0 {
+		ext = int(r.u32())
+	}
+	s := r.chars(cch, flags&0x01 != 0)
+	r.skip(4*runs + ext)
+	return s
+}
+
+// chars 读取 n 个字符；字符跨越 CONTINUE 记录时，新记录以一个标志字节开头，重新指定字符宽度
+func (r *biffReader) chars(n int, wide bool) string {
+	u := make([]uint16, 0, min(n, 1<<12))
+	for len(u) < n && r.err == nil {
+		if r.chunk < len(r.chunks) && r.pos >= len(r.chunks[r.chunk]) {
+			r.chunk++
+			r.pos = 0
+			wide = r.u8()&0x01 != 0
+			continue
+		}
+		if wide {
+			u = append(u, r.u16())
+		} else {
+			u = append(u, uint16(r.u8()))
+		}
+	}
+	return string(utf16.Decode(u))
+}
@@ -0,0 +1,335 @@
+package excelsnapshot
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+	"unicode/utf16"
+
+	"go.uber.org/zap/zaptest"
+)
+
+// testBIFF 按顺序写入 BIFF8 记录
+type testBIFF struct {
+	bytes.Buffer
+}
+
+// record 写入一条记录，fields 为 uint8/uint16/uint32/float64/[]byte
+func (b *testBIFF) record(typ uint16, fields ...interface{}) {
+	data := biffFields(fields...)
+	binary.Write(&b.Buffer, binary.LittleEndian, typ)
+	binary.Write(&b.Buffer, binary.LittleEndian, uint16(len(data)))
+	b.Write(data)
+}
+
+// biffFields 按小端序拼接字段
+func biffFields(fields ...interface{}) []byte {
+	var buf bytes.Buffer
+	for _, f := range fields {
+		if data, ok := f.([]byte); ok {
+			buf.Write(data)
+			continue
+		}
+		binary.Write(&buf, binary.LittleEndian, f)
+	}
+	return buf.Bytes()
+}
+
+// biffText 编码 Unicode 字符串（cchSize 为长度字段的字节数）；非 ASCII 字符串使用 UTF-16
+func biffText(s string, cchSize int) []byte {
+	u := utf16.Encode([]rune(s))
+	var buf bytes.Buffer
+	if cchSize == 1 {
+		buf.WriteByte(byte(len(u)))
+	} else {
+		binary.Write(&buf, binary.LittleEndian, uint16(len(u)))
+	}
+	wide := false
+	for _, c := range u {
+		wide = wide || c > 0xFF
+	}
+	if !wide {
+		buf.WriteByte(0)
+		for _, c := range u {
+			buf.WriteByte(byte(c))
+		}
+		return buf.Bytes()
+	}
+	buf.WriteByte(1)
+	binary.Write(&buf, binary.LittleEndian, u)
+	return buf.Bytes()
+}
+
+// bof 写入 BIFF8 的 BOF 记录
+func (b *testBIFF) bof(dt uint16) {
+	b.record(biffBOF, uint16(biff8Version), dt, uint16(0x0DBB), uint16(1996), uint32(0), uint32(6))
+}
+
+// biffXFRecord 构建 XF 记录的内容
+func biffXFRecord(font, format uint16, align, rotation, indent byte, border1, border2 uint32, colors uint16) []byte {
+	return biffFields(font, format, uint16(0x0001), align, rotation, indent, byte(0), border1, border2, colors)
+}
+
+// biffRKNumber 将整数编码为 RK 值；centi 为 true 时表示值的 1/100
+func biffRKNumber(v int32, centi bool) uint32 {
+	rk := uint32(v)<<2 | 0x02
+	if centi {
+		rk |= 0x01
+	}
+	return rk
+}
+
+// createTestXLS 构建测试用的 .xls：Data 工作表包含各类单元格、样式与尺寸，Hidden 工作表被隐藏
+func createTestXLS(t *testing.T) []byte {
+	t.Helper()
+	sheets := []struct {
+		name   string
+		hidden byte
+		write  func(b *testBIFF)
+	}{
+		{"Data", 0, writeTestXLSData},
+		{"Hidden", 1, func(b *testBIFF) {
+			b.record(biffLabelSST, uint16(0), uint16(0), uint16(0), uint32(1))
+		}},
+	}
+
+	globals := func(offsets []uint32) []byte {
+		var b testBIFF
+		b.bof(0x0005)
+		b.record(biffDate1904, uint16(0))
+		for i := 0; i < 4; i++ {
+			b.record(biffFont, uint16(200), uint16(0), uint16(biffAutoColor), uint16(400), uint16(0), byte(0), byte(0), byte(0), byte(0), biffText("Arial", 1))
+		}
+		// 字体索引 5：14pt 粗斜体、单下划线、红色
+		b.record(biffFont, uint16(280), uint16(0x02), uint16(10), uint16(700), uint16(0), byte(1), byte(0), byte(0), byte(0), biffText("Arial", 1))
+		b.record(biffFormat, uint16(164), biffText("0.00%", 2))
+		// XF 0 默认；XF 1 居中换行、左边框蓝色细线、下边框双线、黄色填充；XF 2 日期；XF 3 百分比
+		b.record(biffXF, biffXFRecord(0, 0, 0x20, 0, 0, 0, 0, 0x2040))
+		b.record(biffXF, biffXFRecord(5, 0, 0x12|0x08, 0, 0, 0x1|0x6<<12|12<<16, 1<<26, 13|0x41<<7))
+		b.record(biffXF, biffXFRecord(0, 14, 0x20, 0, 0, 0, 0, 0))
+		b.record(biffXF, biffXFRecord(0, 164, 0x23, 0, 0, 0, 0, 0))
+		b.record(biffPalette, uint16(1), []byte{0x12, 0x34, 0x56, 0x00})
+		for i, s := range sheets {
+			b.record(biffBoundSheet, offsets[i], s.hidden, byte(biffSheetWork), biffText(s.name, 1))
+		}
+
+		// SST：第三个字符串跨越 CONTINUE，续接部分改为 UTF-16
+		sst := biffFields(uint32(4), uint32(3), biffText("Header", 2), biffText("中文", 2), uint16(11), byte(0), []byte("Hello"))
+		b.record(biffSST, sst)
+		b.record(biffContinue, byte(1), utf16.Encode([]rune(" World")))
+		b.record(biffEOF)
+		return b.Bytes()
+	}
+
+	offsets := make([]uint32, len(sheets))
+	stream := globals(offsets)
+	var body testBIFF
+	for i, s := range sheets {
+		offsets[i] = uint32(len(stream) + body.Len())
+		body.bof(biffWorksheet)
+		s.write(&body)
+		body.record(biffEOF)
+	}
+	stream = append(globals(offsets), body.Bytes()...)
+	return createTestCFB(t, "Workbook", stream)
+}
+
+// writeTestXLSData 写入 Data 工作表的记录
+func writeTestXLSData(b *testBIFF) {
+	b.record(biffDefColWidth, uint16(10))
+	b.record(biffDefaultRowHeight, uint16(0), uint16(300))
+	b.record(biffColInfo, uint16(1), uint16(1), uint16(20*256), uint16(0), uint16(0), uint16(0))
+	b.record(biffColInfo, uint16(3), uint16(3), uint16(12*256), uint16(0), uint16(1), uint16(0))
+	b.record(biffRow, uint16(0), uint16(0), uint16(2), uint16(600), uint16(0), uint16(0), uint32(0x40))
+	b.record(biffRow, uint16(2), uint16(0), uint16(2), uint16(300), uint16(0), uint16(0), uint32(0x20))
+
+	b.record(biffLabelSST, uint16(0), uint16(0), uint16(1), uint32(0))
+	b.record(biffRK, uint16(1), uint16(0), uint16(0), biffRKNumber(42, false))
+	b.record(biffRK, uint16(1), uint16(1), uint16(3), biffRKNumber(1234, true))
+	// 3.5 的双精度高 30 位
+	b.record(biffMulRK, uint16(2), uint16(0), uint16(0), biffRKNumber(7, false), uint16(0), uint32(math.Float64bits(3.5)>>32), uint16(1))
+	b.record(biffNumber, uint16(3), uint16(0), uint16(2), float64(45292))
+	b.record(biffBoolErr, uint16(3), uint16(1), uint16(0), byte(1), byte(0))
+	b.record(biffBoolErr, uint16(3), uint16(2), uint16(0), byte(0x07), byte(1))
+	b.record(biffFormula, uint16(4), uint16(0), uint16(0), float64(84), uint16(0), uint32(0), uint16(0))
+	b.record(biffFormula, uint16(4), uint16(1), uint16(0), []byte{0, 0, 0, 0, 0, 0, 0xFF, 0xFF}, uint16(0), uint32(0), uint16(0))
+	b.record(biffString, biffText("abc", 2))
+	b.record(biffFormula, uint16(4), uint16(2), uint16(0), []byte{1, 0, 1, 0, 0, 0, 0xFF, 0xFF}, uint16(0), uint32(0), uint16(0))
+	b.record(biffLabelSST, uint16(5), uint16(0), uint16(0), uint32(2))
+	b.record(biffLabel, uint16(5), uint16(1), uint16(0), biffText("标签", 2))
+	b.record(biffMulBlank, uint16(6), uint16(0), uint16(1), uint16(1), uint16(1), uint16(2))
+	b.record(biffMergeCells, uint16(1), uint16(0), uint16(0), uint16(0), uint16(1))
+	b.record(biffSheetExt, uint16(biffSheetExt), uint16(0), uint64(0), uint32(0x28), uint32(8))
+}
+
+// TestNewExcelFromXLS 测试 xls 的值、样式、合并单元格与行列尺寸转换
+func TestNewExcelFromXLS(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	excel, err := NewExcelFromXLS(bytes.NewReader(createTestXLS(t)), logger)
+	if err != nil {
+		t.Fatalf("NewExcelFromXLS() 失败: %v", err)
+	}
+	defer excel.Close()
+
+	if got := excel.GetSheetNameByIndex(1); got != "Hidden" {
+		t.Errorf("第二个工作表 = %q, want Hidden", got)
+	}
+	if visible, _ := excel.file.GetSheetVisible("Hidden"); visible {
+		t.Error("Hidden 应该被隐藏")
+	}
+	if props, err := excel.file.GetSheetProps("Data"); err != nil || props.TabColorRGB == nil || *props.TabColorRGB != "123456" {
+		t.Errorf("标签颜色 = %+v, %v, want 调色板中修改的 123456", props.TabColorRGB, err)
+	}
+
+	sheet, err := excel.GetSheet("Data")
+	if err != nil {
+		t.Fatalf("获取工作表失败: %v", err)
+	}
+	tests := []struct {
+		addr string
+		want string
+	}{
+		{"A1", "Header"},
+		{"A2", "42"},
+		{"B2", "1234.00%"},
+		{"A3", "7"},
+		{"B3", "3.5"},
+		{"A4", "01-01-24"},
+		{"B4", "TRUE"},
+		{"C4", "#DIV/0!"},
+		{"A5", "84"},
+		{"B5", "abc"},
+		{"C5", "TRUE"},
+		{"A6", "Hello World"},
+		{"B6", "标签"},
+	}
+	for _, tt := range tests {
+		if cell := sheet.cells.get(tt.addr); cell == nil || cell.Value != tt.want {
+			t.Errorf("%s = %+v, want %q", tt.addr, cell, tt.want)
+		}
+	}
+	if cell := sheet.cells.get("A1"); cell == nil || !cell.IsMerged || len(cell.MergedRange) != 2 || cell.MergedRange[1] != "B1" {
+		t.Errorf("A1 应为 A1:B1 的合并单元格: %+v", cell)
+	}
+
+	style, err := sheet.cells.get("A1").RenderStyle()
+	if err != nil {
+		t.Fatalf("获取样式失败: %v", err)
+	}
+	if !style.Font.Bold || !style.Font.Italic || style.Font.Size != 14 || style.Font.Color != "FF0000" || style.Font.Underline != "single" {
+		t.Errorf("A1 字体 = %+v, want 14pt 红色粗斜体单下划线", style.Font)
+	}
+	if len(style.Fill.Color) == 0 || style.Fill.Color[0] != "FFFF00" {
+		t.Errorf("A1 填充 = %+v, want FFFF00", style.Fill)
+	}
+	if len(style.Border) != 2 || style.Border[0].Type != "left" || style.Border[0].Color != "0000FF" ||
+		style.Border[1].Type != "bottom" || style.Border[1].Style != 6 {
+		t.Errorf("A1 边框 = %+v, want 蓝色左边框与双线下边框", style.Border)
+	}
+	if style.Alignment == nil || style.Alignment.Horizontal != "center" || style.Alignment.Vertical != "center" || !style.Alignment.WrapText {
+		t.Errorf("A1 对齐 = %+v", style.Alignment)
+	}
+	if style, _ := sheet.cells.get("C7").RenderStyle(); style == nil || len(style.Fill.Color) == 0 {
+		t.Errorf("C7 应为带填充的空白单元格: %+v", style)
+	}
+
+	if w, _ := excel.file.GetColWidth("Data", "B"); w != 20 {
+		t.Errorf("B 列宽 = %v, want 20", w)
+	}
+	if visible, _ := excel.file.GetColVisible("Data", "D"); visible {
+		t.Error("D 列应该被隐藏")
+	}
+	if w, _ := excel.file.GetColWidth("Data", "A"); w != colWidthFromPixels(10*excel.MaxDigitWidth()+5, excel.MaxDigitWidth()) {
+		t.Errorf("默认列宽 = %v", w)
+	}
+	if h := sheet.GetRowHeight(1); h != 30 {
+		t.Errorf("第 1 行行高 = %v, want 30", h)
+	}
+	if visible, _ := excel.file.GetRowVisible("Data", 3); visible {
+		t.Error("第 3 行应该被隐藏")
+	}
+
+	hidden, err := excel.GetSheet("Hidden")
+	if err != nil {
+		t.Fatalf("获取工作表失败: %v", err)
+	}
+	if cell := hidden.cells.get("A1"); cell == nil || cell.Value != "中文" {
+		t.Errorf("Hidden!A1 = %+v, want 中文", cell)
+	}
+
+	if _, err := NewSheetRenderer(logger).RenderSheet(sheet); err != nil {
+		t.Errorf("渲染失败: %v", err)
+	}
+}
+
+// TestNewExcelFromXLS_Invalid 测试无效或不支持的 xls 内容
+func TestNewExcelFromXLS_Invalid(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	var encrypted testBIFF
+	encrypted.bof(0x0005)
+	encrypted.record(biffFilePass, uint16(1))
+	encrypted.record(biffEOF)
+	var biff5 testBIFF
+	biff5.record(biffBOF, uint16(0x0500), uint16(0x0005))
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"不是复合文档", []byte("plain text")},
+		{"没有 Workbook 流", createTestCFB(t, "Data", []byte("x"))},
+		{"BIFF5 的 Book 流", createTestCFB(t, "Book", biff5.Bytes())},
+		{"BIFF5 版本号", createTestCFB(t, "Workbook", biff5.Bytes())},
+		{"加密", createTestCFB(t, "Workbook", encrypted.Bytes())},
+		{"记录被截断", createTestCFB(t, "Workbook", []byte{0x09, 0x08, 0x10, 0x00, 0x00})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewExcelFromXLS(bytes.NewReader(tt.data), logger); err == nil {
+				t.Error("应该返回错误")
+			}
+		})
+	}
+}
+
+// TestBIFFReader 测试跨 CONTINUE 记录读取字符串
+func TestBIFFReader(t *testing.T) {
+	tests := []struct {
+		name   string
+		chunks [][]byte
+		want   string
+	}{
+		{"单个记录", [][]byte{biffText("abc", 2)}, "abc"},
+		{"UTF-16", [][]byte{biffText("中文", 2)}, "中文"},
+		{"跨记录改变字符宽度", [][]byte{{5, 0, 0, 'a', 'b'}, {1, 0x2D, 0x4E, 'c', 0, 'd', 0}}, "ab中cd"},
+		{"富文本", [][]byte{biffFields(uint16(2), byte(0x08), uint16(1), []byte("xy"), uint32(0))}, "xy"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &biffReader{chunks: tt.chunks}
+			if got := r.str(2); got != tt.want || r.err != nil {
+				t.Errorf("str() = %q, %v, want %q", got, r.err, tt.want)
+			}
+		})
+	}
+}
+
+// TestBIFFRKValue 测试 RK 数值解码
+func TestBIFFRKValue(t *testing.T) {
+	tests := []struct {
+		rk   uint32
+		want float64
+	}{
+		{biffRKNumber(42, false), 42},
+		{biffRKNumber(-5, false), -5},
+		{biffRKNumber(1234, true), 12.34},
+		{uint32(math.Float64bits(3.5) >> 32), 3.5},
+		{uint32(math.Float64bits(12.5)>>32) | 0x01, 0.125},
+	}
+	for _, tt := range tests {
+		if got := biffRKValue(tt.rk); got != tt.want {
+			t.Errorf("biffRKValue(0x%08X) = %v, want %v", tt.rk, got, tt.want)
+		}
+	}
+}